@@ -0,0 +1,57 @@
+// Command reaper runs the reaper of a knuu scope inside the cluster.
+// It is started by knuu as the timeout handler of the scope and is configured through environment variables.
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+	"github.com/celestiaorg/knuu/pkg/log"
+	"github.com/celestiaorg/knuu/pkg/reaper"
+)
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	logger := log.DefaultLogger()
+	if err := run(ctx, logger); err != nil {
+		logger.WithError(err).Fatal("reaper failed")
+	}
+}
+
+func run(ctx context.Context, logger *logrus.Logger) error {
+	scope := os.Getenv(reaper.EnvScope)
+	if scope == "" {
+		return reaper.ErrEnvironmentVariableNotSet.WithParams(reaper.EnvScope)
+	}
+
+	interval := reaper.DefaultInterval
+	if value := os.Getenv(reaper.EnvInterval); value != "" {
+		var err error
+		interval, err = time.ParseDuration(value)
+		if err != nil {
+			return reaper.ErrInvalidInterval.WithParams(value).Wrap(err)
+		}
+	}
+
+	// the namespace of a scope is named after the scope
+	k8sClient, err := k8s.NewClientForExistingNamespace(scope, logger)
+	if err != nil {
+		return err
+	}
+
+	r := &reaper.Reaper{
+		K8sClient: k8sClient,
+		Scope:     scope,
+		Interval:  interval,
+		Logger:    logger,
+	}
+	return r.Run(ctx)
+}
@@ -22,6 +22,10 @@ const (
 	kanikoContainerName = "kaniko-container"
 	kanikoJobNamePrefix = "kaniko-build-job"
 
+	labelManagedBy      = "k8s.kubernetes.io/managed-by"
+	labelManagedByValue = "knuu"
+	labelScope          = "knuu.sh/scope"
+
 	DefaultParallelism  = int32(1)
 	DefaultBackoffLimit = int32(5)
 
@@ -147,14 +151,23 @@ func (k *Kaniko) prepareJob(ctx context.Context, b *builder.BuilderOptions) (*ba
 		parallelism  = DefaultParallelism
 		backoffLimit = DefaultBackoffLimit
 	)
+	// the job is labeled with the scope, so that it is removed with the scope if it is left behind
+	labels := map[string]string{
+		labelManagedBy: labelManagedByValue,
+		labelScope:     k.Scope,
+	}
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: jobName,
+			Name:   jobName,
+			Labels: labels,
 		},
 		Spec: batchv1.JobSpec{
 			Parallelism:  &parallelism,  // Set parallelism to 1 to ensure only one Pod
 			BackoffLimit: &backoffLimit, // Retry the Job at most 5 times
 			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
 				Spec: v1.PodSpec{
 					Containers: []v1.Container{
 						{
@@ -0,0 +1,517 @@
+package instance
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	appv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+	"github.com/celestiaorg/knuu/pkg/system"
+)
+
+// These suffixes must match the ones used by the k8s package to build the pod spec
+const (
+	podFilesConfigmapNameSuffix = "-config"
+	remoteFilesNameSuffix       = "-files"
+	liveFilesVolumeInfix        = "-live-"
+	secretsNameSuffix           = "-secrets"
+	initContainerNameSuffix     = "-init"
+)
+
+type attacher struct {
+	sysDeps   *system.SystemDependencies
+	instances map[string]*Instance
+	// order keeps the non-sidecar instances in the order they were found
+	order []*Instance
+}
+
+// Attach rebuilds the instances of the scope set in the system dependencies
+// from the resources that are deployed in the cluster.
+// Instances with a ReplicaSet, a StatefulSet or a DaemonSet are attached in the state 'Started',
+// the ones that only have their service, files or volume left are attached in the state 'Stopped'.
+// As the pod spec of a stopped instance is gone, it can be destroyed but not started again.
+// The timeout handler is not attached.
+func Attach(ctx context.Context, sysDeps *system.SystemDependencies) ([]*Instance, error) {
+	a := &attacher{
+		sysDeps:   sysDeps,
+		instances: make(map[string]*Instance),
+	}
+	selector := map[string]string{
+		labelManagedByKey: labelKnuuValue,
+		labelScopeKey:     sysDeps.Scope,
+	}
+
+	rsList, err := sysDeps.K8sClient.ListReplicaSets(ctx, selector)
+	if err != nil {
+		return nil, ErrListingResourcesForAttach.WithParams(sysDeps.Scope).Wrap(err)
+	}
+	for n := range rsList {
+		if err := a.attachReplicaSet(ctx, &rsList[n]); err != nil {
+			return nil, err
+		}
+	}
+
+	ssList, err := sysDeps.K8sClient.ListStatefulSets(ctx, selector)
+	if err != nil {
+		return nil, ErrListingResourcesForAttach.WithParams(sysDeps.Scope).Wrap(err)
+	}
+	for n := range ssList {
+		if err := a.attachStatefulSet(ctx, &ssList[n]); err != nil {
+			return nil, err
+		}
+	}
+
+	dsList, err := sysDeps.K8sClient.ListDaemonSets(ctx, selector)
+	if err != nil {
+		return nil, ErrListingResourcesForAttach.WithParams(sysDeps.Scope).Wrap(err)
+	}
+	for n := range dsList {
+		if err := a.attachDaemonSet(ctx, &dsList[n]); err != nil {
+			return nil, err
+		}
+	}
+
+	services, err := sysDeps.K8sClient.ListServices(ctx, selector)
+	if err != nil {
+		return nil, ErrListingResourcesForAttach.WithParams(sysDeps.Scope).Wrap(err)
+	}
+	for n := range services {
+		i, err := a.instanceFor(services[n].Labels)
+		if err != nil {
+			return nil, err
+		}
+		if i == nil {
+			continue
+		}
+		// the headless service only exists for the instances that run as a StatefulSet
+		if services[n].Name == i.headlessServiceName() {
+			i.execution.workloadKind = WorkloadStatefulSet
+			continue
+		}
+		i.network.attachService(&services[n])
+	}
+
+	configMaps, err := sysDeps.K8sClient.ListConfigMaps(ctx, selector)
+	if err != nil {
+		return nil, ErrListingResourcesForAttach.WithParams(sysDeps.Scope).Wrap(err)
+	}
+	for n := range configMaps {
+		i, err := a.instanceFor(configMaps[n].Labels)
+		if err != nil {
+			return nil, err
+		}
+		if i != nil {
+			i.storage.attachConfigMap(&configMaps[n])
+		}
+	}
+
+	pvcs, err := sysDeps.K8sClient.ListPersistentVolumeClaims(ctx, selector)
+	if err != nil {
+		return nil, ErrListingResourcesForAttach.WithParams(sysDeps.Scope).Wrap(err)
+	}
+	for n := range pvcs {
+		i, err := a.instanceFor(pvcs[n].Labels)
+		if err != nil {
+			return nil, err
+		}
+		if i == nil {
+			continue
+		}
+		// only the claims deployed by the instance are labeled with their volume,
+		// the claims of the replicas of a StatefulSet are named after their template, the StatefulSet and the replica
+		if _, ok := pvcs[n].Labels[labelVolumeKey]; !ok {
+			i.execution.workloadKind = WorkloadStatefulSet
+		}
+		i.storage.attachPersistentVolumeClaim(&pvcs[n])
+	}
+
+	return a.order, nil
+}
+
+// attachReplicaSet creates a started instance, and its sidecars, from the given ReplicaSet
+func (a *attacher) attachReplicaSet(ctx context.Context, rs *appv1.ReplicaSet) error {
+	i, err := a.attachWorkload(ctx, rs.Labels, rs.Spec.Replicas, rs.Spec.Template.Spec)
+	if err != nil || i == nil {
+		return err
+	}
+	i.kubernetesReplicaSet = rs
+	return nil
+}
+
+// attachStatefulSet creates a started instance, and its sidecars, from the given StatefulSet
+func (a *attacher) attachStatefulSet(ctx context.Context, ss *appv1.StatefulSet) error {
+	i, err := a.attachWorkload(ctx, ss.Labels, ss.Spec.Replicas, ss.Spec.Template.Spec)
+	if err != nil || i == nil {
+		return err
+	}
+	i.kubernetesStatefulSet = ss
+	i.execution.workloadKind = WorkloadStatefulSet
+	return nil
+}
+
+// attachDaemonSet creates a started instance, and its sidecars, from the given DaemonSet
+func (a *attacher) attachDaemonSet(ctx context.Context, ds *appv1.DaemonSet) error {
+	i, err := a.attachWorkload(ctx, ds.Labels, nil, ds.Spec.Template.Spec)
+	if err != nil || i == nil {
+		return err
+	}
+	i.kubernetesDaemonSet = ds
+	i.execution.workloadKind = WorkloadDaemonSet
+	return nil
+}
+
+// attachWorkload creates a started instance, and its sidecars, from the pod spec of its workload.
+// It returns nil if the workload is not one of an instance.
+func (a *attacher) attachWorkload(ctx context.Context, labels map[string]string, replicas *int32, podSpec v1.PodSpec) (*Instance, error) {
+	if isIgnoredForAttach(labels) || len(podSpec.Containers) == 0 {
+		return nil, nil
+	}
+
+	i, err := a.newInstance(labels[labelNameKey], parseInstanceType(labels[labelTypeKey]), StateStarted)
+	if err != nil {
+		return nil, err
+	}
+	i.execution.attachLabels(labels)
+	if replicas != nil {
+		i.execution.replicas = *replicas
+	}
+
+	if podSpec.SecurityContext != nil && podSpec.SecurityContext.FSGroup != nil {
+		i.storage.fsGroup = *podSpec.SecurityContext.FSGroup
+	}
+
+	var initContainer *v1.Container
+	for n := range podSpec.InitContainers {
+		if podSpec.InitContainers[n].Name == podSpec.Containers[0].Name+initContainerNameSuffix {
+			initContainer = &podSpec.InitContainers[n]
+		}
+	}
+	i.attachContainer(&podSpec.Containers[0], initContainer)
+
+	role, err := a.sysDeps.K8sClient.GetRole(ctx, i.name)
+	switch {
+	case err == nil:
+		i.security.policyRules = role.Rules
+	case !errors.Is(err, k8s.ErrRoleDoesNotExist):
+		return nil, ErrGettingRoleForAttach.WithParams(i.name).Wrap(err)
+	}
+
+	for n := range podSpec.Containers[1:] {
+		container := &podSpec.Containers[n+1]
+		sc, err := a.newInstance(container.Name, BasicInstance, StateStarted)
+		if err != nil {
+			return nil, err
+		}
+		sc.storage.fsGroup = i.storage.fsGroup
+		i.sidecars.attachSidecar(sc)
+		sc.attachContainer(container, nil)
+	}
+
+	a.order = append(a.order, i)
+	return i, nil
+}
+
+// instanceFor returns the instance that owns a resource with the given labels.
+// If the instance is not known yet, it is created in the state 'Stopped'.
+func (a *attacher) instanceFor(labels map[string]string) (*Instance, error) {
+	if isIgnoredForAttach(labels) {
+		return nil, nil
+	}
+	name := labels[labelNameKey]
+	if i, ok := a.instances[name]; ok {
+		return i, nil
+	}
+
+	i, err := a.newInstance(name, parseInstanceType(labels[labelTypeKey]), StateStopped)
+	if err != nil {
+		return nil, err
+	}
+	i.execution.attachLabels(labels)
+
+	parentName, ok := labels[labelParentKey]
+	if !ok {
+		a.order = append(a.order, i)
+		return i, nil
+	}
+
+	parent, err := a.instanceFor(map[string]string{
+		labelNameKey: parentName,
+		labelTypeKey: BasicInstance.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	parent.sidecars.attachSidecar(i)
+	return i, nil
+}
+
+func (a *attacher) newInstance(name string, instanceType InstanceType, state InstanceState) (*Instance, error) {
+	i, err := New(name, a.sysDeps)
+	if err != nil {
+		return nil, ErrAttachingInstance.WithParams(name).Wrap(err)
+	}
+	i.instanceType = instanceType
+	i.state = state
+	a.instances[i.name] = i
+
+	a.sysDeps.Logger.WithFields(logrus.Fields{
+		"instance": i.name,
+		"state":    state.String(),
+	}).Debug("attached instance")
+	return i, nil
+}
+
+func isIgnoredForAttach(labels map[string]string) bool {
+	return labels[labelNameKey] == "" ||
+		labels[labelTypeKey] == TimeoutHandlerInstance.String()
+}
+
+// attachContainer restores the configuration of the instance from its container.
+// Files below a volume are only mounted in the init container, so it is used for the files when it is given.
+func (i *Instance) attachContainer(c *v1.Container, initContainer *v1.Container) {
+	i.build.imageName = c.Image
+	i.build.imagePullPolicy = c.ImagePullPolicy
+	i.build.command = append(i.build.command, c.Command...)
+	i.build.args = append(i.build.args, c.Args...)
+	for _, env := range c.Env {
+		// the values of the secrets are unknown, but the variables are still needed to clean up the secret
+		if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil && env.ValueFrom.SecretKeyRef.Name == i.name+secretsNameSuffix {
+			i.security.secretEnv[env.Name] = ""
+			continue
+		}
+		i.build.env[env.Name] = env.Value
+	}
+
+	i.resources.memoryRequest = c.Resources.Requests[v1.ResourceMemory]
+	i.resources.memoryLimit = c.Resources.Limits[v1.ResourceMemory]
+	i.resources.cpuRequest = c.Resources.Requests[v1.ResourceCPU]
+
+	i.monitoring.livenessProbe = c.LivenessProbe
+	i.monitoring.readinessProbe = c.ReadinessProbe
+	i.monitoring.startupProbe = c.StartupProbe
+
+	if sc := c.SecurityContext; sc != nil {
+		if sc.Privileged != nil {
+			i.security.privileged = *sc.Privileged
+		}
+		if sc.Capabilities != nil {
+			for _, capability := range sc.Capabilities.Add {
+				i.security.capabilitiesAdd = append(i.security.capabilitiesAdd, string(capability))
+			}
+		}
+	}
+
+	// the volumes are mounted from pod volumes named after their container, see k8s.VolumeClaimName
+	for _, m := range c.VolumeMounts {
+		if m.Name == i.name+secretsNameSuffix {
+			i.storage.secretFiles = append(i.storage.secretFiles, &secretFile{
+				SecretFile: k8s.SecretFile{Key: m.SubPath, Dest: m.MountPath},
+			})
+			continue
+		}
+		if i.isReservedVolume(m.Name) {
+			continue
+		}
+		if name, ok := strings.CutPrefix(m.Name, i.name+"-"); ok {
+			i.storage.volumes = append(i.storage.volumes, &k8s.Volume{Name: name, Path: m.MountPath, Owner: i.storage.fsGroup})
+			continue
+		}
+		if i.parentInstance == nil {
+			continue
+		}
+		if name, ok := strings.CutPrefix(m.Name, i.parentInstance.name+"-"); ok {
+			i.storage.volumeMounts = append(i.storage.volumeMounts, &k8s.VolumeMount{Volume: name, Path: m.MountPath, ReadOnly: m.ReadOnly})
+		}
+	}
+
+	fileMounts := c.VolumeMounts
+	if initContainer != nil {
+		fileMounts = initContainer.VolumeMounts
+	}
+	files := make(map[int]*k8s.File)
+	for _, m := range fileMounts {
+		file, ok := i.fileOfVolume(m.Name)
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(m.SubPath)
+		if err != nil {
+			continue
+		}
+		file.Dest = m.MountPath
+		files[n] = file
+	}
+	i.storage.files = attachedFiles(files)
+}
+
+// fileOfVolume returns a file mounted from the pod volume with the given name, with only its shard and whether it is remote set,
+// if the pod volume holds files of the instance
+func (i *Instance) fileOfVolume(name string) (*k8s.File, bool) {
+	switch name {
+	case i.name + podFilesConfigmapNameSuffix:
+		return &k8s.File{}, true
+	case i.name + remoteFilesNameSuffix:
+		return &k8s.File{Remote: true}, true
+	}
+	// the volumes of the other shards are named after their configmap
+	if shard, ok := i.filesShard(name); ok && shard != 0 {
+		return &k8s.File{Shard: shard}, true
+	}
+	return nil, false
+}
+
+// filesShard returns the shard of the files of the instance stored in the configmap with the given name,
+// see k8s.FilesConfigMapName
+func (i *Instance) filesShard(configMapName string) (int, bool) {
+	if configMapName == i.name {
+		return 0, true
+	}
+	suffix, ok := strings.CutPrefix(configMapName, i.name+podFilesConfigmapNameSuffix+"-")
+	if !ok {
+		return 0, false
+	}
+	shard, err := strconv.Atoi(suffix)
+	return shard, err == nil
+}
+
+// isReservedVolume reports whether the pod volume with the given name holds files or secrets of the instance,
+// so it cannot be the volume of a claim
+func (i *Instance) isReservedVolume(name string) bool {
+	_, ok := i.fileOfVolume(name)
+	return ok || i.isLiveFilesVolume(name) || name == i.name+secretsNameSuffix
+}
+
+// isLiveFilesVolume reports whether the pod volume with the given name holds live files of the instance
+func (i *Instance) isLiveFilesVolume(name string) bool {
+	suffix, ok := strings.CutPrefix(name, i.name+liveFilesVolumeInfix)
+	if !ok {
+		return false
+	}
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
+
+// attachedFiles returns the files ordered by their key in the configmap.
+// The source of an attached file is unknown, only its destination and where it is stored are restored.
+func attachedFiles(byKey map[int]*k8s.File) []*k8s.File {
+	keys := make([]int, 0, len(byKey))
+	for n := range byKey {
+		keys = append(keys, n)
+	}
+	sort.Ints(keys)
+
+	files := make([]*k8s.File, 0, len(keys))
+	for _, n := range keys {
+		files = append(files, byKey[n])
+	}
+	return files
+}
+
+func (n *network) attachService(svc *v1.Service) {
+	n.kubernetesService = svc
+	if len(n.portsTCP) != 0 || len(n.portsUDP) != 0 {
+		return
+	}
+	for _, port := range svc.Spec.Ports {
+		switch port.Protocol {
+		case v1.ProtocolTCP:
+			n.portsTCP = append(n.portsTCP, int(port.Port))
+		case v1.ProtocolUDP:
+			n.portsUDP = append(n.portsUDP, int(port.Port))
+		}
+	}
+}
+
+func (s *storage) attachConfigMap(cm *v1.ConfigMap) {
+	shard, ok := s.instance.filesShard(cm.Name)
+	if !ok {
+		// e.g. the configmap of the live files
+		return
+	}
+	for _, file := range s.files {
+		if file.Dest != "" {
+			// the files are restored from the pod
+			return
+		}
+	}
+	// the destinations are unknown when the pod is gone,
+	// but the files are still needed to clean up the configmaps
+	files := make(map[int]*k8s.File, len(cm.Data))
+	for key := range cm.Data {
+		n, err := strconv.Atoi(key)
+		if err != nil {
+			continue
+		}
+		files[n] = &k8s.File{Shard: shard}
+	}
+	s.files = append(s.files, attachedFiles(files)...)
+}
+
+func (s *storage) attachPersistentVolumeClaim(pvc *v1.PersistentVolumeClaim) {
+	name, ok := pvc.Labels[labelVolumeKey]
+	if !ok {
+		// the claim of a replica is named <instance>-<volume>-<instance>-<replica>
+		name = strings.TrimPrefix(pvc.Name, s.instance.name+"-")
+		if n := strings.LastIndex(name, "-"+s.instance.name+"-"); n > 0 {
+			name = name[:n]
+		}
+	}
+
+	volume := s.volume(name)
+	if volume == nil {
+		// the path is unknown when the pod is gone,
+		// but the volume is still needed to clean up the claim
+		volume = &k8s.Volume{Name: name, Owner: s.fsGroup}
+		s.volumes = append(s.volumes, volume)
+	}
+	volume.Size = pvc.Spec.Resources.Requests[v1.ResourceStorage]
+	if pvc.Spec.StorageClassName != nil {
+		volume.StorageClass = *pvc.Spec.StorageClassName
+	}
+	if len(pvc.Spec.AccessModes) != 0 {
+		volume.AccessMode = pvc.Spec.AccessModes[0]
+	}
+}
+
+func (s *sidecars) attachSidecar(sc *Instance) {
+	sc.sidecars.isSidecar = true
+	sc.parentInstance = s.instance
+	s.sidecars = append(s.sidecars, &attachedSidecar{instance: sc})
+}
+
+// attachedSidecar is the SidecarManager of a sidecar that has been attached from the cluster
+type attachedSidecar struct {
+	instance *Instance
+}
+
+var _ SidecarManager = (*attachedSidecar)(nil)
+
+func (a *attachedSidecar) Initialize(_ context.Context, _ string, _ *system.SystemDependencies) error {
+	return ErrAttachedSidecarCannotBeInitialized.WithParams(a.instance.name)
+}
+
+func (a *attachedSidecar) Instance() *Instance {
+	return a.instance
+}
+
+func (a *attachedSidecar) PreStart(_ context.Context) error {
+	return nil
+}
+
+func (a *attachedSidecar) Clone(namePrefix string) (SidecarManager, error) {
+	suffix := a.instance.name
+	if a.instance.parentInstance != nil {
+		suffix = strings.TrimPrefix(suffix, a.instance.parentInstance.name+"-")
+	}
+	clone, err := a.instance.CloneWithName(namePrefix + "-" + suffix)
+	if err != nil {
+		return nil, err
+	}
+	return &attachedSidecar{instance: clone}, nil
+}
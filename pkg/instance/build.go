@@ -236,6 +236,15 @@ func (b *build) addFileToBuilder(src, dest, chown string) {
 	b.builderFactory.AddToBuilder(dest, dest, chown)
 }
 
+// addSymlinkToBuilder creates a symlink at dest in the image, pointing to target
+func (b *build) addSymlinkToBuilder(target, dest, chown string) {
+	b.builderFactory.AddCmdToBuilder([]string{
+		"mkdir", "-p", filepath.Dir(dest), "&&",
+		"ln", "-sfn", target, dest, "&&",
+		"chown", "-h", chown, dest,
+	})
+}
+
 // SetEnvironmentVariable sets the given environment variable in the instance
 // This function can only be called in the states 'Preparing' and 'Committed'
 func (b *build) SetEnvironmentVariable(key, value string) error {
@@ -0,0 +1,229 @@
+package instance
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+)
+
+// CopyTo copies the local file or directory to the given path in the instance, like kubectl cp.
+// The content is streamed as a tar archive to the stdin of tar in the first pod of the instance,
+// so it can be binary and does not have to fit in memory. The image of the instance needs tar.
+// The parent directories of the remote path are created, and an existing file is overwritten.
+// This function can only be called in the state 'Started'
+func (s *storage) CopyTo(ctx context.Context, localPath, remotePath string) error {
+	if !s.instance.IsInState(StateStarted) {
+		return ErrCopyingNotAllowed.WithParams(s.instance.state.String())
+	}
+	if err := s.checkSrcExists(localPath); err != nil {
+		return err
+	}
+	pod, err := s.instance.firstPod(ctx)
+	if err != nil {
+		return ErrCopyingToInstance.WithParams(localPath, remotePath, s.instance.name).Wrap(err)
+	}
+
+	cmd := tarExtractCommand(remotePath)
+	pr, pw := io.Pipe()
+	writeErr := make(chan error, 1)
+	go func() {
+		err := writeTar(pw, localPath, path.Base(remotePath))
+		pw.CloseWithError(err)
+		writeErr <- err
+	}()
+	err = s.instance.K8sClient.StreamCommandInPod(ctx, pod.Name, s.instance.name, cmd, pr, io.Discard)
+	// unblocks the archiving if the command stopped before reading the whole archive
+	pr.CloseWithError(err)
+	if wErr := <-writeErr; wErr != nil && err == nil {
+		err = wErr
+	}
+	if err != nil {
+		return ErrCopyingToInstance.WithParams(localPath, remotePath, s.instance.name).Wrap(err)
+	}
+
+	s.instance.Logger.WithFields(logrus.Fields{
+		"instance": s.instance.name,
+		"local":    localPath,
+		"remote":   remotePath,
+	}).Debug("copied to instance")
+	return nil
+}
+
+// CopyFrom copies the file or directory at the given path in the instance to the local path, like kubectl cp.
+// The content is streamed as a tar archive from the stdout of tar in the first pod of the instance,
+// so it can be binary and does not have to fit in memory. The image of the instance needs tar.
+// The parent directories of the local path are created, and an existing file is overwritten.
+// This function can only be called in the state 'Started'
+func (s *storage) CopyFrom(ctx context.Context, remotePath, localPath string) error {
+	if !s.instance.IsInState(StateStarted) {
+		return ErrCopyingNotAllowed.WithParams(s.instance.state.String())
+	}
+	pod, err := s.instance.firstPod(ctx)
+	if err != nil {
+		return ErrCopyingFromInstance.WithParams(remotePath, localPath, s.instance.name).Wrap(err)
+	}
+
+	if err := s.streamTarFrom(ctx, pod.Name, s.instance.name, remotePath, localPath); err != nil {
+		return ErrCopyingFromInstance.WithParams(remotePath, localPath, s.instance.name).Wrap(err)
+	}
+
+	s.instance.Logger.WithFields(logrus.Fields{
+		"instance": s.instance.name,
+		"remote":   remotePath,
+		"local":    localPath,
+	}).Debug("copied from instance")
+	return nil
+}
+
+// streamTarFrom extracts the file or directory at the remote path in the container of the pod to the local path
+func (s *storage) streamTarFrom(ctx context.Context, podName, containerName, remotePath, localPath string) error {
+	cmd := tarCreateCommand(remotePath)
+	pr, pw := io.Pipe()
+	streamErr := make(chan error, 1)
+	go func() {
+		err := s.instance.K8sClient.StreamCommandInPod(ctx, podName, containerName, cmd, nil, pw)
+		pw.CloseWithError(err)
+		streamErr <- err
+	}()
+
+	if err := os.MkdirAll(filepath.Dir(localPath), 0o755); err != nil {
+		pr.CloseWithError(err)
+		<-streamErr
+		return err
+	}
+	err := readTar(pr, localPath, path.Base(remotePath), s.instance.Logger)
+	// unblocks the command if the extraction stopped before reading the whole archive
+	pr.CloseWithError(err)
+	// the error of the command explains an unexpected end of the archive
+	if sErr := <-streamErr; sErr != nil {
+		return sErr
+	}
+	return err
+}
+
+// CopyFromInstance copies the file or directory at srcPath in the source instance to dstPath in the instance.
+// The content is streamed as a tar archive from tar in the source to tar in the instance,
+// without being stored by the test process, so it can be binary and does not have to fit in memory.
+// A started instance is accessed through its first pod, and its image needs tar.
+// A stopped instance is accessed through a helper pod that mounts the volume containing the path,
+// so the path has to be in a volume, where the copy is kept when the instance is started again.
+// Group.CopyFromInstance copies to several instances at once.
+// This function can only be called in the states 'Started' and 'Stopped', of both instances
+func (s *storage) CopyFromInstance(ctx context.Context, src *Instance, srcPath, dstPath string) error {
+	if src == nil {
+		return ErrCopySourceIsNil.WithParams(s.instance.name)
+	}
+	ends := []struct {
+		instance *Instance
+		path     string
+	}{{src, srcPath}, {s.instance, dstPath}}
+	for _, end := range ends {
+		if !end.instance.IsInState(StateStarted, StateStopped) {
+			return ErrCopyingBetweenInstancesNotAllowed.WithParams(end.instance.name, end.instance.state.String())
+		}
+		if end.instance.IsState(StateStopped) && end.instance.storage.volumeContaining(end.path) == nil {
+			return ErrPathNotInVolume.WithParams(end.path, end.instance.name)
+		}
+	}
+
+	// a failure closes the pipes, which makes the other steps fail too, so only the first error is kept.
+	// It is recorded before the pipes are closed.
+	var (
+		once     sync.Once
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	fail := func(err error) {
+		if err != nil {
+			once.Do(func() { firstErr = err })
+		}
+	}
+
+	// the entries are renamed on the way, as tar can not rename them when they are extracted
+	srcReader, srcWriter := io.Pipe()
+	dstReader, dstWriter := io.Pipe()
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		err := src.storage.withPathInPod(ctx, srcPath, func(podName, containerName string) error {
+			return src.K8sClient.StreamCommandInPod(ctx, podName, containerName, tarCreateCommand(srcPath), nil, srcWriter)
+		})
+		fail(err)
+		srcWriter.CloseWithError(err)
+	}()
+	go func() {
+		defer wg.Done()
+		err := renameTar(srcReader, dstWriter, path.Base(srcPath), path.Base(dstPath))
+		fail(err)
+		srcReader.CloseWithError(err)
+		dstWriter.CloseWithError(err)
+	}()
+
+	err := s.withPathInPod(ctx, dstPath, func(podName, containerName string) error {
+		return s.instance.K8sClient.StreamCommandInPod(ctx, podName, containerName, tarExtractCommand(dstPath), dstReader, io.Discard)
+	})
+	fail(err)
+	// unblocks the source if the destination stopped before reading the whole archive
+	dstReader.CloseWithError(err)
+	wg.Wait()
+	if firstErr != nil {
+		return ErrCopyingBetweenInstances.WithParams(srcPath, src.name, dstPath, s.instance.name).Wrap(firstErr)
+	}
+
+	s.instance.Logger.WithFields(logrus.Fields{
+		"instance": s.instance.name,
+		"source":   src.name,
+		"src_path": srcPath,
+		"dst_path": dstPath,
+	}).Debug("copied from instance")
+	return nil
+}
+
+// withPathInPod runs fn with the pod and the container where the path of the instance can be accessed:
+// the first pod of a started instance, or a helper pod that mounts the volume containing the path of a stopped instance
+func (s *storage) withPathInPod(ctx context.Context, p string, fn func(podName, containerName string) error) error {
+	if s.instance.IsState(StateStarted) {
+		pod, err := s.instance.firstPod(ctx)
+		if err != nil {
+			return err
+		}
+		return fn(pod.Name, s.instance.name)
+	}
+
+	volume := s.volumeContaining(p)
+	if volume == nil {
+		return ErrPathNotInVolume.WithParams(p, s.instance.name)
+	}
+	return s.withVolumeHelperPod(ctx, volume, fn)
+}
+
+// volumeContaining returns the volume that contains the given path, or nil if there is none
+func (s *storage) volumeContaining(p string) *k8s.Volume {
+	p = path.Clean(p)
+	for _, v := range s.volumes {
+		if p == v.Path || strings.HasPrefix(p, strings.TrimSuffix(v.Path, "/")+"/") {
+			return v
+		}
+	}
+	return nil
+}
+
+// tarCreateCommand returns the command that writes a tar archive of the file or directory at the path to its stdout,
+// under the base name of the path
+func tarCreateCommand(p string) []string {
+	return []string{"tar", "-cf", "-", "-C", path.Dir(p), path.Base(p)}
+}
+
+// tarExtractCommand returns the command that extracts the tar archive from its stdin to the directory of the path,
+// which is created if needed. The directory is passed as an argument of the script, so it is not parsed by the shell.
+func tarExtractCommand(p string) []string {
+	return []string{"sh", "-c", `mkdir -p -- "$1" && tar -xmf - -C "$1"`, "sh", path.Dir(p)}
+}
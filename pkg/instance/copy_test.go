@@ -0,0 +1,38 @@
+package instance
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTarExtractCommand(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("tar"); err != nil {
+		t.Skip("tar is needed to extract the archive")
+	}
+	src := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(src, "genesis.json"), []byte("{}"), 0o644))
+	var archive bytes.Buffer
+	require.NoError(t, writeTar(&archive, filepath.Join(src, "genesis.json"), "genesis.json"))
+
+	// the directory is not parsed by the shell, so it can not run commands
+	dst := t.TempDir()
+	dir := filepath.Join(dst, "my config; touch pwned", "$(touch pwned)")
+	cmdArgs := tarExtractCommand(filepath.Join(dir, "genesis.json"))
+	cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
+	cmd.Dir = dst
+	cmd.Stdin = &archive
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	content, err := os.ReadFile(filepath.Join(dir, "genesis.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(content))
+	assert.NoFileExists(t, filepath.Join(dst, "pwned"))
+}
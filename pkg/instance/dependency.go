@@ -0,0 +1,357 @@
+package instance
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// conditionPollInterval is the time between two checks of the condition of a dependency
+const conditionPollInterval = time.Second
+
+// Condition is a condition on an instance that the instances depending on it wait for before they are started
+type Condition interface {
+	// Met reports whether the condition holds for the instance.
+	// An error is not fatal, the condition is checked again until the context is done.
+	Met(ctx context.Context, i *Instance) (bool, error)
+	// String describes the condition in the errors and the logs
+	String() string
+}
+
+// Dependency is an instance that has to meet a condition before the instance depending on it is started
+type Dependency struct {
+	Instance  *Instance
+	Condition Condition
+}
+
+// DependsOn makes the instance wait for the other instance to meet the condition before it is started,
+// e.g. DependsOn(core, ConditionRunning()). A nil condition is the same as ConditionRunning.
+// The dependencies are honored by Group.Start, which starts the instances in dependency order,
+// and by WaitForDependencies, to be called before Start when the instance is started on its own.
+// A dependency that would make a cycle is rejected.
+// This function can only be called in the states 'Preparing', 'Committed' and 'Stopped'
+func (e *execution) DependsOn(other *Instance, condition Condition) error {
+	if !e.instance.IsInState(StatePreparing, StateCommitted, StateStopped) {
+		return ErrAddingDependencyNotAllowed.WithParams(e.instance.state.String())
+	}
+	if e.instance.sidecars.isSidecar {
+		return ErrAddingDependencyNotAllowedForSidecar.WithParams(e.instance.name)
+	}
+	if other == nil {
+		return ErrDependencyIsNil.WithParams(e.instance.name)
+	}
+	if other.sidecars.isSidecar {
+		return ErrDependencyOnSidecar.WithParams(e.instance.name, other.name)
+	}
+	if condition == nil {
+		condition = ConditionRunning()
+	}
+	if pc, ok := condition.(*probeCondition); ok {
+		if err := validateConditionProbe(pc.probe); err != nil {
+			return err
+		}
+	}
+
+	e.dependencies = append(e.dependencies, Dependency{Instance: other, Condition: condition})
+	if cycle := findDependencyCycle([]*Instance{e.instance}); cycle != nil {
+		e.dependencies = e.dependencies[:len(e.dependencies)-1]
+		return ErrDependencyCycle.WithParams(strings.Join(cycle, " -> "))
+	}
+
+	e.instance.Logger.WithFields(logrus.Fields{
+		"instance":   e.instance.name,
+		"dependency": other.name,
+		"condition":  condition.String(),
+	}).Debug("added dependency")
+	return nil
+}
+
+// Dependencies returns the dependencies of the instance, in the order they were added
+func (e *execution) Dependencies() []Dependency {
+	return append([]Dependency(nil), e.dependencies...)
+}
+
+// WaitForDependencies waits until all the dependencies of the instance meet their condition
+func (e *execution) WaitForDependencies(ctx context.Context) error {
+	for _, d := range e.dependencies {
+		if err := waitForCondition(ctx, d.Instance, d.Condition); err != nil {
+			return ErrWaitingForDependency.WithParams(e.instance.name, d.Instance.name, d.Condition.String()).Wrap(err)
+		}
+	}
+	return nil
+}
+
+// waitForCondition checks the condition on the instance until it is met or the context is done
+func waitForCondition(ctx context.Context, i *Instance, condition Condition) error {
+	var lastErr error
+	for {
+		met, err := condition.Met(ctx, i)
+		if met {
+			return nil
+		}
+		if err != nil {
+			lastErr = err
+			i.Logger.WithError(err).WithFields(logrus.Fields{
+				"instance":  i.name,
+				"condition": condition.String(),
+			}).Debug("checking condition failed, retrying")
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return lastErr
+			}
+			return ctx.Err()
+		case <-time.After(conditionPollInterval):
+		}
+	}
+}
+
+// findDependencyCycle returns the names of the instances of a dependency cycle that can be reached
+// from the given instances, the first instance of the cycle being repeated at its end, or nil if there is none
+func findDependencyCycle(roots []*Instance) []string {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	var (
+		marks = make(map[*Instance]int)
+		path  []*Instance
+		visit func(i *Instance) []string
+	)
+	visit = func(i *Instance) []string {
+		switch marks[i] {
+		case visited:
+			return nil
+		case visiting:
+			var cycle []string
+			for n := len(path) - 1; n >= 0; n-- {
+				cycle = append([]string{path[n].name}, cycle...)
+				if path[n] == i {
+					break
+				}
+			}
+			return append(cycle, i.name)
+		}
+
+		marks[i] = visiting
+		path = append(path, i)
+		for _, d := range i.execution.dependencies {
+			if cycle := visit(d.Instance); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		marks[i] = visited
+		return nil
+	}
+
+	for _, root := range roots {
+		if cycle := visit(root); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+type runningCondition struct{}
+
+// ConditionRunning is met once the instance is started and all its replicas are running and ready
+func ConditionRunning() Condition {
+	return runningCondition{}
+}
+
+func (runningCondition) Met(ctx context.Context, i *Instance) (bool, error) {
+	if !i.IsState(StateStarted) {
+		return false, nil
+	}
+	return i.execution.IsRunning(ctx)
+}
+
+func (runningCondition) String() string {
+	return "running"
+}
+
+type probeCondition struct {
+	probe *v1.Probe
+}
+
+// ConditionProbe is met once the probe succeeds against the first pod of the started instance,
+// e.g. a TCP probe on its RPC port.
+// All the probes are run inside the pod, so they check what the instance itself serves:
+// an exec probe runs its command, a TCP probe connects with nc, or bash when the image has no nc,
+// and an HTTP probe requests the path with curl or wget.
+// TCP and HTTP probes connect to the IP of the pod unless the probe sets a host.
+// The probe is checked every second, its period and thresholds are not used.
+func ConditionProbe(probe *v1.Probe) Condition {
+	return &probeCondition{probe: probe}
+}
+
+func (c *probeCondition) Met(ctx context.Context, i *Instance) (bool, error) {
+	if !i.IsState(StateStarted) {
+		return false, nil
+	}
+	if c.probe.Exec != nil {
+		_, err := i.execution.ExecuteCommand(ctx, c.probe.Exec.Command...)
+		return err == nil, err
+	}
+
+	pod, err := i.firstPod(ctx)
+	if err != nil {
+		return false, err
+	}
+	host := pod.Status.PodIP
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	timeout := int32(1)
+	if c.probe.TimeoutSeconds > 0 {
+		timeout = c.probe.TimeoutSeconds
+	}
+
+	var script string
+	if c.probe.TCPSocket != nil {
+		if c.probe.TCPSocket.Host != "" {
+			host = c.probe.TCPSocket.Host
+		}
+		script = tcpProbeScript(host, c.probe.TCPSocket.Port.IntValue(), timeout)
+	} else {
+		if c.probe.HTTPGet.Host != "" {
+			host = c.probe.HTTPGet.Host
+		}
+		script = httpProbeScript(c.probe.HTTPGet, host, timeout)
+	}
+	_, err = i.execution.ExecuteCommand(ctx, script)
+	return err == nil, err
+}
+
+// tcpProbeScript returns a script that succeeds if the host accepts connections on the port.
+// It runs in the pod with nc, or with the /dev/tcp files of bash when the image has no nc.
+func tcpProbeScript(host string, port int, timeout int32) string {
+	return fmt.Sprintf(
+		"if command -v nc >/dev/null 2>&1; then nc -z -w %[3]d %[1]s %[2]d; "+
+			"elif command -v bash >/dev/null 2>&1; then bash -c 'exec 3<>\"/dev/tcp/$1/$2\"' bash %[1]s %[2]d; "+
+			"else echo 'the tcp probe needs nc or bash in the image' >&2; exit 127; fi",
+		k8s.ShellQuote(host), port, timeout)
+}
+
+// httpProbeScript returns a script that succeeds if the request to the probe path gets a status below 400.
+// It runs in the pod with curl, or with wget when the image has no curl.
+// As with the kubelet, the certificate of an HTTPS server is not verified.
+func httpProbeScript(get *v1.HTTPGetAction, host string, timeout int32) string {
+	scheme := strings.ToLower(string(get.Scheme))
+	if scheme == "" {
+		scheme = "http"
+	}
+	url := k8s.ShellQuote(fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(host, get.Port.String()), get.Path))
+
+	var curlHeaders, wgetHeaders string
+	for _, header := range get.HTTPHeaders {
+		h := k8s.ShellQuote(header.Name + ": " + header.Value)
+		curlHeaders += " -H " + h
+		wgetHeaders += " --header " + h
+	}
+	var wgetInsecure string
+	if scheme == "https" {
+		wgetInsecure = " --no-check-certificate"
+	}
+
+	return fmt.Sprintf(
+		"if command -v curl >/dev/null 2>&1; then curl -fsk -o /dev/null -m %[1]d%[2]s %[4]s; "+
+			"elif command -v wget >/dev/null 2>&1; then wget -q -O /dev/null -T %[1]d%[5]s%[3]s %[4]s; "+
+			"else echo 'the http probe needs curl or wget in the image' >&2; exit 127; fi",
+		timeout, curlHeaders, wgetHeaders, url, wgetInsecure)
+}
+
+func (c *probeCondition) String() string {
+	switch {
+	case c.probe.Exec != nil:
+		return fmt.Sprintf("probe exec '%s'", strings.Join(c.probe.Exec.Command, " "))
+	case c.probe.TCPSocket != nil:
+		return fmt.Sprintf("probe tcp %s", c.probe.TCPSocket.Port.String())
+	case c.probe.HTTPGet != nil:
+		return fmt.Sprintf("probe http %s%s", c.probe.HTTPGet.Port.String(), c.probe.HTTPGet.Path)
+	}
+	return "probe"
+}
+
+// validateConditionProbe checks that the probe can be run by a ConditionProbe
+func validateConditionProbe(probe *v1.Probe) error {
+	switch {
+	case probe == nil:
+		return ErrInvalidConditionProbe.WithParams("the probe is nil")
+	case probe.Exec != nil:
+		return nil
+	case probe.TCPSocket != nil:
+		if probe.TCPSocket.Port.Type != intstr.Int {
+			return ErrInvalidConditionProbe.WithParams("named ports are not supported")
+		}
+		return nil
+	case probe.HTTPGet != nil:
+		if probe.HTTPGet.Port.Type != intstr.Int {
+			return ErrInvalidConditionProbe.WithParams("named ports are not supported")
+		}
+		return nil
+	}
+	return ErrInvalidConditionProbe.WithParams("only exec, tcp and http probes are supported")
+}
+
+type logLineCondition struct {
+	substr string
+}
+
+// ConditionLogLine is met once a line of the logs of the started instance contains the given string
+func ConditionLogLine(substr string) Condition {
+	return logLineCondition{substr: substr}
+}
+
+func (c logLineCondition) Met(ctx context.Context, i *Instance) (bool, error) {
+	if !i.IsState(StateStarted) {
+		return false, nil
+	}
+	logs, err := i.monitoring.Logs(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer logs.Close()
+
+	scanner := bufio.NewScanner(logs)
+	for scanner.Scan() {
+		if strings.Contains(scanner.Text(), c.substr) {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+func (c logLineCondition) String() string {
+	return fmt.Sprintf("log line '%s'", c.substr)
+}
+
+type funcCondition struct {
+	name  string
+	check func(ctx context.Context, i *Instance) (bool, error)
+}
+
+// ConditionFunc is met once the check returns true, the name describes the check in the errors and the logs
+func ConditionFunc(name string, check func(ctx context.Context, i *Instance) (bool, error)) Condition {
+	return funcCondition{name: name, check: check}
+}
+
+func (c funcCondition) Met(ctx context.Context, i *Instance) (bool, error) {
+	return c.check(ctx, i)
+}
+
+func (c funcCondition) String() string {
+	return c.name
+}
@@ -0,0 +1,70 @@
+package instance
+
+import (
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// runProbeScript runs the script with only the given tools in the PATH, as they would be found in the image
+func runProbeScript(t *testing.T, script string, tools map[string]string) error {
+	t.Helper()
+	dir := t.TempDir()
+	for name, target := range tools {
+		require.NoError(t, os.Symlink(target, filepath.Join(dir, name)))
+	}
+	cmd := exec.Command("/bin/sh", "-c", script)
+	cmd.Env = []string{"PATH=" + dir}
+	return cmd.Run()
+}
+
+func TestTCPProbeScript(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash is needed to run the probe without nc")
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	script := tcpProbeScript("127.0.0.1", port, 1)
+
+	assert.NoError(t, runProbeScript(t, script, map[string]string{"bash": bash}))
+	require.NoError(t, l.Close())
+	assert.Error(t, runProbeScript(t, script, map[string]string{"bash": bash}), "nothing listens on the port anymore")
+
+	var exitErr *exec.ExitError
+	require.ErrorAs(t, runProbeScript(t, script, nil), &exitErr)
+	assert.Equal(t, 127, exitErr.ExitCode(), "the image has neither nc nor bash")
+}
+
+func TestHTTPProbeScript(t *testing.T) {
+	// the fake curl prints its arguments, one per line, to check how they are quoted
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "args")
+	fakeCurl := filepath.Join(dir, "curl")
+	require.NoError(t, os.WriteFile(fakeCurl, []byte("#!/bin/sh\nfor a in \"$@\"; do echo \"$a\"; done > "+argsFile+"\n"), 0o755))
+
+	script := httpProbeScript(&v1.HTTPGetAction{
+		Path:        "/status?height=1&x=$(id)",
+		Port:        intstr.FromInt32(26657),
+		HTTPHeaders: []v1.HTTPHeader{{Name: "X-Note", Value: "it's ready"}},
+	}, "10.0.0.1", 2)
+	require.NoError(t, runProbeScript(t, script, map[string]string{"curl": fakeCurl}))
+
+	args, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"-fsk", "-o", "/dev/null", "-m", "2",
+		"-H", "X-Note: it's ready",
+		"http://10.0.0.1:26657/status?height=1&x=$(id)",
+	}, strings.Split(strings.TrimSpace(string(args)), "\n"))
+}
@@ -221,4 +221,112 @@ var (
 	ErrInstanceNameAlreadyExists                 = errors.New("InstanceNameAlreadyExists", "instance name '%s' already exists")
 	ErrSettingSidecarName                        = errors.New("SettingSidecarName", "error setting sidecar name with prefix '%s' for instance '%s'")
 	ErrCannotCloneInstance                       = errors.New("CannotCloneInstance", "cannot clone instance '%s' in state '%s'")
+	ErrListingResourcesForAttach                 = errors.New("ListingResourcesForAttach", "error listing resources to attach for scope '%s'")
+	ErrGettingRoleForAttach                      = errors.New("GettingRoleForAttach", "error getting role to attach instance '%s'")
+	ErrAttachingInstance                         = errors.New("AttachingInstance", "error attaching instance '%s'")
+	ErrAttachedSidecarCannotBeInitialized        = errors.New("AttachedSidecarCannotBeInitialized", "attached sidecar '%s' cannot be initialized")
+	ErrImageNotSetForInstance                    = errors.New("ImageNotSetForInstance", "image is not set for instance '%s'")
+	ErrSettingTTLNotAllowed                      = errors.New("SettingTTLNotAllowed", "setting ttl is only allowed in state 'Preparing', 'Committed' and 'Stopped'. Current state is '%s'")
+	ErrInvalidTTL                                = errors.New("InvalidTTL", "invalid ttl '%s'")
+	ErrAddingLabelNotAllowed                     = errors.New("AddingLabelNotAllowed", "adding a label is only allowed in state 'Preparing', 'Committed' and 'Stopped'. Current state is '%s'")
+	ErrLabelKeyReserved                          = errors.New("LabelKeyReserved", "label key '%s' is reserved for knuu")
+	ErrInvalidLabel                              = errors.New("InvalidLabel", "invalid label '%s=%s': %v")
+	ErrExecutingCommandOnInstances               = errors.New("ExecutingCommandOnInstances", "error executing command on instance '%s'")
+	ErrStoppingInstances                         = errors.New("StoppingInstances", "error stopping instance '%s'")
+	ErrDestroyingInstances                       = errors.New("DestroyingInstances", "error destroying instance '%s'")
+	ErrSettingReplicasNotAllowed                 = errors.New("SettingReplicasNotAllowed", "setting replicas is only allowed in state 'Preparing', 'Committed' and 'Stopped'. Current state is '%s'")
+	ErrSettingReplicasNotAllowedForSidecar       = errors.New("SettingReplicasNotAllowedForSidecar", "setting replicas is not allowed for sidecar '%s', it runs in every replica of its instance")
+	ErrInvalidReplicas                           = errors.New("InvalidReplicas", "invalid number of replicas %d, it must be at least 1")
+	ErrReplicasShareVolume                       = errors.New("ReplicasShareVolume", "instance '%s' can not have %d replicas sharing the volume at '%s', use WorkloadStatefulSet to give each replica its own volume, or the ReadWriteMany access mode")
+	ErrScalingNotAllowed                         = errors.New("ScalingNotAllowed", "scaling is only allowed in state 'Started'. Current state is '%s'")
+	ErrScalingInstance                           = errors.New("ScalingInstance", "error scaling instance '%s' to %d replicas")
+	ErrListingReplicaPods                        = errors.New("ListingReplicaPods", "error listing the pods of the replicas of instance '%s'")
+	ErrReplicaNotFound                           = errors.New("ReplicaNotFound", "replica %d of instance '%s' not found, %d replicas are running")
+	ErrExecutingCommandOnReplica                 = errors.New("ExecutingCommandOnReplica", "error executing command '%s' on replica %d of instance '%s'")
+	ErrGettingReplicaIP                          = errors.New("GettingReplicaIP", "IP address is not available for replica %d of instance '%s'")
+	ErrSettingWorkloadKindNotAllowed             = errors.New("SettingWorkloadKindNotAllowed", "setting the workload kind is only allowed in state 'Preparing' and 'Committed'. Current state is '%s'")
+	ErrSettingWorkloadKindNotAllowedForSidecar   = errors.New("SettingWorkloadKindNotAllowedForSidecar", "setting the workload kind is not allowed for sidecar '%s', it runs in the workload of its instance")
+	ErrInvalidWorkloadKind                       = errors.New("InvalidWorkloadKind", "invalid workload kind '%s'")
+	ErrDeployingHeadlessService                  = errors.New("DeployingHeadlessService", "error deploying headless service for instance '%s'")
+	ErrDestroyingHeadlessService                 = errors.New("DestroyingHeadlessService", "error destroying headless service for instance '%s'")
+	ErrDestroyingReplicaVolumes                  = errors.New("DestroyingReplicaVolumes", "error destroying the volumes of the replicas of instance '%s'")
+	ErrReplicaHostnameNotSupported               = errors.New("ReplicaHostnameNotSupported", "replicas of instance '%s' have no stable hostname, it does not run as a StatefulSet")
+	ErrStartingJobNotAllowed                     = errors.New("StartingJobNotAllowed", "instance '%s' runs as a Job, it can only be run with Run")
+	ErrScalingJobNotAllowed                      = errors.New("ScalingJobNotAllowed", "instance '%s' runs as a Job, it can not be scaled")
+	ErrSettingJobOptionsNotAllowed               = errors.New("SettingJobOptionsNotAllowed", "setting job options is only allowed in state 'Preparing', 'Committed' and 'Stopped'. Current state is '%s'")
+	ErrInvalidBackoffLimit                       = errors.New("InvalidBackoffLimit", "invalid backoff limit %d, it can not be negative")
+	ErrInvalidActiveDeadline                     = errors.New("InvalidActiveDeadline", "invalid active deadline '%s', it must be at least one second")
+	ErrRunningNotAllowedForWorkloadKind          = errors.New("RunningNotAllowedForWorkloadKind", "instance '%s' runs as a %s, only a Job can be run")
+	ErrRunningNotAllowed                         = errors.New("RunningNotAllowed", "running is only allowed in state 'Committed' or 'Stopped'. Current state of instance '%s' is '%s'")
+	ErrRunningWithSidecarsNotAllowed             = errors.New("RunningWithSidecarsNotAllowed", "instance '%s' has sidecars, which would keep its Job from completing")
+	ErrWaitingForRun                             = errors.New("WaitingForRun", "error waiting for the run of instance '%s' to finish")
+	ErrGettingRunResult                          = errors.New("GettingRunResult", "error getting the result of the run of instance '%s'")
+	ErrReadingFileFromImage                      = errors.New("ReadingFileFromImage", "error reading file '%s' from image '%s': %s")
+	ErrScalingDaemonSetNotAllowed                = errors.New("ScalingDaemonSetNotAllowed", "instance '%s' runs as a DaemonSet, it runs one pod per node and can not be scaled")
+	ErrNodeNotFound                              = errors.New("NodeNotFound", "instance '%s' has no pod on node '%s'")
+	ErrExecutingCommandOnNode                    = errors.New("ExecutingCommandOnNode", "error executing command '%s' on node '%s' of instance '%s'")
+	ErrInvalidParallelism                        = errors.New("InvalidParallelism", "invalid parallelism %d, it must be at least 1")
+	ErrGroupInstanceSkipped                      = errors.New("GroupInstanceSkipped", "instance '%s' was skipped as the group operation was cancelled")
+	ErrAddingDependencyNotAllowed                = errors.New("AddingDependencyNotAllowed", "adding a dependency is only allowed in state 'Preparing', 'Committed' and 'Stopped'. Current state is '%s'")
+	ErrAddingDependencyNotAllowedForSidecar      = errors.New("AddingDependencyNotAllowedForSidecar", "sidecar '%s' is started with its instance, it can not have dependencies")
+	ErrDependencyIsNil                           = errors.New("DependencyIsNil", "dependency of instance '%s' is nil")
+	ErrDependencyOnSidecar                       = errors.New("DependencyOnSidecar", "instance '%s' can not depend on sidecar '%s', it can depend on its instance")
+	ErrDependencyCycle                           = errors.New("DependencyCycle", "dependency cycle: %s")
+	ErrInvalidConditionProbe                     = errors.New("InvalidConditionProbe", "invalid probe for condition: %s")
+	ErrWaitingForDependency                      = errors.New("WaitingForDependency", "error waiting for dependency of instance '%s' on instance '%s' (%s)")
+	ErrDependencyFailed                          = errors.New("DependencyFailed", "instance '%s' was not started as its dependency '%s' failed to start")
+	ErrInvalidVolumeName                         = errors.New("InvalidVolumeName", "invalid volume name '%s' for instance '%s': %s")
+	ErrVolumeNameMustBeSet                       = errors.New("VolumeNameMustBeSet", "volume name must be set")
+	ErrVolumePathMustBeSet                       = errors.New("VolumePathMustBeSet", "volume path must be set")
+	ErrInvalidVolumeAccessMode                   = errors.New("InvalidVolumeAccessMode", "invalid access mode '%s' for volume '%s'")
+	ErrVolumeAlreadyExists                       = errors.New("VolumeAlreadyExists", "volume '%s' already exists in instance '%s'")
+	ErrVolumePathAlreadyUsed                     = errors.New("VolumePathAlreadyUsed", "path '%s' is already used by volume '%s' of instance '%s'")
+	ErrMountingVolumeNotAllowed                  = errors.New("MountingVolumeNotAllowed", "mounting a volume is only allowed in state 'Preparing', 'Committed' and 'Stopped'. Current state is '%s'")
+	ErrFailedToCreatePersistentVolumeClaim       = errors.New("FailedToCreatePersistentVolumeClaim", "failed to create persistent volume claim '%s'")
+	ErrExportingVolumeNotAllowed                 = errors.New("ExportingVolumeNotAllowed", "exporting a volume is only allowed in state 'Started' and 'Stopped'. Current state is '%s'")
+	ErrVolumeNotFound                            = errors.New("VolumeNotFound", "no volume is mounted at path '%s' in instance '%s'")
+	ErrExportingVolume                           = errors.New("ExportingVolume", "error exporting volume at path '%s' of instance '%s'")
+	ErrPushingVolumeExport                       = errors.New("PushingVolumeExport", "error pushing the export of volume at path '%s' of instance '%s' to minio")
+	ErrVolumeIsNodeLocal                         = errors.New("VolumeIsNodeLocal", "volume at path '%s' of instance '%s' is node-local and can only be accessed while the instance is started")
+	ErrDeployingVolumeHelperPod                  = errors.New("DeployingVolumeHelperPod", "error deploying helper pod for claim '%s'")
+	ErrSeedingVolumeNotAllowed                   = errors.New("SeedingVolumeNotAllowed", "seeding a volume is only allowed in state 'Preparing' and 'Committed'. Current state is '%s'")
+	ErrSeedingVolumeNotAllowedForSidecar         = errors.New("SeedingVolumeNotAllowedForSidecar", "seeding a volume is not allowed for sidecar '%s'")
+	ErrVolumeAlreadySeeded                       = errors.New("VolumeAlreadySeeded", "volume at path '%s' of instance '%s' already has a seed")
+	ErrInvalidVolumeSeed                         = errors.New("InvalidVolumeSeed", "invalid seed for volume at path '%s': %s %s")
+	ErrUploadingVolumeSeed                       = errors.New("UploadingVolumeSeed", "error uploading the seed of volume at path '%s' of instance '%s'")
+	ErrCopyingNotAllowed                         = errors.New("CopyingNotAllowed", "copying files is only allowed in state 'Started'. Current state is '%s'")
+	ErrCopyingToInstance                         = errors.New("CopyingToInstance", "error copying '%s' to '%s' in instance '%s'")
+	ErrCopyingFromInstance                       = errors.New("CopyingFromInstance", "error copying '%s' to '%s' from instance '%s'")
+	ErrCopySourceIsNil                           = errors.New("CopySourceIsNil", "the instance to copy from to instance '%s' is nil")
+	ErrCopyingBetweenInstancesNotAllowed         = errors.New("CopyingBetweenInstancesNotAllowed", "copying between instances is only allowed in state 'Started' and 'Stopped'. Instance '%s' is in state '%s'")
+	ErrCopyingBetweenInstances                   = errors.New("CopyingBetweenInstances", "error copying '%s' of instance '%s' to '%s' of instance '%s'")
+	ErrUnexpectedTarEntry                        = errors.New("UnexpectedTarEntry", "unexpected entry '%s' in the archive of '%s'")
+	ErrTarEntryOutsideDestination                = errors.New("TarEntryOutsideDestination", "entry '%s' is outside of '%s'")
+	ErrPathNotInVolume                           = errors.New("PathNotInVolume", "path '%s' of stopped instance '%s' is not in a volume")
+	ErrAddingLiveFileNotAllowed                  = errors.New("AddingLiveFileNotAllowed", "adding a live file is only allowed in state 'Preparing' and 'Committed'. Current state is '%s'")
+	ErrInvalidLiveFilePath                       = errors.New("InvalidLiveFilePath", "invalid live file path '%s', it must be absolute and not at the root")
+	ErrLiveFileAlreadyExists                     = errors.New("LiveFileAlreadyExists", "live file '%s' already exists in instance '%s'")
+	ErrLiveFilesMixedInDirectory                 = errors.New("LiveFilesMixedInDirectory", "the live files of directory '%s' must all be stored in a ConfigMap or all in a Secret")
+	ErrLiveFileNotUTF8                           = errors.New("LiveFileNotUTF8", "the content of live file '%s' is not UTF-8 text, binary content must be stored in a Secret")
+	ErrInvalidReloadSignal                       = errors.New("InvalidReloadSignal", "invalid reload signal '%s', it must be a signal name like HUP")
+	ErrUpdatingFileNotAllowed                    = errors.New("UpdatingFileNotAllowed", "updating a file is only allowed in state 'Started'. Current state is '%s'")
+	ErrLiveFileNotFound                          = errors.New("LiveFileNotFound", "file '%s' of instance '%s' was not added with AddLiveFile")
+	ErrUpdatingLiveFile                          = errors.New("UpdatingLiveFile", "error updating live file '%s' of instance '%s'")
+	ErrWaitingForLiveFile                        = errors.New("WaitingForLiveFile", "error waiting for the update of live file '%s' in pod '%s'")
+	ErrReloadingLiveFile                         = errors.New("ReloadingLiveFile", "error reloading live file '%s' in pod '%s'")
+	ErrFailedToCreateSecret                      = errors.New("FailedToCreateSecret", "failed to create secret")
+	ErrFailedToDeleteSecret                      = errors.New("FailedToDeleteSecret", "failed to delete secret")
+	ErrDeployingLiveFilesForInstance             = errors.New("DeployingLiveFilesForInstance", "error deploying live files for instance '%s'")
+	ErrDestroyingLiveFilesForInstance            = errors.New("DestroyingLiveFilesForInstance", "error destroying live files for instance '%s'")
+	ErrFileTooLargeForConfigMap                  = errors.New("FileTooLargeForConfigMap", "file '%s' is larger than %d bytes, the size of a ConfigMap, it can only be added with Minio")
+	ErrUploadingFiles                            = errors.New("UploadingFiles", "error uploading the files of instance '%s'")
+	ErrDeletingFilesArchive                      = errors.New("DeletingFilesArchive", "error deleting the archive of the files of instance '%s' from Minio")
+	ErrReadingSymlink                            = errors.New("ReadingSymlink", "error reading symlink '%s'")
+	ErrSymlinkNotInVolume                        = errors.New("SymlinkNotInVolume", "symlink '%s' of instance '%s' is not in a volume, once the instance is committed the symlinks can only be added in its volumes")
+	ErrAddingSecretNotAllowed                    = errors.New("AddingSecretNotAllowed", "adding a secret is only allowed in state 'Preparing' and 'Committed'. Current state is '%s'")
+	ErrInvalidSecretEnvName                      = errors.New("InvalidSecretEnvName", "invalid secret environment variable name '%s': %s")
+	ErrInvalidSecretFilePath                     = errors.New("InvalidSecretFilePath", "invalid secret file path '%s', it must be absolute")
+	ErrSecretFileAlreadyExists                   = errors.New("SecretFileAlreadyExists", "secret file '%s' already exists in instance '%s'")
+	ErrDeployingSecretsForInstance               = errors.New("DeployingSecretsForInstance", "error deploying secrets for instance '%s'")
+	ErrDestroyingSecretsForInstance              = errors.New("DestroyingSecretsForInstance", "error destroying secrets for instance '%s'")
 )
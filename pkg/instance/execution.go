@@ -2,13 +2,16 @@ package instance
 
 import (
 	"context"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/celestiaorg/knuu/pkg/k8s"
+	"github.com/celestiaorg/knuu/pkg/minio"
+	"github.com/celestiaorg/knuu/pkg/reaper"
 
 	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 const (
@@ -19,11 +22,29 @@ const (
 	labelNameKey        = "knuu.sh/name"
 	labelK8sNameKey     = "knuu.sh/k8s-name"
 	labelTypeKey        = "knuu.sh/type"
+	labelParentKey      = "knuu.sh/parent"
+	labelVolumeKey      = "knuu.sh/volume"
 	labelKnuuValue      = "knuu"
+	// labelKnuuPrefix is the prefix of the labels reserved for knuu
+	labelKnuuPrefix = "knuu.sh/"
 )
 
 type execution struct {
 	instance *Instance
+	// ttl is the time after which the instance is removed by the reaper once started
+	ttl time.Duration
+	// labels are the labels set by the user, they are added to the resources of the instance
+	labels map[string]string
+	// replicas is the number of pods of the instance
+	replicas int32
+	// workloadKind is the kind of workload that runs the pods of the instance
+	workloadKind WorkloadKind
+	// backoffLimit is the number of retries of a failed run, for a Job
+	backoffLimit int32
+	// activeDeadline is the time a run can take, for a Job
+	activeDeadline time.Duration
+	// dependencies are the instances that have to meet a condition before the instance is started
+	dependencies []Dependency
 }
 
 func (i *Instance) Execution() *execution {
@@ -37,27 +58,72 @@ func (e *execution) ExecuteCommand(ctx context.Context, command ...string) (stri
 		return "", ErrExecutingCommandNotAllowed.WithParams(e.instance.state.String())
 	}
 
-	var (
-		instanceName  string
-		eErr          *Error
-		containerName = e.instance.name
-	)
-
+	var eErr *Error
 	if e.instance.sidecars.isSidecar {
-		instanceName = e.instance.parentInstance.name
 		eErr = ErrExecutingCommandInSidecar.WithParams(command, e.instance.name, e.instance.parentInstance.name)
 	} else {
-		instanceName = e.instance.name
 		eErr = ErrExecutingCommandInInstance.WithParams(command, e.instance.name)
 	}
 
-	pod, err := e.instance.K8sClient.GetFirstPodFromReplicaSet(ctx, instanceName)
+	pod, err := e.instance.firstPod(ctx)
+	if err != nil {
+		return "", err
+	}
+	return e.executeCommandInPod(ctx, pod.Name, eErr, command)
+}
+
+// ExecuteCommandOnReplica executes the given command in the given replica of the instance.
+// The replicas are numbered from 0, the oldest first.
+// This function can only be called in the states 'Started'
+func (e *execution) ExecuteCommandOnReplica(ctx context.Context, replica int, command ...string) (string, error) {
+	if e.instance.state != StateStarted {
+		return "", ErrExecutingCommandNotAllowed.WithParams(e.instance.state.String())
+	}
+
+	pod, err := e.instance.replicaPod(ctx, replica)
+	if err != nil {
+		return "", err
+	}
+	eErr := ErrExecutingCommandOnReplica.WithParams(command, replica, e.instance.name)
+	return e.executeCommandInPod(ctx, pod.Name, eErr, command)
+}
+
+// ExecuteCommandOnNode executes the given command in the pod of the instance that runs on the given node,
+// e.g. in the pod of an instance that runs as a DaemonSet. Nodes lists the nodes the instance runs on.
+// This function can only be called in the states 'Started'
+func (e *execution) ExecuteCommandOnNode(ctx context.Context, node string, command ...string) (string, error) {
+	if e.instance.state != StateStarted {
+		return "", ErrExecutingCommandNotAllowed.WithParams(e.instance.state.String())
+	}
+
+	pod, err := e.instance.nodePod(ctx, node)
+	if err != nil {
+		return "", err
+	}
+	eErr := ErrExecutingCommandOnNode.WithParams(command, node, e.instance.name)
+	return e.executeCommandInPod(ctx, pod.Name, eErr, command)
+}
+
+// Nodes returns the names of the nodes the pods of the instance run on, sorted by name for a DaemonSet,
+// or in the order of the replicas for the other workload kinds.
+// A pod that is not scheduled yet is skipped.
+func (e *execution) Nodes(ctx context.Context) ([]string, error) {
+	pods, err := e.instance.pods(ctx)
 	if err != nil {
-		return "", ErrGettingPodFromReplicaSet.WithParams(e.instance.name).Wrap(err)
+		return nil, err
+	}
+	nodes := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" {
+			nodes = append(nodes, pod.Spec.NodeName)
+		}
 	}
+	return nodes, nil
+}
 
+func (e *execution) executeCommandInPod(ctx context.Context, podName string, eErr *Error, command []string) (string, error) {
 	commandWithShell := []string{"/bin/sh", "-c", strings.Join(command, " ")}
-	output, err := e.instance.K8sClient.RunCommandInPod(ctx, pod.Name, containerName, commandWithShell)
+	output, err := e.instance.K8sClient.RunCommandInPod(ctx, podName, e.instance.name, commandWithShell)
 	if err != nil {
 		return "", eErr.Wrap(err)
 	}
@@ -106,6 +172,19 @@ func (e *execution) StartAsync(ctx context.Context) error {
 	if e.instance.sidecars.isSidecar {
 		return ErrStartingSidecarNotAllowed
 	}
+	if e.workloadKind == WorkloadJob {
+		return ErrStartingJobNotAllowed.WithParams(e.instance.name)
+	}
+
+	// an instance attached while stopped has no image, as its pod spec is gone
+	if e.instance.build.imageName == "" {
+		return ErrImageNotSetForInstance.WithParams(e.instance.name)
+	}
+
+	// the volumes can be added after the replicas are set
+	if err := e.validateReplicaVolumes(int(e.replicas)); err != nil {
+		return err
+	}
 
 	if e.instance.state == StateCommitted {
 		if err := e.deployResourcesForCommittedState(ctx); err != nil {
@@ -142,6 +221,14 @@ func (e *execution) IsRunning(ctx context.Context) (bool, error) {
 		return false, ErrCheckingIfInstanceRunningNotAllowed.WithParams(e.instance.state.String())
 	}
 
+	switch e.workloadKind {
+	case WorkloadStatefulSet:
+		return e.instance.K8sClient.IsStatefulSetRunning(ctx, e.instance.name)
+	case WorkloadJob:
+		return e.instance.K8sClient.IsJobRunning(ctx, e.instance.name)
+	case WorkloadDaemonSet:
+		return e.instance.K8sClient.IsDaemonSetRunning(ctx, e.instance.name)
+	}
 	return e.instance.K8sClient.IsReplicaSetRunning(ctx, e.instance.name)
 }
 
@@ -220,9 +307,210 @@ func (b *execution) SetImage(ctx context.Context, image string) error {
 	return b.instance.build.SetImage(ctx, image)
 }
 
+// SetTTL sets the time to live of the instance, counted from the moment it is started or run.
+// Once it has passed, the instance and its sidecars are removed by the reaper, if the scope runs one,
+// even if the timeout of the scope has not been reached yet.
+// This function can only be called in the states 'Preparing', 'Committed' and 'Stopped'
+func (e *execution) SetTTL(ttl time.Duration) error {
+	if !e.instance.IsInState(StatePreparing, StateCommitted, StateStopped) {
+		return ErrSettingTTLNotAllowed.WithParams(e.instance.state.String())
+	}
+	if ttl < 0 {
+		return ErrInvalidTTL.WithParams(ttl.String())
+	}
+	e.ttl = ttl
+	e.instance.Logger.WithFields(logrus.Fields{
+		"instance": e.instance.name,
+		"ttl":      ttl.String(),
+	}).Debug("set ttl")
+	return nil
+}
+
+// SetReplicas sets the number of pods of the instance, 1 by default.
+// All the replicas are identical and share the service of the instance, which balances the traffic between them.
+// The replicas of a ReplicaSet share the claims of the volumes, so an instance with ReadWriteOnce volumes
+// can only have several replicas as a StatefulSet, where each replica gets its own claims, see SetWorkloadKind.
+// This function can only be called in the states 'Preparing', 'Committed' and 'Stopped'
+func (e *execution) SetReplicas(replicas int) error {
+	if e.instance.sidecars.isSidecar {
+		return ErrSettingReplicasNotAllowedForSidecar.WithParams(e.instance.name)
+	}
+	if !e.instance.IsInState(StatePreparing, StateCommitted, StateStopped) {
+		return ErrSettingReplicasNotAllowed.WithParams(e.instance.state.String())
+	}
+	if replicas < 1 {
+		return ErrInvalidReplicas.WithParams(replicas)
+	}
+	if err := e.validateReplicaVolumes(replicas); err != nil {
+		return err
+	}
+	e.replicas = int32(replicas)
+	e.instance.Logger.WithFields(logrus.Fields{
+		"instance": e.instance.name,
+		"replicas": replicas,
+	}).Debug("set replicas")
+	return nil
+}
+
+// validateReplicaVolumes checks that the replicas of a ReplicaSet do not share a claim that
+// can only be mounted by a single node, as the replicas scheduled on the other nodes would never start
+func (e *execution) validateReplicaVolumes(replicas int) error {
+	if replicas <= 1 || e.workloadKind != WorkloadReplicaSet {
+		return nil
+	}
+	instances := []*Instance{e.instance}
+	for _, sidecar := range e.instance.sidecars.sidecars {
+		instances = append(instances, sidecar.Instance())
+	}
+	for _, i := range instances {
+		for _, v := range i.storage.volumes {
+			if v.AccessMode != v1.ReadWriteMany && v.AccessMode != v1.ReadOnlyMany {
+				return ErrReplicasShareVolume.WithParams(e.instance.name, replicas, v.Path)
+			}
+		}
+	}
+	return nil
+}
+
+// Replicas returns the number of pods of the instance
+func (e *execution) Replicas() int {
+	return int(e.replicas)
+}
+
+// Scale changes the number of pods of a started instance.
+// It does not wait for the new replicas to be running, WaitInstanceIsRunning can be used for that.
+// This function can only be called in the state 'Started'
+func (e *execution) Scale(ctx context.Context, replicas int) error {
+	if e.instance.sidecars.isSidecar {
+		return ErrSettingReplicasNotAllowedForSidecar.WithParams(e.instance.name)
+	}
+	if !e.instance.IsInState(StateStarted) {
+		return ErrScalingNotAllowed.WithParams(e.instance.state.String())
+	}
+	if replicas < 1 {
+		return ErrInvalidReplicas.WithParams(replicas)
+	}
+	if e.workloadKind == WorkloadJob {
+		return ErrScalingJobNotAllowed.WithParams(e.instance.name)
+	}
+	if e.workloadKind == WorkloadDaemonSet {
+		return ErrScalingDaemonSetNotAllowed.WithParams(e.instance.name)
+	}
+
+	if err := e.validateReplicaVolumes(replicas); err != nil {
+		return err
+	}
+
+	if e.workloadKind == WorkloadStatefulSet {
+		ss, err := e.instance.K8sClient.ScaleStatefulSet(ctx, e.instance.name, int32(replicas))
+		if err != nil {
+			return ErrScalingInstance.WithParams(e.instance.name, replicas).Wrap(err)
+		}
+		e.instance.kubernetesStatefulSet = ss
+	} else {
+		rs, err := e.instance.K8sClient.ScaleReplicaSet(ctx, e.instance.name, int32(replicas))
+		if err != nil {
+			return ErrScalingInstance.WithParams(e.instance.name, replicas).Wrap(err)
+		}
+		e.instance.kubernetesReplicaSet = rs
+	}
+	e.replicas = int32(replicas)
+	e.instance.Logger.WithFields(logrus.Fields{
+		"instance": e.instance.name,
+		"replicas": replicas,
+	}).Debug("scaled instance")
+	return nil
+}
+
+// SetWorkloadKind sets the kind of workload that runs the pods of the instance, WorkloadReplicaSet by default.
+// With WorkloadStatefulSet, the replicas are named name-0, name-1... and can be reached at a stable DNS name,
+// see GetReplicaHostname, and each replica gets its own volumes, which are kept when the instance is stopped.
+// With WorkloadJob, the instance is run to completion with Run, and a single replica is run.
+// With WorkloadDaemonSet, a pod runs on every node, see Nodes and ExecuteCommandOnNode, and its volumes are node-local.
+// This function can only be called in the states 'Preparing' and 'Committed'
+func (e *execution) SetWorkloadKind(kind WorkloadKind) error {
+	if e.instance.sidecars.isSidecar {
+		return ErrSettingWorkloadKindNotAllowedForSidecar.WithParams(e.instance.name)
+	}
+	if !e.instance.IsInState(StatePreparing, StateCommitted) {
+		return ErrSettingWorkloadKindNotAllowed.WithParams(e.instance.state.String())
+	}
+	if kind != WorkloadReplicaSet && kind != WorkloadStatefulSet && kind != WorkloadJob && kind != WorkloadDaemonSet {
+		return ErrInvalidWorkloadKind.WithParams(kind.String())
+	}
+	e.workloadKind = kind
+	e.instance.Logger.WithFields(logrus.Fields{
+		"instance": e.instance.name,
+		"kind":     kind.String(),
+	}).Debug("set workload kind")
+	return nil
+}
+
+// WorkloadKind returns the kind of workload that runs the pods of the instance, the one of its instance for a sidecar
+func (e *execution) WorkloadKind() WorkloadKind {
+	return e.instance.podOwner().execution.workloadKind
+}
+
+// AddLabel adds a label to the instance.
+// The label is set on all the resources of the instance and can be used to find the instance with a Filter.
+// The labels used by knuu itself can not be set.
+// This function can only be called in the states 'Preparing', 'Committed' and 'Stopped'
+func (e *execution) AddLabel(key, value string) error {
+	if !e.instance.IsInState(StatePreparing, StateCommitted, StateStopped) {
+		return ErrAddingLabelNotAllowed.WithParams(e.instance.state.String())
+	}
+	if isReservedLabel(key) {
+		return ErrLabelKeyReserved.WithParams(key)
+	}
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return ErrInvalidLabel.WithParams(key, value, errs)
+	}
+	if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+		return ErrInvalidLabel.WithParams(key, value, errs)
+	}
+
+	if e.labels == nil {
+		e.labels = make(map[string]string)
+	}
+	e.labels[key] = value
+	e.instance.Logger.WithFields(logrus.Fields{
+		"instance": e.instance.name,
+		"key":      key,
+		"value":    value,
+	}).Debug("added label")
+	return nil
+}
+
+// UserLabels returns the labels added with AddLabel
+func (e *execution) UserLabels() map[string]string {
+	labels := make(map[string]string, len(e.labels))
+	for k, v := range e.labels {
+		labels[k] = v
+	}
+	return labels
+}
+
+// attachLabels restores the labels added by the user from the labels of a resource of the instance
+func (e *execution) attachLabels(labels map[string]string) {
+	for k, v := range labels {
+		if isReservedLabel(k) {
+			continue
+		}
+		if e.labels == nil {
+			e.labels = make(map[string]string)
+		}
+		e.labels[k] = v
+	}
+}
+
+func isReservedLabel(key string) bool {
+	return key == labelAppKey || key == labelManagedByKey || strings.HasPrefix(key, labelKnuuPrefix)
+}
+
 // Labels returns the labels for the instance
 func (e *execution) Labels() map[string]string {
-	return map[string]string{
+	labels := e.UserLabels()
+	for k, v := range map[string]string{
 		labelAppKey:         e.instance.name,
 		labelManagedByKey:   labelKnuuValue,
 		labelScopeKey:       e.instance.Scope,
@@ -230,7 +518,14 @@ func (e *execution) Labels() map[string]string {
 		labelNameKey:        e.instance.name,
 		labelK8sNameKey:     e.instance.name,
 		labelTypeKey:        e.instance.instanceType.String(),
+	} {
+		labels[k] = v
+	}
+	// the parent label allows to find the resources of a sidecar when attaching to a scope
+	if e.instance.sidecars.isSidecar && e.instance.parentInstance != nil {
+		labels[labelParentKey] = e.instance.parentInstance.name
 	}
+	return labels
 }
 
 // Destroy destroys the instance
@@ -244,8 +539,11 @@ func (e *execution) Destroy(ctx context.Context) error {
 		return ErrDestroyingNotAllowed.WithParams(e.instance.state.String())
 	}
 
-	if err := e.destroyPod(ctx); err != nil {
-		return ErrDestroyingPod.WithParams(e.instance.name).Wrap(err)
+	// the pod of a stopped instance, or of a finished run, has already been destroyed
+	if e.instance.state == StateStarted {
+		if err := e.destroyPod(ctx); err != nil {
+			return ErrDestroyingPod.WithParams(e.instance.name).Wrap(err)
+		}
 	}
 	if err := e.instance.resources.destroyResources(ctx); err != nil {
 		return ErrDestroyingResourcesForInstance.WithParams(e.instance.name).Wrap(err)
@@ -268,22 +566,10 @@ func (e *execution) Destroy(ctx context.Context) error {
 	return nil
 }
 
-// BatchDestroy destroys a list of instances.
+// BatchDestroy destroys a list of instances, in parallel.
+// The nil instances are skipped, and the errors of the instances are returned together in a GroupError.
 func BatchDestroy(ctx context.Context, instances ...*Instance) error {
-	if os.Getenv("KNUU_SKIP_CLEANUP") == "true" {
-		logrus.Info("Skipping cleanup")
-		return nil
-	}
-
-	for _, i := range instances {
-		if i == nil {
-			continue
-		}
-		if err := i.Execution().Destroy(ctx); err != nil {
-			return err
-		}
-	}
-	return nil
+	return NewGroup(instances...).Destroy(ctx)
 }
 
 // deployResourcesForCommittedState handles resource deployment for instances in the 'Committed' state
@@ -324,7 +610,32 @@ func (e *execution) deployPod(ctx context.Context) error {
 		}
 	}
 
-	// Deploy the statefulSet
+	switch e.workloadKind {
+	case WorkloadStatefulSet:
+		statefulSet, err := e.instance.K8sClient.CreateStatefulSet(ctx, e.prepareStatefulSetConfig(), true)
+		if err != nil {
+			return ErrFailedToDeployPod.Wrap(err)
+		}
+		e.instance.kubernetesStatefulSet = statefulSet
+		e.instance.Logger.WithField("instance", e.instance.name).Debugf("started statefulSet")
+		return nil
+	case WorkloadJob:
+		if _, err := e.instance.K8sClient.CreateJob(ctx, e.prepareJobConfig(), true); err != nil {
+			return ErrFailedToDeployPod.Wrap(err)
+		}
+		e.instance.Logger.WithField("instance", e.instance.name).Debugf("started job")
+		return nil
+	case WorkloadDaemonSet:
+		daemonSet, err := e.instance.K8sClient.DeployDaemonSet(ctx, e.prepareDaemonSetConfig(), true)
+		if err != nil {
+			return ErrFailedToDeployPod.Wrap(err)
+		}
+		e.instance.kubernetesDaemonSet = daemonSet
+		e.instance.Logger.WithField("instance", e.instance.name).Debugf("started daemonSet")
+		return nil
+	}
+
+	// Deploy the replicaSet
 	replicaSet, err := e.instance.K8sClient.CreateReplicaSet(ctx, e.prepareReplicaSetConfig(), true)
 	if err != nil {
 		return ErrFailedToDeployPod.Wrap(err)
@@ -334,14 +645,24 @@ func (e *execution) deployPod(ctx context.Context) error {
 	e.instance.kubernetesReplicaSet = replicaSet
 
 	// Log the deployment of the pod
-	e.instance.Logger.WithField("instance", e.instance.name).Debugf("started statefulSet")
+	e.instance.Logger.WithField("instance", e.instance.name).Debugf("started replicaSet")
 	return nil
 }
 
 // destroyPod destroys the pod for the instance (no grace period)
 // Skips if the pod is already destroyed
 func (e *execution) destroyPod(ctx context.Context) error {
-	err := e.instance.K8sClient.DeleteReplicaSetWithGracePeriod(ctx, e.instance.name, nil)
+	var err error
+	switch e.workloadKind {
+	case WorkloadStatefulSet:
+		err = e.instance.K8sClient.DeleteStatefulSetWithGracePeriod(ctx, e.instance.name, nil)
+	case WorkloadJob:
+		err = e.instance.K8sClient.DeleteJob(ctx, e.instance.name)
+	case WorkloadDaemonSet:
+		err = e.instance.K8sClient.DeleteDaemonSet(ctx, e.instance.name)
+	default:
+		err = e.instance.K8sClient.DeleteReplicaSetWithGracePeriod(ctx, e.instance.name, nil)
+	}
 	if err != nil {
 		return ErrFailedToDeletePod.Wrap(err)
 	}
@@ -366,47 +687,91 @@ func (e *execution) destroyPod(ctx context.Context) error {
 	return nil
 }
 
-// prepareConfig prepares the config for the instance
+// prepareDaemonSetConfig prepares the DaemonSet config for the instance
+func (e *execution) prepareDaemonSetConfig() k8s.DaemonSetConfig {
+	return k8s.DaemonSetConfig{
+		Namespace: e.instance.K8sClient.Namespace(),
+		Name:      e.instance.name,
+		Labels:    e.Labels(),
+		PodConfig: e.preparePodConfig(),
+	}
+}
+
+// prepareReplicaSetConfig prepares the ReplicaSet config for the instance
 func (e *execution) prepareReplicaSetConfig() k8s.ReplicaSetConfig {
+	return k8s.ReplicaSetConfig{
+		Namespace: e.instance.K8sClient.Namespace(),
+		Name:      e.instance.name,
+		Labels:    e.Labels(),
+		Replicas:  e.replicas,
+		PodConfig: e.preparePodConfig(),
+	}
+}
+
+// prepareStatefulSetConfig prepares the StatefulSet config for the instance
+func (e *execution) prepareStatefulSetConfig() k8s.StatefulSetConfig {
+	return k8s.StatefulSetConfig{
+		Namespace:   e.instance.K8sClient.Namespace(),
+		Name:        e.instance.name,
+		Labels:      e.Labels(),
+		Replicas:    e.replicas,
+		ServiceName: e.instance.headlessServiceName(),
+		PodConfig:   e.preparePodConfig(),
+	}
+}
+
+// preparePodConfig prepares the pod config for the instance
+func (e *execution) preparePodConfig() k8s.PodConfig {
 	containerConfig := k8s.ContainerConfig{
-		Name:            e.instance.name,
-		Image:           e.instance.build.imageName,
-		ImagePullPolicy: e.instance.build.imagePullPolicy,
-		Command:         e.instance.build.command,
-		Args:            e.instance.build.args,
-		Env:             e.instance.build.env,
-		Volumes:         e.instance.storage.volumes,
-		MemoryRequest:   e.instance.resources.memoryRequest,
-		MemoryLimit:     e.instance.resources.memoryLimit,
-		CPURequest:      e.instance.resources.cpuRequest,
-		LivenessProbe:   e.instance.monitoring.livenessProbe,
-		ReadinessProbe:  e.instance.monitoring.readinessProbe,
-		StartupProbe:    e.instance.monitoring.startupProbe,
-		Files:           e.instance.storage.files,
-		SecurityContext: e.instance.security.prepareSecurityContext(),
+		Name:                          e.instance.name,
+		Image:                         e.instance.build.imageName,
+		ImagePullPolicy:               e.instance.build.imagePullPolicy,
+		Command:                       e.instance.build.command,
+		Args:                          e.instance.build.args,
+		Env:                           e.instance.build.env,
+		Volumes:                       e.instance.storage.volumes,
+		MemoryRequest:                 e.instance.resources.memoryRequest,
+		MemoryLimit:                   e.instance.resources.memoryLimit,
+		CPURequest:                    e.instance.resources.cpuRequest,
+		LivenessProbe:                 e.instance.monitoring.livenessProbe,
+		ReadinessProbe:                e.instance.monitoring.readinessProbe,
+		StartupProbe:                  e.instance.monitoring.startupProbe,
+		Files:                         e.instance.storage.files,
+		FilesArchiveURL:               e.instance.storage.filesArchiveURL,
+		FilesArchiveCredentialsSecret: minio.CredentialsSecretName,
+		LiveFiles:                     e.instance.storage.k8sLiveFiles(),
+		SecretEnv:                     e.instance.security.k8sSecretEnv(),
+		SecretFiles:                   e.instance.storage.k8sSecretFiles(),
+		SecurityContext:               e.instance.security.prepareSecurityContext(),
 	}
 
 	sidecarConfigs := make([]k8s.ContainerConfig, 0)
 	for _, sidecar := range e.instance.sidecars.sidecars {
 		sidecarConfigs = append(sidecarConfigs, k8s.ContainerConfig{
-			Name:            sidecar.Instance().name,
-			Image:           sidecar.Instance().build.imageName,
-			Command:         sidecar.Instance().build.command,
-			Args:            sidecar.Instance().build.args,
-			Env:             sidecar.Instance().build.env,
-			Volumes:         sidecar.Instance().storage.volumes,
-			MemoryRequest:   sidecar.Instance().resources.memoryRequest,
-			MemoryLimit:     sidecar.Instance().resources.memoryLimit,
-			CPURequest:      sidecar.Instance().resources.cpuRequest,
-			LivenessProbe:   sidecar.Instance().monitoring.livenessProbe,
-			ReadinessProbe:  sidecar.Instance().monitoring.readinessProbe,
-			StartupProbe:    sidecar.Instance().monitoring.startupProbe,
-			Files:           sidecar.Instance().storage.files,
-			SecurityContext: sidecar.Instance().security.prepareSecurityContext(),
+			Name:                          sidecar.Instance().name,
+			Image:                         sidecar.Instance().build.imageName,
+			Command:                       sidecar.Instance().build.command,
+			Args:                          sidecar.Instance().build.args,
+			Env:                           sidecar.Instance().build.env,
+			Volumes:                       sidecar.Instance().storage.volumes,
+			VolumeMounts:                  sidecar.Instance().storage.volumeMounts,
+			MemoryRequest:                 sidecar.Instance().resources.memoryRequest,
+			MemoryLimit:                   sidecar.Instance().resources.memoryLimit,
+			CPURequest:                    sidecar.Instance().resources.cpuRequest,
+			LivenessProbe:                 sidecar.Instance().monitoring.livenessProbe,
+			ReadinessProbe:                sidecar.Instance().monitoring.readinessProbe,
+			StartupProbe:                  sidecar.Instance().monitoring.startupProbe,
+			Files:                         sidecar.Instance().storage.files,
+			FilesArchiveURL:               sidecar.Instance().storage.filesArchiveURL,
+			FilesArchiveCredentialsSecret: minio.CredentialsSecretName,
+			LiveFiles:                     sidecar.Instance().storage.k8sLiveFiles(),
+			SecretEnv:                     sidecar.Instance().security.k8sSecretEnv(),
+			SecretFiles:                   sidecar.Instance().storage.k8sSecretFiles(),
+			SecurityContext:               sidecar.Instance().security.prepareSecurityContext(),
 		})
 	}
 
-	podConfig := k8s.PodConfig{
+	return k8s.PodConfig{
 		Namespace:          e.instance.K8sClient.Namespace(),
 		Name:               e.instance.name,
 		Labels:             e.Labels(),
@@ -414,17 +779,29 @@ func (e *execution) prepareReplicaSetConfig() k8s.ReplicaSetConfig {
 		FsGroup:            e.instance.storage.fsGroup,
 		ContainerConfig:    containerConfig,
 		SidecarConfigs:     sidecarConfigs,
+		Annotations:        e.annotations(),
 	}
+}
 
-	return k8s.ReplicaSetConfig{
-		Namespace: e.instance.K8sClient.Namespace(),
-		Name:      e.instance.name,
-		Labels:    e.Labels(),
-		Replicas:  1,
-		PodConfig: podConfig,
+// annotations returns the pod annotations for the instance
+func (e *execution) annotations() map[string]string {
+	if e.ttl == 0 {
+		return nil
+	}
+	return map[string]string{
+		reaper.AnnotationExpiresAt: time.Now().Add(e.ttl).UTC().Format(time.RFC3339),
 	}
 }
 
 func (e *execution) clone() *execution {
-	return &execution{instance: nil}
+	return &execution{
+		instance:       nil,
+		ttl:            e.ttl,
+		labels:         e.UserLabels(),
+		replicas:       e.replicas,
+		workloadKind:   e.workloadKind,
+		backoffLimit:   e.backoffLimit,
+		activeDeadline: e.activeDeadline,
+		dependencies:   append([]Dependency(nil), e.dependencies...),
+	}
 }
@@ -0,0 +1,343 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/celestiaorg/knuu/pkg/errors"
+)
+
+// defaultGroupParallelism is the number of instances of a group that are handled at the same time by default
+const defaultGroupParallelism = 10
+
+// Group runs the lifecycle operations of several instances in parallel,
+// with at most a given number of instances handled at the same time.
+// Unlike a List, an operation is run on all the instances even if some of them fail,
+// unless the group is set to fail fast, and the errors are returned together in a GroupError.
+type Group struct {
+	instances   []*Instance
+	parallelism int
+	failFast    bool
+}
+
+// NewGroup creates a group of the given instances, handled 10 at a time by default
+func NewGroup(instances ...*Instance) *Group {
+	return &Group{
+		instances:   instances,
+		parallelism: defaultGroupParallelism,
+	}
+}
+
+// Group returns a group of the instances of the list
+func (l List) Group() *Group {
+	return NewGroup(l...)
+}
+
+// Add adds instances to the group
+func (g *Group) Add(instances ...*Instance) {
+	g.instances = append(g.instances, instances...)
+}
+
+// Instances returns the instances of the group
+func (g *Group) Instances() []*Instance {
+	return g.instances
+}
+
+// SetParallelism sets the number of instances that are handled at the same time
+func (g *Group) SetParallelism(parallelism int) error {
+	if parallelism < 1 {
+		return ErrInvalidParallelism.WithParams(parallelism)
+	}
+	g.parallelism = parallelism
+	return nil
+}
+
+// SetFailFast makes the operations stop at the first failure:
+// the context of the running operations is cancelled and the remaining instances are skipped.
+func (g *Group) SetFailFast(failFast bool) {
+	g.failFast = failFast
+}
+
+// Commit commits all the instances of the group
+func (g *Group) Commit(ctx context.Context) error {
+	return g.run(ctx, "commit", func(ctx context.Context, i *Instance) error {
+		return i.build.Commit(ctx)
+	})
+}
+
+// StartAsync starts all the instances of the group without waiting for them to be ready.
+// The dependencies of the instances are not waited for, Start can be used for that.
+func (g *Group) StartAsync(ctx context.Context) error {
+	return g.run(ctx, "start", func(ctx context.Context, i *Instance) error {
+		return i.execution.StartAsync(ctx)
+	})
+}
+
+// WaitRunning waits until all the instances of the group are running
+func (g *Group) WaitRunning(ctx context.Context) error {
+	return g.run(ctx, "wait running", func(ctx context.Context, i *Instance) error {
+		return i.execution.WaitInstanceIsRunning(ctx)
+	})
+}
+
+// Stop stops all the instances of the group
+func (g *Group) Stop(ctx context.Context) error {
+	return g.run(ctx, "stop", func(ctx context.Context, i *Instance) error {
+		return i.execution.Stop(ctx)
+	})
+}
+
+// Destroy destroys all the instances of the group.
+// Nothing is destroyed if the environment variable KNUU_SKIP_CLEANUP is set to true.
+func (g *Group) Destroy(ctx context.Context) error {
+	if os.Getenv("KNUU_SKIP_CLEANUP") == "true" {
+		logrus.Info("Skipping cleanup")
+		return nil
+	}
+	return g.run(ctx, "destroy", func(ctx context.Context, i *Instance) error {
+		return i.execution.Destroy(ctx)
+	})
+}
+
+// CopyFromInstance copies the file or directory at srcPath in the source instance to dstPath in all the instances of the group,
+// see storage.CopyFromInstance. The source is skipped if it is in the group.
+func (g *Group) CopyFromInstance(ctx context.Context, src *Instance, srcPath, dstPath string) error {
+	return g.run(ctx, "copy", func(ctx context.Context, i *Instance) error {
+		if i == src {
+			return nil
+		}
+		return i.storage.CopyFromInstance(ctx, src, srcPath, dstPath)
+	})
+}
+
+// Start starts all the instances of the group and waits for them to be running.
+// An instance is started as soon as its dependencies meet their conditions, see DependsOn,
+// so the instances that do not depend on each other are started in parallel.
+// A dependency on an instance that is not in the group is only waited for,
+// and an instance whose dependency in the group failed to start is not started.
+// Nothing is started if the dependencies of the instances make a cycle.
+func (g *Group) Start(ctx context.Context) error {
+	instances := g.nonNilInstances()
+	if cycle := findDependencyCycle(instances); cycle != nil {
+		return ErrDependencyCycle.WithParams(strings.Join(cycle, " -> "))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		errs = g.newErrors(cancel)
+		sem  = make(chan struct{}, g.parallelism)
+		// done is closed once the start of the instance has finished, successfully or not
+		done = make(map[*Instance]chan struct{}, len(instances))
+	)
+	for _, i := range instances {
+		done[i] = make(chan struct{})
+	}
+
+	start := func(i *Instance) {
+		for _, d := range i.execution.dependencies {
+			if ch, ok := done[d.Instance]; ok {
+				select {
+				case <-ch:
+				case <-ctx.Done():
+					errs.skip(i.name, ctx.Err())
+					return
+				}
+				if errs.has(d.Instance.name) {
+					errs.build(i.name, func() error {
+						return ErrDependencyFailed.WithParams(i.name, d.Instance.name)
+					})
+					return
+				}
+			}
+			if err := waitForCondition(ctx, d.Instance, d.Condition); err != nil {
+				errs.build(i.name, func() error {
+					return ErrWaitingForDependency.WithParams(i.name, d.Instance.name, d.Condition.String()).Wrap(err)
+				})
+				return
+			}
+		}
+
+		// the slot is only taken once the dependencies are met, so waiting instances do not block the others
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			errs.skip(i.name, ctx.Err())
+			return
+		}
+		defer func() { <-sem }()
+		if err := i.execution.Start(ctx); err != nil {
+			errs.add(i.name, err)
+		}
+	}
+
+	for _, i := range instances {
+		wg.Add(1)
+		go func(i *Instance) {
+			defer wg.Done()
+			defer close(done[i])
+			start(i)
+		}(i)
+	}
+	wg.Wait()
+	return errs.err("start")
+}
+
+// run runs the operation on all the instances of the group, at most parallelism at the same time.
+// The instances that are not handled yet when the context is cancelled are skipped.
+func (g *Group) run(ctx context.Context, operation string, fn func(context.Context, *Instance) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		errs = g.newErrors(cancel)
+		sem  = make(chan struct{}, g.parallelism)
+	)
+	for _, i := range g.nonNilInstances() {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		// the context is checked again, as select picks any ready case
+		if ctx.Err() != nil {
+			errs.skip(i.name, ctx.Err())
+			continue
+		}
+
+		wg.Add(1)
+		go func(i *Instance) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(ctx, i); err != nil {
+				errs.add(i.name, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	return errs.err(operation)
+}
+
+func (g *Group) nonNilInstances() []*Instance {
+	instances := make([]*Instance, 0, len(g.instances))
+	for _, i := range g.instances {
+		if i != nil {
+			instances = append(instances, i)
+		}
+	}
+	return instances
+}
+
+// groupErrors collects the errors of the instances during an operation of a group
+type groupErrors struct {
+	mu   sync.Mutex
+	errs map[string]error
+	// cancel is called on the first error when the group fails fast, nil otherwise
+	cancel context.CancelFunc
+}
+
+func (g *Group) newErrors(cancel context.CancelFunc) *groupErrors {
+	e := &groupErrors{errs: make(map[string]error)}
+	if g.failFast {
+		e.cancel = cancel
+	}
+	return e
+}
+
+// add records the error of the instance
+func (e *groupErrors) add(name string, err error) {
+	e.build(name, func() error { return err })
+}
+
+// build records the error of the instance returned by fn.
+// The errors of the packages are shared values that WithParams and Wrap modify,
+// so fn is called under the lock and a copy of its error is recorded,
+// which keeps the parameters of this instance when others fail with the same error.
+func (e *groupErrors) build(name string, fn func() error) {
+	e.mu.Lock()
+	e.errs[name] = snapshot(fn())
+	e.mu.Unlock()
+	if e.cancel != nil {
+		e.cancel()
+	}
+}
+
+// skip records that the instance was skipped because of the given context error
+func (e *groupErrors) skip(name string, cause error) {
+	e.build(name, func() error {
+		return ErrGroupInstanceSkipped.WithParams(name).Wrap(cause)
+	})
+}
+
+// snapshot returns a copy of the error if it is one of the shared errors of the packages
+func snapshot(err error) error {
+	if e, ok := err.(*errors.Error); ok {
+		c := *e
+		return &c
+	}
+	return err
+}
+
+func (e *groupErrors) has(name string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, ok := e.errs[name]
+	return ok
+}
+
+// err returns a GroupError with the collected errors, or nil if there are none
+func (e *groupErrors) err(operation string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.errs) == 0 {
+		return nil
+	}
+	return &GroupError{Operation: operation, Errors: e.errs}
+}
+
+// GroupError is returned by the operations of a Group when some of its instances failed or were skipped
+type GroupError struct {
+	// Operation is the name of the operation, e.g. 'start'
+	Operation string
+	// Errors are the errors by instance name
+	Errors map[string]error
+}
+
+var _ error = &GroupError{}
+
+// Failed returns the names of the instances that failed or were skipped, sorted by name
+func (e *GroupError) Failed() []string {
+	names := make([]string, 0, len(e.Errors))
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (e *GroupError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, name := range e.Failed() {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", name, e.Errors[name]))
+	}
+	return fmt.Sprintf("%s failed for %d instances: %s", e.Operation, len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors of the instances, so they can be matched with errors.Is and errors.As
+func (e *GroupError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, name := range e.Failed() {
+		errs = append(errs, e.Errors[name])
+	}
+	return errs
+}
@@ -1,6 +1,8 @@
 package instance
 
 import (
+	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -38,9 +40,14 @@ type Instance struct {
 	state        InstanceState
 	instanceType InstanceType
 
-	kubernetesReplicaSet *appv1.ReplicaSet
+	kubernetesReplicaSet  *appv1.ReplicaSet
+	kubernetesStatefulSet *appv1.StatefulSet
+	kubernetesDaemonSet   *appv1.DaemonSet
 
 	parentInstance *Instance
+
+	stateObservers   []StateObserver
+	stateObserversMu sync.RWMutex
 }
 
 func New(name string, sysDeps *system.SystemDependencies) (*Instance, error) {
@@ -63,7 +70,7 @@ func New(name string, sysDeps *system.SystemDependencies) (*Instance, error) {
 		imagePullPolicy: v1.PullAlways,
 	}
 
-	i.execution = &execution{instance: i}
+	i.execution = &execution{instance: i, replicas: 1}
 	i.resources = &resources{
 		instance:      i,
 		memoryRequest: resource.Quantity{},
@@ -93,6 +100,7 @@ func New(name string, sysDeps *system.SystemDependencies) (*Instance, error) {
 		privileged:      false,
 		capabilitiesAdd: make([]string, 0),
 		policyRules:     make([]rbacv1.PolicyRule, 0),
+		secretEnv:       make(map[string]string),
 	}
 
 	i.sidecars = &sidecars{
@@ -112,7 +120,7 @@ func (i *Instance) SetName(name string) error {
 	if i.SystemDependencies.HasInstanceName(name) {
 		return ErrInstanceNameAlreadyExists.WithParams(name)
 	}
-	i.SystemDependencies.AddInstanceName(name)
+	i.SystemDependencies.AddInstance(name, i)
 
 	if i.name != "" {
 		// Remove the old name from the system dependencies
@@ -127,6 +135,10 @@ func (i *Instance) State() InstanceState {
 	return i.state
 }
 
+func (i *Instance) InstanceType() InstanceType {
+	return i.instanceType
+}
+
 func (i *Instance) SetInstanceType(instanceType InstanceType) {
 	i.instanceType = instanceType
 }
@@ -182,3 +194,96 @@ func (i *Instance) CloneWithName(name string) (*Instance, error) {
 
 	return newInstance, nil
 }
+
+// replicaPod returns the pod of the given replica of the instance, or of the instance of the sidecar.
+// The replicas are numbered from 0, the oldest first, or by their ordinal for a StatefulSet.
+// For a Job, the pods of the attempts are returned, the first attempt first.
+// For a DaemonSet, the pods are sorted by the name of their node.
+func (i *Instance) replicaPod(ctx context.Context, replica int) (*v1.Pod, error) {
+	owner := i.podOwner()
+	pods, err := i.pods(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if owner.execution.workloadKind == WorkloadStatefulSet {
+		for n := range pods {
+			if pods[n].Name == fmt.Sprintf("%s-%d", owner.name, replica) {
+				return &pods[n], nil
+			}
+		}
+		return nil, ErrReplicaNotFound.WithParams(replica, owner.name, len(pods))
+	}
+	if replica < 0 || replica >= len(pods) {
+		return nil, ErrReplicaNotFound.WithParams(replica, owner.name, len(pods))
+	}
+	return &pods[replica], nil
+}
+
+// nodePod returns the pod of the instance, or of the instance of the sidecar, that runs on the given node
+func (i *Instance) nodePod(ctx context.Context, node string) (*v1.Pod, error) {
+	pods, err := i.pods(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for n := range pods {
+		if pods[n].Spec.NodeName == node {
+			return &pods[n], nil
+		}
+	}
+	return nil, ErrNodeNotFound.WithParams(i.podOwner().name, node)
+}
+
+// pods returns the pods of the workload of the instance, or of the instance of the sidecar
+func (i *Instance) pods(ctx context.Context) ([]v1.Pod, error) {
+	owner := i.podOwner()
+	var (
+		pods []v1.Pod
+		err  error
+	)
+	switch owner.execution.workloadKind {
+	case WorkloadStatefulSet:
+		pods, err = i.K8sClient.ListPodsFromStatefulSet(ctx, owner.name)
+	case WorkloadJob:
+		pods, err = i.K8sClient.ListPodsFromJob(ctx, owner.name)
+	case WorkloadDaemonSet:
+		pods, err = i.K8sClient.ListPodsFromDaemonSet(ctx, owner.name)
+	default:
+		pods, err = i.K8sClient.ListPodsFromReplicaSet(ctx, owner.name)
+	}
+	if err != nil {
+		return nil, ErrListingReplicaPods.WithParams(owner.name).Wrap(err)
+	}
+	return pods, nil
+}
+
+// firstPod returns the first pod of the instance, or of the instance of the sidecar.
+// For a Job, the pod of the current attempt is returned.
+func (i *Instance) firstPod(ctx context.Context) (*v1.Pod, error) {
+	owner := i.podOwner()
+	switch owner.execution.workloadKind {
+	case WorkloadStatefulSet, WorkloadDaemonSet:
+		return i.replicaPod(ctx, 0)
+	case WorkloadJob:
+		pods, err := i.pods(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(pods) == 0 {
+			return nil, ErrReplicaNotFound.WithParams(0, owner.name, 0)
+		}
+		return &pods[len(pods)-1], nil
+	}
+	pod, err := i.K8sClient.GetFirstPodFromReplicaSet(ctx, owner.name)
+	if err != nil {
+		return nil, ErrGettingPodFromReplicaSet.WithParams(i.name).Wrap(err)
+	}
+	return pod, nil
+}
+
+// podOwner returns the instance whose workload runs the pods, i.e. the parent of a sidecar
+func (i *Instance) podOwner() *Instance {
+	if i.sidecars.isSidecar && i.parentInstance != nil {
+		return i.parentInstance
+	}
+	return i
+}
@@ -0,0 +1,880 @@
+package instance_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/celestiaorg/knuu/pkg/instance"
+	"github.com/celestiaorg/knuu/pkg/k8s/fake"
+	"github.com/celestiaorg/knuu/pkg/system"
+)
+
+// newTestSystemDependencies returns the dependencies of the instances of the scope, running on a fake client
+func newTestSystemDependencies(t *testing.T, scope string) (*fake.Client, *system.SystemDependencies) {
+	t.Helper()
+	client, err := fake.NewClient(context.Background(), scope, nil)
+	require.NoError(t, err)
+	return client, &system.SystemDependencies{
+		K8sClient: client,
+		Logger:    logrus.New(),
+		Scope:     scope,
+	}
+}
+
+func TestReplicas(t *testing.T) {
+	ctx := context.Background()
+	client, sysDeps := newTestSystemDependencies(t, "replicas-test")
+	client.HandleExec(func(e fake.Exec) (string, error) {
+		return e.Pod, nil
+	})
+
+	light, err := instance.New("light", sysDeps)
+	require.NoError(t, err)
+	require.NoError(t, light.Build().SetImage(ctx, "alpine:latest"))
+	require.NoError(t, light.Build().Commit(ctx))
+	assert.ErrorIs(t, light.Execution().SetReplicas(0), instance.ErrInvalidReplicas)
+	require.NoError(t, light.Execution().SetReplicas(3))
+	require.NoError(t, light.Execution().Start(ctx))
+	assert.ErrorIs(t, light.Execution().SetReplicas(2), instance.ErrSettingReplicasNotAllowed)
+
+	pods := make(map[string]bool)
+	ips := make(map[string]bool)
+	for replica := 0; replica < 3; replica++ {
+		pod, err := light.Execution().ExecuteCommandOnReplica(ctx, replica, "hostname")
+		require.NoError(t, err)
+		pods[pod] = true
+
+		ip, err := light.Network().GetReplicaIP(ctx, replica)
+		require.NoError(t, err)
+		ips[ip] = true
+
+		logs, err := light.Monitoring().ReplicaLogs(ctx, replica)
+		require.NoError(t, err)
+		require.NoError(t, logs.Close())
+	}
+	assert.Len(t, pods, 3)
+	assert.Len(t, ips, 3)
+
+	_, err = light.Execution().ExecuteCommandOnReplica(ctx, 3, "hostname")
+	assert.ErrorIs(t, err, instance.ErrReplicaNotFound)
+
+	require.NoError(t, light.Execution().Scale(ctx, 1))
+	assert.Equal(t, 1, light.Execution().Replicas())
+	require.NoError(t, light.Execution().WaitInstanceIsRunning(ctx))
+	first, err := light.Execution().ExecuteCommandOnReplica(ctx, 0, "hostname")
+	require.NoError(t, err)
+	assert.True(t, pods[first], "the oldest replica is kept when scaling down")
+	_, err = light.Execution().ExecuteCommandOnReplica(ctx, 1, "hostname")
+	assert.ErrorIs(t, err, instance.ErrReplicaNotFound)
+}
+
+func TestReplicasWithVolumes(t *testing.T) {
+	ctx := context.Background()
+	_, sysDeps := newTestSystemDependencies(t, "replica-volumes-test")
+	newInstance := func(name string, accessMode v1.PersistentVolumeAccessMode) *instance.Instance {
+		inst, err := instance.New(name, sysDeps)
+		require.NoError(t, err)
+		require.NoError(t, inst.Build().SetImage(ctx, "alpine:latest"))
+		require.NoError(t, inst.Build().Commit(ctx))
+		require.NoError(t, inst.Storage().AddNamedVolume("data", "/data", resource.MustParse("1Gi"), instance.VolumeOptions{AccessMode: accessMode}))
+		return inst
+	}
+
+	// the replicas of a ReplicaSet would share the claim, which only one node can mount
+	validator := newInstance("validator", "")
+	assert.ErrorIs(t, validator.Execution().SetReplicas(2), instance.ErrReplicasShareVolume)
+	require.NoError(t, validator.Execution().SetWorkloadKind(instance.WorkloadStatefulSet))
+	require.NoError(t, validator.Execution().SetReplicas(2))
+
+	shared := newInstance("shared", v1.ReadWriteMany)
+	require.NoError(t, shared.Execution().SetReplicas(2))
+
+	// the volume can be added after the replicas are set
+	bridge, err := instance.New("bridge", sysDeps)
+	require.NoError(t, err)
+	require.NoError(t, bridge.Build().SetImage(ctx, "alpine:latest"))
+	require.NoError(t, bridge.Build().Commit(ctx))
+	require.NoError(t, bridge.Execution().SetReplicas(2))
+	require.NoError(t, bridge.Storage().AddVolume("/data", resource.MustParse("1Gi")))
+	assert.ErrorIs(t, bridge.Execution().Start(ctx), instance.ErrReplicasShareVolume)
+}
+
+func TestStatefulSet(t *testing.T) {
+	ctx := context.Background()
+	client, sysDeps := newTestSystemDependencies(t, "statefulset-test")
+	client.HandleExec(func(e fake.Exec) (string, error) {
+		return e.Pod, nil
+	})
+
+	validator, err := instance.New("validator", sysDeps)
+	require.NoError(t, err)
+	require.NoError(t, validator.Build().SetImage(ctx, "alpine:latest"))
+	require.NoError(t, validator.Storage().AddVolume("/data", resource.MustParse("1Gi")))
+	require.NoError(t, validator.Execution().SetWorkloadKind(instance.WorkloadStatefulSet))
+	require.NoError(t, validator.Execution().SetReplicas(2))
+	require.NoError(t, validator.Build().Commit(ctx))
+	require.NoError(t, validator.Execution().Start(ctx))
+	assert.ErrorIs(t, validator.Execution().SetWorkloadKind(instance.WorkloadReplicaSet), instance.ErrSettingWorkloadKindNotAllowed)
+
+	for replica := 0; replica < 2; replica++ {
+		pod, err := validator.Execution().ExecuteCommandOnReplica(ctx, replica, "hostname")
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("validator-%d", replica), pod)
+	}
+	hostname, err := validator.Network().GetReplicaHostname(1)
+	require.NoError(t, err)
+	assert.Equal(t, "validator-1.validator-headless", hostname)
+	_, err = validator.Network().GetReplicaHostname(2)
+	assert.ErrorIs(t, err, instance.ErrReplicaNotFound)
+
+	svc, err := client.GetService(ctx, "validator-headless")
+	require.NoError(t, err)
+	assert.Equal(t, v1.ClusterIPNone, svc.Spec.ClusterIP)
+
+	claims := func() []string {
+		pvcs, err := client.ListPersistentVolumeClaims(ctx, map[string]string{"knuu.sh/name": "validator"})
+		require.NoError(t, err)
+		names := make([]string, 0, len(pvcs))
+		for _, pvc := range pvcs {
+			names = append(names, pvc.Name)
+		}
+		return names
+	}
+	assert.ElementsMatch(t, []string{"validator-volume-0-validator-0", "validator-volume-0-validator-1"}, claims())
+
+	// the claims of the replicas are kept while the instance is stopped, and reused once it is started again
+	require.NoError(t, validator.Execution().Stop(ctx))
+	assert.ElementsMatch(t, []string{"validator-volume-0-validator-0", "validator-volume-0-validator-1"}, claims())
+	require.NoError(t, validator.Execution().Start(ctx))
+	require.NoError(t, validator.Execution().Scale(ctx, 3))
+	assert.ElementsMatch(t, []string{"validator-volume-0-validator-0", "validator-volume-0-validator-1", "validator-volume-0-validator-2"}, claims())
+	require.NoError(t, validator.Execution().Scale(ctx, 1))
+	assert.Len(t, claims(), 3)
+
+	require.NoError(t, validator.Execution().Destroy(ctx))
+	assert.Empty(t, claims())
+	_, err = client.GetService(ctx, "validator-headless")
+	assert.True(t, apierrs.IsNotFound(err))
+}
+
+func TestVolumes(t *testing.T) {
+	ctx := context.Background()
+	client, sysDeps := newTestSystemDependencies(t, "volumes-test")
+	validator, err := instance.New("validator", sysDeps)
+	require.NoError(t, err)
+	require.NoError(t, validator.Build().SetImage(ctx, "alpine:latest"))
+
+	require.NoError(t, validator.Storage().AddVolume("/data", resource.MustParse("1Gi")))
+	require.NoError(t, validator.Storage().AddVolumeWithOwner("/keys", resource.MustParse("1Mi"), 10001))
+	require.NoError(t, validator.Storage().AddNamedVolume("shared", "/shared", resource.MustParse("1Gi"), instance.VolumeOptions{
+		StorageClass: "nfs",
+		AccessMode:   v1.ReadWriteMany,
+	}))
+	assert.ErrorIs(t, validator.Storage().AddNamedVolume("shared", "/other", resource.MustParse("1Gi"), instance.VolumeOptions{}), instance.ErrVolumeAlreadyExists)
+	assert.ErrorIs(t, validator.Storage().AddNamedVolume("other", "/data", resource.MustParse("1Gi"), instance.VolumeOptions{}), instance.ErrVolumePathAlreadyUsed)
+	assert.ErrorIs(t, validator.Storage().AddNamedVolume("Other", "/other", resource.MustParse("1Gi"), instance.VolumeOptions{}), instance.ErrInvalidVolumeName)
+	assert.ErrorIs(t, validator.Storage().AddNamedVolume("config", "/other", resource.MustParse("1Gi"), instance.VolumeOptions{}), instance.ErrInvalidVolumeName)
+	assert.ErrorIs(t, validator.Storage().AddNamedVolume("config-1", "/other", resource.MustParse("1Gi"), instance.VolumeOptions{}), instance.ErrInvalidVolumeName)
+	assert.ErrorIs(t, validator.Storage().AddNamedVolume("files", "/other", resource.MustParse("1Gi"), instance.VolumeOptions{}), instance.ErrInvalidVolumeName)
+	assert.ErrorIs(t, validator.Storage().AddNamedVolume("other", "/other", resource.MustParse("1Gi"), instance.VolumeOptions{AccessMode: "ReadSometimes"}), instance.ErrInvalidVolumeAccessMode)
+
+	volumes := validator.Storage().Volumes()
+	require.Len(t, volumes, 3)
+	assert.Equal(t, "volume-0", volumes[0].Name)
+	assert.Equal(t, "volume-1", volumes[1].Name)
+	assert.Equal(t, int64(10001), volumes[1].Owner)
+	assert.Equal(t, "shared", volumes[2].Name)
+
+	require.NoError(t, validator.Build().Commit(ctx))
+	require.NoError(t, validator.Execution().Start(ctx))
+	claims := func() []string {
+		pvcs, err := client.ListPersistentVolumeClaims(ctx, map[string]string{"knuu.sh/name": "validator"})
+		require.NoError(t, err)
+		names := make([]string, 0, len(pvcs))
+		for _, pvc := range pvcs {
+			names = append(names, pvc.Name)
+		}
+		return names
+	}
+	assert.ElementsMatch(t, []string{"validator-volume-0", "validator-volume-1", "validator-shared"}, claims())
+
+	require.NoError(t, validator.Execution().Destroy(ctx))
+	assert.Empty(t, claims())
+}
+
+func TestDaemonSet(t *testing.T) {
+	ctx := context.Background()
+	client, sysDeps := newTestSystemDependencies(t, "daemonset-test")
+	for _, node := range []string{"node-b", "node-a"} {
+		_, err := client.FakeClientset().CoreV1().Nodes().Create(ctx, &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: node},
+		}, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	client.HandleExec(func(e fake.Exec) (string, error) {
+		return e.Pod, nil
+	})
+
+	capture, err := instance.New("capture", sysDeps)
+	require.NoError(t, err)
+	require.NoError(t, capture.Build().SetImage(ctx, "alpine:latest"))
+	require.NoError(t, capture.Storage().AddVolume("/captures", resource.MustParse("1Gi")))
+	require.NoError(t, capture.Execution().SetWorkloadKind(instance.WorkloadDaemonSet))
+	require.NoError(t, capture.Build().Commit(ctx))
+	require.NoError(t, capture.Execution().Start(ctx))
+
+	nodes, err := capture.Execution().Nodes(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"node-a", "node-b"}, nodes)
+
+	pods := make(map[string]bool)
+	for _, node := range nodes {
+		pod, err := capture.Execution().ExecuteCommandOnNode(ctx, node, "hostname")
+		require.NoError(t, err)
+		pods[pod] = true
+
+		logs, err := capture.Monitoring().NodeLogs(ctx, node)
+		require.NoError(t, err)
+		require.NoError(t, logs.Close())
+	}
+	assert.Len(t, pods, 2)
+	_, err = capture.Execution().ExecuteCommandOnNode(ctx, "node-c", "hostname")
+	assert.ErrorIs(t, err, instance.ErrNodeNotFound)
+	assert.ErrorIs(t, capture.Execution().Scale(ctx, 3), instance.ErrScalingDaemonSetNotAllowed)
+
+	// the volumes are node-local, no claim is made for them
+	pvcs, err := client.ListPersistentVolumeClaims(ctx, map[string]string{"knuu.sh/name": "capture"})
+	require.NoError(t, err)
+	assert.Empty(t, pvcs)
+
+	require.NoError(t, capture.Execution().Destroy(ctx))
+	exists, err := client.DaemonSetExists(ctx, "capture")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	podList, err := client.ListPods(ctx, map[string]string{"knuu.sh/name": "capture"})
+	require.NoError(t, err)
+	assert.Empty(t, podList)
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	client, sysDeps := newTestSystemDependencies(t, "run-test")
+	client.SetExecOutput("genesis --chain-id test", "genesis written")
+	client.SetExecOutput("cat /etc/motd", "welcome")
+	client.HandleExec(func(e fake.Exec) (string, error) {
+		return "", errors.New("migration failed")
+	})
+
+	newJob := func(name string, command ...string) *instance.Instance {
+		ins, err := instance.New(name, sysDeps)
+		require.NoError(t, err)
+		require.NoError(t, ins.Build().SetImage(ctx, "alpine:latest"))
+		require.NoError(t, ins.Build().SetStartCommand(command...))
+		require.NoError(t, ins.Execution().SetWorkloadKind(instance.WorkloadJob))
+		require.NoError(t, ins.Build().Commit(ctx))
+		return ins
+	}
+
+	genesis := newJob("genesis", "genesis", "--chain-id", "test")
+	assert.ErrorIs(t, genesis.Execution().Start(ctx), instance.ErrStartingJobNotAllowed)
+	result, err := genesis.Execution().Run(ctx)
+	require.NoError(t, err)
+	assert.True(t, result.Succeeded)
+	assert.Equal(t, int32(0), result.ExitCode)
+	assert.Equal(t, "genesis written", result.Logs)
+	assert.Equal(t, 1, result.Attempts)
+	assert.Equal(t, instance.StateStopped, genesis.State())
+
+	// a stopped job can be run again
+	_, err = genesis.Execution().Run(ctx)
+	require.NoError(t, err)
+	require.NoError(t, genesis.Execution().Destroy(ctx))
+
+	migration := newJob("migration", "migrate")
+	require.NoError(t, migration.Execution().SetBackoffLimit(2))
+	result, err = migration.Execution().Run(ctx)
+	require.NoError(t, err)
+	assert.False(t, result.Succeeded)
+	assert.Equal(t, int32(1), result.ExitCode)
+	assert.Equal(t, "migration failed", result.Message)
+	assert.Equal(t, 3, result.Attempts)
+
+	replicaSet, err := instance.New("server", sysDeps)
+	require.NoError(t, err)
+	require.NoError(t, replicaSet.Build().SetImage(ctx, "alpine:latest"))
+	require.NoError(t, replicaSet.Build().Commit(ctx))
+	_, err = replicaSet.Execution().Run(ctx)
+	assert.ErrorIs(t, err, instance.ErrRunningNotAllowedForWorkloadKind)
+
+	content, err := replicaSet.Storage().GetFileBytes(ctx, "/etc/motd")
+	require.NoError(t, err)
+	assert.Equal(t, "welcome", string(content))
+}
+
+func TestGroup(t *testing.T) {
+	ctx := context.Background()
+	client, sysDeps := newTestSystemDependencies(t, "group-test")
+	// the ReplicaSet of node-3 can not be created
+	client.FakeClientset().PrependReactor("create", "replicasets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj := action.(k8stesting.CreateAction).GetObject().(metav1.Object)
+		if obj.GetName() == "node-3" {
+			return true, nil, errors.New("quota exceeded")
+		}
+		return false, nil, nil
+	})
+
+	group := instance.NewGroup()
+	for n := 0; n < 5; n++ {
+		ins, err := instance.New(fmt.Sprintf("node-%d", n), sysDeps)
+		require.NoError(t, err)
+		require.NoError(t, ins.Build().SetImage(ctx, "alpine:latest"))
+		group.Add(ins)
+	}
+	assert.ErrorIs(t, group.SetParallelism(0), instance.ErrInvalidParallelism)
+	require.NoError(t, group.SetParallelism(2))
+
+	require.NoError(t, group.Commit(ctx))
+	err := group.StartAsync(ctx)
+	var groupErr *instance.GroupError
+	require.ErrorAs(t, err, &groupErr)
+	assert.Equal(t, "start", groupErr.Operation)
+	assert.Equal(t, []string{"node-3"}, groupErr.Failed())
+	assert.ErrorIs(t, err, instance.ErrDeployingPodForInstance)
+	for _, ins := range group.Instances() {
+		if ins.Name() == "node-3" {
+			assert.Equal(t, instance.StateCommitted, ins.State())
+			continue
+		}
+		assert.Equal(t, instance.StateStarted, ins.State())
+	}
+
+	started := instance.NewGroup()
+	for _, ins := range group.Instances() {
+		if ins.IsState(instance.StateStarted) {
+			started.Add(ins)
+		}
+	}
+	require.NoError(t, started.WaitRunning(ctx))
+	require.NoError(t, started.Stop(ctx))
+	require.NoError(t, started.Destroy(ctx))
+	for _, ins := range started.Instances() {
+		assert.Equal(t, instance.StateDestroyed, ins.State())
+	}
+
+	// with fail fast, the instances after the failing one are skipped
+	failing := instance.NewGroup(group.Instances()[3])
+	for n := 5; n < 7; n++ {
+		ins, err := instance.New(fmt.Sprintf("node-%d", n), sysDeps)
+		require.NoError(t, err)
+		require.NoError(t, ins.Build().SetImage(ctx, "alpine:latest"))
+		require.NoError(t, ins.Build().Commit(ctx))
+		failing.Add(ins)
+	}
+	failing.SetFailFast(true)
+	require.NoError(t, failing.SetParallelism(1))
+	err = failing.StartAsync(ctx)
+	require.ErrorAs(t, err, &groupErr)
+	assert.Equal(t, []string{"node-3", "node-5", "node-6"}, groupErr.Failed())
+	for _, ins := range failing.Instances()[1:] {
+		assert.ErrorIs(t, groupErr.Errors[ins.Name()], instance.ErrGroupInstanceSkipped)
+		// each skipped instance keeps its own name, although they are skipped with the same error
+		assert.Contains(t, groupErr.Errors[ins.Name()].Error(), "'"+ins.Name()+"'")
+		assert.Equal(t, instance.StateCommitted, ins.State())
+	}
+}
+
+func TestDependsOn(t *testing.T) {
+	ctx := context.Background()
+	client, sysDeps := newTestSystemDependencies(t, "depends-on-test")
+	client.SetExecOutput("/bin/sh -c curl -sf localhost:26657/status", "")
+	// the ReplicaSet of broken can not be created
+	client.FakeClientset().PrependReactor("create", "replicasets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj := action.(k8stesting.CreateAction).GetObject().(metav1.Object)
+		if obj.GetName() == "broken" {
+			return true, nil, errors.New("quota exceeded")
+		}
+		return false, nil, nil
+	})
+
+	instances := make(map[string]*instance.Instance)
+	for _, name := range []string{"core", "bridge", "light", "broken", "orphan"} {
+		ins, err := instance.New(name, sysDeps)
+		require.NoError(t, err)
+		require.NoError(t, ins.Build().SetImage(ctx, "alpine:latest"))
+		require.NoError(t, ins.Build().Commit(ctx))
+		instances[name] = ins
+	}
+	core, bridge, light := instances["core"], instances["bridge"], instances["light"]
+
+	rpcReady := instance.ConditionProbe(&v1.Probe{
+		ProbeHandler: v1.ProbeHandler{
+			Exec: &v1.ExecAction{Command: []string{"curl", "-sf", "localhost:26657/status"}},
+		},
+	})
+	require.NoError(t, bridge.Execution().DependsOn(core, rpcReady))
+	var checked bool
+	require.NoError(t, light.Execution().DependsOn(bridge, instance.ConditionFunc("checked", func(ctx context.Context, i *instance.Instance) (bool, error) {
+		checked = true
+		return i.IsState(instance.StateStarted), nil
+	})))
+	require.NoError(t, instances["orphan"].Execution().DependsOn(instances["broken"], nil))
+
+	assert.ErrorIs(t, core.Execution().DependsOn(light, nil), instance.ErrDependencyCycle)
+	assert.ErrorIs(t, core.Execution().DependsOn(core, nil), instance.ErrDependencyCycle)
+	assert.ErrorIs(t, core.Execution().DependsOn(nil, nil), instance.ErrDependencyIsNil)
+	assert.ErrorIs(t, core.Execution().DependsOn(bridge, instance.ConditionProbe(&v1.Probe{})), instance.ErrInvalidConditionProbe)
+	assert.Empty(t, core.Execution().Dependencies())
+
+	group := instance.NewGroup(light, bridge, core, instances["broken"], instances["orphan"])
+	require.NoError(t, group.SetParallelism(1))
+	err := group.Start(ctx)
+	var groupErr *instance.GroupError
+	require.ErrorAs(t, err, &groupErr)
+	assert.Equal(t, []string{"broken", "orphan"}, groupErr.Failed())
+	assert.ErrorIs(t, groupErr.Errors["orphan"], instance.ErrDependencyFailed)
+	assert.Equal(t, instance.StateCommitted, instances["orphan"].State())
+
+	assert.True(t, checked)
+	for _, ins := range []*instance.Instance{core, bridge, light} {
+		assert.Equal(t, instance.StateStarted, ins.State())
+	}
+	require.NoError(t, light.Execution().WaitForDependencies(ctx))
+
+	// a tcp probe connects to the port from inside the pod, not through a port-forward
+	tcpReady := instance.ConditionProbe(&v1.Probe{
+		ProbeHandler: v1.ProbeHandler{
+			TCPSocket: &v1.TCPSocketAction{Port: intstr.FromInt32(26657)},
+		},
+	})
+	listening := false
+	client.HandleExec(func(e fake.Exec) (string, error) {
+		if strings.Contains(e.Script(), "nc -z") && !listening {
+			return "", errors.New("connection refused")
+		}
+		return "", nil
+	})
+	met, err := tcpReady.Met(ctx, core)
+	assert.Error(t, err)
+	assert.False(t, met)
+	listening = true
+	met, err = tcpReady.Met(ctx, core)
+	require.NoError(t, err)
+	assert.True(t, met)
+
+	// a dependency that is never met times out with the context
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	never := instance.ConditionFunc("never", func(context.Context, *instance.Instance) (bool, error) {
+		return false, nil
+	})
+	require.NoError(t, instances["orphan"].Execution().DependsOn(core, never))
+	assert.ErrorIs(t, instances["orphan"].Execution().WaitForDependencies(timeoutCtx), instance.ErrWaitingForDependency)
+}
+
+func TestExportVolume(t *testing.T) {
+	ctx := context.Background()
+	client, sysDeps := newTestSystemDependencies(t, "export-volume-test")
+	client.SetExecOutput("tar -cf - -C /data .", "archive")
+
+	validator, err := instance.New("validator", sysDeps)
+	require.NoError(t, err)
+	require.NoError(t, validator.Build().SetImage(ctx, "alpine:latest"))
+	require.NoError(t, validator.Storage().AddVolume("/data", resource.MustParse("1Gi")))
+	require.NoError(t, validator.Build().Commit(ctx))
+
+	var buf bytes.Buffer
+	assert.ErrorIs(t, validator.Storage().ExportVolume(ctx, "/data", &buf), instance.ErrExportingVolumeNotAllowed)
+
+	require.NoError(t, validator.Execution().Start(ctx))
+	assert.ErrorIs(t, validator.Storage().ExportVolume(ctx, "/other", &buf), instance.ErrVolumeNotFound)
+	require.NoError(t, validator.Storage().ExportVolume(ctx, "/data", &buf))
+	assert.Equal(t, "archive", buf.String())
+	execs := client.Execs()
+	require.NotEmpty(t, execs)
+	assert.Equal(t, "validator", execs[len(execs)-1].Container)
+
+	// the volume of a stopped instance is read from a helper pod that mounts its claim
+	require.NoError(t, validator.Execution().Stop(ctx))
+	buf.Reset()
+	require.NoError(t, validator.Storage().ExportVolume(ctx, "/data", &buf))
+	assert.Equal(t, "archive", buf.String())
+	execs = client.Execs()
+	helper := execs[len(execs)-1].Pod
+	assert.Contains(t, helper, "validator-volume-")
+	_, err = client.FakeClientset().CoreV1().Pods(client.Namespace()).Get(ctx, helper, metav1.GetOptions{})
+	assert.True(t, apierrs.IsNotFound(err))
+
+	_, err = validator.Storage().ExportVolumeToMinio(ctx, "/data", "validator-data.tar")
+	assert.ErrorIs(t, err, instance.ErrMinioNotInitialized)
+}
+
+func TestSeedVolume(t *testing.T) {
+	ctx := context.Background()
+	_, sysDeps := newTestSystemDependencies(t, "seed-volume-test")
+	validator, err := instance.New("validator", sysDeps)
+	require.NoError(t, err)
+	require.NoError(t, validator.Build().SetImage(ctx, "alpine:latest"))
+	require.NoError(t, validator.Storage().AddVolume("/data", resource.MustParse("1Gi")))
+
+	dir := t.TempDir()
+	archive := dir + "/snapshot.tar.gz"
+	require.NoError(t, os.WriteFile(archive, []byte("archive"), 0o644))
+
+	storage := validator.Storage()
+	assert.ErrorIs(t, storage.SeedVolume("/other", instance.SeedFromDir(dir)), instance.ErrVolumeNotFound)
+	assert.ErrorIs(t, storage.SeedVolume("/data", instance.SeedFromDir(dir+"/missing")), instance.ErrInvalidVolumeSeed)
+	err = storage.SeedVolume("/data", instance.SeedFromDir(archive))
+	assert.ErrorIs(t, err, instance.ErrInvalidVolumeSeed)
+	assert.ErrorContains(t, err, "is not a directory")
+	assert.ErrorIs(t, storage.SeedVolume("/data", instance.SeedFromArchive(dir)), instance.ErrInvalidVolumeSeed)
+	assert.ErrorIs(t, storage.SeedVolume("/data", instance.SeedFromMinio("", "snapshot.tar")), instance.ErrInvalidVolumeSeed)
+	assert.ErrorIs(t, storage.SeedVolume("/data", instance.SeedFromMinio("snapshots", "snapshot.zip")), instance.ErrInvalidVolumeSeed)
+	assert.ErrorIs(t, storage.SeedVolume("/data", instance.VolumeSeed{}), instance.ErrInvalidVolumeSeed)
+	// the seeds are uploaded to Minio
+	assert.ErrorIs(t, storage.SeedVolume("/data", instance.SeedFromArchive(archive)), instance.ErrMinioNotInitialized)
+
+	require.NoError(t, validator.Build().Commit(ctx))
+	require.NoError(t, validator.Execution().Start(ctx))
+	assert.ErrorIs(t, storage.SeedVolume("/data", instance.SeedFromDir(dir)), instance.ErrSeedingVolumeNotAllowed)
+}
+
+func TestCopy(t *testing.T) {
+	ctx := context.Background()
+	client, sysDeps := newTestSystemDependencies(t, "copy-test")
+	validator, err := instance.New("validator", sysDeps)
+	require.NoError(t, err)
+	require.NoError(t, validator.Build().SetImage(ctx, "alpine:latest"))
+	require.NoError(t, validator.Build().Commit(ctx))
+
+	local := t.TempDir()
+	require.NoError(t, os.MkdirAll(local+"/config", 0o755))
+	require.NoError(t, os.WriteFile(local+"/config/genesis.json", []byte("{}"), 0o644))
+	assert.ErrorIs(t, validator.Storage().CopyTo(ctx, local+"/config", "/home/celestia/config"), instance.ErrCopyingNotAllowed)
+	require.NoError(t, validator.Execution().Start(ctx))
+
+	// the directory is sent as a tar archive to the stdin of tar
+	require.NoError(t, validator.Storage().CopyTo(ctx, local+"/config", "/home/celestia/config"))
+	execs := client.Execs()
+	upload := execs[len(execs)-1]
+	assert.Equal(t, []string{"sh", "-c", `mkdir -p -- "$1" && tar -xmf - -C "$1"`, "sh", "/home/celestia"}, upload.Command)
+	var names []string
+	tr := tar.NewReader(bytes.NewReader(upload.Stdin))
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, header.Name)
+	}
+	assert.Equal(t, []string{"config/", "config/genesis.json"}, names)
+	assert.ErrorIs(t, validator.Storage().CopyTo(ctx, local+"/missing", "/tmp/missing"), instance.ErrSrcDoesNotExist)
+
+	// the tar archive written by tar is extracted to the local path
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "data/", Typeflag: tar.TypeDir, Mode: 0o755}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "data/block.db", Typeflag: tar.TypeReg, Mode: 0o644, Size: 3}))
+	_, err = tw.Write([]byte{0, 255, 0})
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	client.SetExecOutput("tar -cf - -C /home/celestia data", archive.String())
+
+	require.NoError(t, validator.Storage().CopyFrom(ctx, "/home/celestia/data", local+"/backup/data"))
+	content, err := os.ReadFile(local + "/backup/data/block.db")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 255, 0}, content)
+
+	client.HandleExec(func(e fake.Exec) (string, error) {
+		return "", errors.New("tar: /home/celestia/missing: No such file or directory")
+	})
+	assert.ErrorIs(t, validator.Storage().CopyFrom(ctx, "/home/celestia/missing", local+"/missing"), instance.ErrCopyingFromInstance)
+}
+
+func TestCopyFromInstance(t *testing.T) {
+	ctx := context.Background()
+	client, sysDeps := newTestSystemDependencies(t, "copy-from-instance-test")
+	instances := make(map[string]*instance.Instance)
+	for _, name := range []string{"genesis", "validator-0", "validator-1"} {
+		ins, err := instance.New(name, sysDeps)
+		require.NoError(t, err)
+		require.NoError(t, ins.Build().SetImage(ctx, "alpine:latest"))
+		require.NoError(t, ins.Storage().AddVolume("/home/celestia", resource.MustParse("1Gi")))
+		require.NoError(t, ins.Build().Commit(ctx))
+		instances[name] = ins
+	}
+	genesis, validator0, validator1 := instances["genesis"], instances["validator-0"], instances["validator-1"]
+	assert.ErrorIs(t, validator0.Storage().CopyFromInstance(ctx, genesis, "/genesis.json", "/home/celestia/genesis.json"), instance.ErrCopyingBetweenInstancesNotAllowed)
+	assert.ErrorIs(t, validator0.Storage().CopyFromInstance(ctx, nil, "/genesis.json", "/home/celestia/genesis.json"), instance.ErrCopySourceIsNil)
+
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "genesis.json", Typeflag: tar.TypeReg, Mode: 0o644, Size: 2}))
+	_, err := tw.Write([]byte("{}"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	client.SetExecOutput("tar -cf - -C /tmp genesis.json", archive.String())
+
+	// the archive of the source is renamed and extracted by the targets
+	extracted := func(e fake.Exec) []string {
+		var names []string
+		tr := tar.NewReader(bytes.NewReader(e.Stdin))
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				return names
+			}
+			require.NoError(t, err)
+			names = append(names, header.Name)
+		}
+	}
+	require.NoError(t, instance.NewGroup(genesis, validator0).Start(ctx))
+	require.NoError(t, validator0.Storage().CopyFromInstance(ctx, genesis, "/tmp/genesis.json", "/home/celestia/config/genesis-file.json"))
+	execs := client.Execs()
+	upload := execs[len(execs)-1]
+	extractToConfig := []string{"sh", "-c", `mkdir -p -- "$1" && tar -xmf - -C "$1"`, "sh", "/home/celestia/config"}
+	assert.Equal(t, extractToConfig, upload.Command)
+	assert.Equal(t, "validator-0", upload.Container)
+	assert.Equal(t, []string{"genesis-file.json"}, extracted(upload))
+
+	// a stopped target is written through a helper pod that mounts its volume
+	require.NoError(t, validator1.Execution().Start(ctx))
+	require.NoError(t, validator1.Execution().Stop(ctx))
+	assert.ErrorIs(t, validator1.Storage().CopyFromInstance(ctx, genesis, "/tmp/genesis.json", "/etc/genesis.json"), instance.ErrPathNotInVolume)
+	group := instance.NewGroup(genesis, validator0, validator1)
+	require.NoError(t, group.CopyFromInstance(ctx, genesis, "/tmp/genesis.json", "/home/celestia/config/genesis.json"))
+	var helpers, uploads int
+	for _, e := range client.Execs()[len(execs):] {
+		if slices.Equal(e.Command, extractToConfig) {
+			uploads++
+			assert.Equal(t, []string{"genesis.json"}, extracted(e))
+			if strings.HasPrefix(e.Pod, "validator-1-volume-") {
+				helpers++
+			}
+		}
+	}
+	assert.Equal(t, 2, uploads)
+	assert.Equal(t, 1, helpers)
+
+	client.HandleExec(func(e fake.Exec) (string, error) {
+		return "", errors.New("tar: /tmp/missing: No such file or directory")
+	})
+	err = validator0.Storage().CopyFromInstance(ctx, genesis, "/tmp/missing", "/home/celestia/missing")
+	assert.ErrorIs(t, err, instance.ErrCopyingBetweenInstances)
+	assert.ErrorContains(t, err, "No such file or directory")
+}
+
+func TestLiveFiles(t *testing.T) {
+	ctx := context.Background()
+	client, sysDeps := newTestSystemDependencies(t, "live-files-test")
+	validator, err := instance.New("validator", sysDeps)
+	require.NoError(t, err)
+	require.NoError(t, validator.Build().SetImage(ctx, "alpine:latest"))
+
+	storage := validator.Storage()
+	require.NoError(t, storage.AddLiveFile([]byte("peers = []"), "/etc/app/config.toml", instance.LiveFileOptions{ReloadSignal: "HUP"}))
+	require.NoError(t, storage.AddLiveFile([]byte{0, 255}, "/etc/keys/node.key", instance.LiveFileOptions{Secret: true}))
+	assert.ErrorIs(t, storage.AddLiveFile([]byte("{}"), "/etc/app/config.toml", instance.LiveFileOptions{}), instance.ErrLiveFileAlreadyExists)
+	assert.ErrorIs(t, storage.AddLiveFile([]byte("{}"), "/etc/app/key.json", instance.LiveFileOptions{Secret: true}), instance.ErrLiveFilesMixedInDirectory)
+	assert.ErrorIs(t, storage.AddLiveFile([]byte("{}"), "config.toml", instance.LiveFileOptions{}), instance.ErrInvalidLiveFilePath)
+	assert.ErrorIs(t, storage.AddLiveFile([]byte("{}"), "/config.toml", instance.LiveFileOptions{}), instance.ErrInvalidLiveFilePath)
+	assert.ErrorIs(t, storage.AddLiveFile([]byte{0, 255}, "/etc/app/node.key", instance.LiveFileOptions{}), instance.ErrLiveFileNotUTF8)
+	assert.ErrorIs(t, storage.AddLiveFile([]byte("{}"), "/etc/app/app.json", instance.LiveFileOptions{ReloadSignal: "hup; rm -rf /"}), instance.ErrInvalidReloadSignal)
+
+	require.NoError(t, validator.Build().Commit(ctx))
+	assert.ErrorIs(t, storage.UpdateFile(ctx, "/etc/app/config.toml", []byte("peers = []")), instance.ErrUpdatingFileNotAllowed)
+	require.NoError(t, validator.Execution().Start(ctx))
+
+	// the directories of the files are mounted from their ConfigMap and Secret
+	pods, err := client.Clientset().CoreV1().Pods(client.Namespace()).List(ctx, metav1.ListOptions{LabelSelector: "knuu.sh/name=validator"})
+	require.NoError(t, err)
+	require.Len(t, pods.Items, 1)
+	mounts := make(map[string]string)
+	for _, mount := range pods.Items[0].Spec.Containers[0].VolumeMounts {
+		mounts[mount.MountPath] = mount.Name
+	}
+	assert.Contains(t, mounts, "/etc/app")
+	assert.Contains(t, mounts, "/etc/keys")
+
+	// the update is written to the ConfigMap, and the signal is sent once it appeared in the pod
+	client.SetExecOutput("cat /etc/app/config.toml", "peers = [\"validator-0\"]")
+	require.NoError(t, storage.UpdateFile(ctx, "/etc/app/config.toml", []byte("peers = [\"validator-0\"]")))
+	configMap, err := client.GetConfigMap(ctx, "validator-live-files")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"0": "peers = [\"validator-0\"]"}, configMap.Data)
+	execs := client.Execs()
+	assert.Equal(t, "kill -s HUP 1", execs[len(execs)-1].Script())
+
+	client.SetExecOutput("cat /etc/keys/node.key", string([]byte{1, 254}))
+	require.NoError(t, storage.UpdateFile(ctx, "/etc/keys/node.key", []byte{1, 254}))
+	secret, err := client.GetSecret(ctx, "validator-live-files")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"1": {1, 254}}, secret.Data)
+	assert.ErrorIs(t, storage.UpdateFile(ctx, "/etc/app/other.toml", nil), instance.ErrLiveFileNotFound)
+
+	require.NoError(t, validator.Execution().Destroy(ctx))
+	_, err = client.GetSecret(ctx, "validator-live-files")
+	assert.Error(t, err)
+}
+
+func TestShardedFiles(t *testing.T) {
+	ctx := context.Background()
+	client, sysDeps := newTestSystemDependencies(t, "sharded-files-test")
+	dir := t.TempDir()
+	writeFile := func(name string, size int) string {
+		path := dir + "/" + name
+		require.NoError(t, os.WriteFile(path, bytes.Repeat([]byte("a"), size), 0o644))
+		return path
+	}
+
+	validator, err := instance.New("validator", sysDeps)
+	require.NoError(t, err)
+	require.NoError(t, validator.Build().SetImage(ctx, "alpine:latest"))
+	require.NoError(t, validator.Build().Commit(ctx))
+	require.NoError(t, validator.Storage().AddFile(writeFile("genesis.json", 600<<10), "/etc/app/genesis.json", "0:0"))
+	require.NoError(t, validator.Storage().AddFile(writeFile("addrbook.json", 600<<10), "/etc/app/addrbook.json", "0:0"))
+	require.NoError(t, validator.Storage().AddFile(writeFile("config.toml", 100), "/etc/app/config.toml", "0:0"))
+	require.NoError(t, validator.Execution().Start(ctx))
+
+	// the files are spread over the configmaps, each one staying below the size limit
+	first, err := client.GetConfigMap(ctx, "validator")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"0", "2"}, keysOf(first.Data))
+	second, err := client.GetConfigMap(ctx, "validator-config-1")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"1"}, keysOf(second.Data))
+
+	require.NoError(t, validator.Execution().Destroy(ctx))
+	for _, name := range []string{"validator", "validator-config-1"} {
+		exists, err := client.ConfigMapExists(ctx, name)
+		require.NoError(t, err)
+		assert.False(t, exists, name)
+	}
+
+	// a file larger than a configmap can only be uploaded to Minio
+	snapshot, err := instance.New("snapshot", sysDeps)
+	require.NoError(t, err)
+	require.NoError(t, snapshot.Build().SetImage(ctx, "alpine:latest"))
+	require.NoError(t, snapshot.Build().Commit(ctx))
+	assert.ErrorIs(t, snapshot.Storage().AddFile(writeFile("snapshot.db", 2<<20), "/data/snapshot.db", "0:0"), instance.ErrFileTooLargeForConfigMap)
+}
+
+func keysOf(data map[string]string) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestFileModesAndSymlinks(t *testing.T) {
+	ctx := context.Background()
+	client, sysDeps := newTestSystemDependencies(t, "file-modes-test")
+	local := t.TempDir()
+	require.NoError(t, os.MkdirAll(local+"/bin", 0o755))
+	require.NoError(t, os.WriteFile(local+"/bin/celestia", []byte{0x7f, 'E', 'L', 'F', 0xff}, 0o755))
+	require.NoError(t, os.WriteFile(local+"/config.toml", []byte("peers = []"), 0o600))
+	require.NoError(t, os.Symlink("bin/celestia", local+"/latest"))
+
+	validator, err := instance.New("validator", sysDeps)
+	require.NoError(t, err)
+	require.NoError(t, validator.Build().SetImage(ctx, "alpine:latest"))
+	require.NoError(t, validator.Storage().AddVolume("/data", resource.MustParse("1Gi")))
+	require.NoError(t, validator.Build().Commit(ctx))
+	// the symlinks can only be created in the volumes
+	links := t.TempDir()
+	require.NoError(t, os.Symlink("config.toml", links+"/config.link"))
+	assert.ErrorIs(t, validator.Storage().AddFolder(links, "/etc/app", "10001:10001"), instance.ErrCopyingFolderToInstance)
+	require.NoError(t, validator.Storage().AddFolder(local, "/data/app", "10001:10001"))
+	require.NoError(t, validator.Execution().Start(ctx))
+
+	// the binary content is kept in the binary data of the configmap
+	configMap, err := client.GetConfigMap(ctx, "validator")
+	require.NoError(t, err)
+	var binary, text int
+	for _, content := range configMap.BinaryData {
+		assert.Equal(t, []byte{0x7f, 'E', 'L', 'F', 0xff}, content)
+		binary++
+	}
+	for _, content := range configMap.Data {
+		assert.Equal(t, "peers = []", content)
+		text++
+	}
+	assert.Equal(t, 1, binary)
+	assert.Equal(t, 1, text)
+
+	// the files are staged with their owner and mode, and the symlink is created in the volume
+	pods, err := client.Clientset().CoreV1().Pods(client.Namespace()).List(ctx, metav1.ListOptions{LabelSelector: "knuu.sh/name=validator"})
+	require.NoError(t, err)
+	require.Len(t, pods.Items, 1)
+	initContainers := pods.Items[0].Spec.InitContainers
+	require.Len(t, initContainers, 2)
+	assert.Contains(t, initContainers[0].Command[2], "chmod 755 /files/")
+	assert.Contains(t, initContainers[0].Command[2], "chmod 600 /files/")
+	assert.Contains(t, initContainers[1].Command[2], "ln -sfn bin/celestia /knuu/data/app/latest && chown -h 10001:10001 /knuu/data/app/latest")
+}
+
+func TestSecrets(t *testing.T) {
+	ctx := context.Background()
+	client, sysDeps := newTestSystemDependencies(t, "secrets-test")
+	var logs bytes.Buffer
+	sysDeps.Logger.SetOutput(&logs)
+	sysDeps.Logger.SetLevel(logrus.DebugLevel)
+
+	validator, err := instance.New("validator", sysDeps)
+	require.NoError(t, err)
+	require.NoError(t, validator.Build().SetImage(ctx, "alpine:latest"))
+
+	require.NoError(t, validator.Security().AddSecretEnv("MNEMONIC", "abandon abandon about"))
+	assert.ErrorIs(t, validator.Security().AddSecretEnv("1MNEMONIC", "abandon"), instance.ErrInvalidSecretEnvName)
+	require.NoError(t, validator.Storage().AddSecretFile("/keys/node_key.json", []byte{0, 255}, 0o400))
+	assert.ErrorIs(t, validator.Storage().AddSecretFile("/keys/node_key.json", []byte("{}"), 0), instance.ErrSecretFileAlreadyExists)
+	assert.ErrorIs(t, validator.Storage().AddSecretFile("keys/node_key.json", []byte("{}"), 0), instance.ErrInvalidSecretFilePath)
+
+	require.NoError(t, validator.Build().Commit(ctx))
+	require.NoError(t, validator.Execution().Start(ctx))
+	assert.ErrorIs(t, validator.Security().AddSecretEnv("API_TOKEN", "token"), instance.ErrAddingSecretNotAllowed)
+
+	// the secrets are stored in the labelled secret of the instance, the pod only references them
+	secret, err := client.GetSecret(ctx, "validator-secrets")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"env-MNEMONIC": []byte("abandon abandon about"),
+		"file-0":       {0, 255},
+	}, secret.Data)
+	assert.Equal(t, "validator", secret.Labels["knuu.sh/name"])
+	pods, err := client.Clientset().CoreV1().Pods(client.Namespace()).List(ctx, metav1.ListOptions{LabelSelector: "knuu.sh/name=validator"})
+	require.NoError(t, err)
+	require.Len(t, pods.Items, 1)
+	for _, env := range pods.Items[0].Spec.Containers[0].Env {
+		if env.Name == "MNEMONIC" {
+			assert.Empty(t, env.Value)
+			assert.Equal(t, "env-MNEMONIC", env.ValueFrom.SecretKeyRef.Key)
+		}
+	}
+	assert.NotContains(t, logs.String(), "abandon")
+
+	require.NoError(t, validator.Execution().Destroy(ctx))
+	_, err = client.GetSecret(ctx, "validator-secrets")
+	assert.Error(t, err)
+}
@@ -0,0 +1,181 @@
+package instance
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+)
+
+// runCleanupTimeout is the time given to remove the Job of a run, which is done even if the context of the run is done
+const runCleanupTimeout = time.Minute
+
+// RunResult is the outcome of the run of an instance with the workload kind WorkloadJob
+type RunResult struct {
+	// Succeeded is true if the instance exited with the code 0, possibly after some retries
+	Succeeded bool
+	// ExitCode is the exit code of the instance in its last attempt, -1 if it was stopped before exiting
+	ExitCode int32
+	// Message is the termination message of the last attempt.
+	// It is the end of the logs when the instance failed without writing one.
+	Message string
+	// Logs are the logs of the instance in its last attempt
+	Logs string
+	// Attempts is the number of times the instance has been run, i.e. 1 plus the number of retries
+	Attempts int
+}
+
+// SetBackoffLimit sets the number of times a failed run is retried, 0 by default.
+// The retries are delayed with an exponential backoff by Kubernetes.
+// This function can only be called in the states 'Preparing', 'Committed' and 'Stopped'
+func (e *execution) SetBackoffLimit(retries int) error {
+	if !e.instance.IsInState(StatePreparing, StateCommitted, StateStopped) {
+		return ErrSettingJobOptionsNotAllowed.WithParams(e.instance.state.String())
+	}
+	if retries < 0 {
+		return ErrInvalidBackoffLimit.WithParams(retries)
+	}
+	e.backoffLimit = int32(retries)
+	e.instance.Logger.WithFields(logrus.Fields{
+		"instance":      e.instance.name,
+		"backoff_limit": retries,
+	}).Debug("set backoff limit")
+	return nil
+}
+
+// SetActiveDeadline sets the time a run can take, including its retries, after which it is stopped and marked as failed.
+// There is no limit by default.
+// This function can only be called in the states 'Preparing', 'Committed' and 'Stopped'
+func (e *execution) SetActiveDeadline(deadline time.Duration) error {
+	if !e.instance.IsInState(StatePreparing, StateCommitted, StateStopped) {
+		return ErrSettingJobOptionsNotAllowed.WithParams(e.instance.state.String())
+	}
+	if deadline < time.Second {
+		return ErrInvalidActiveDeadline.WithParams(deadline.String())
+	}
+	e.activeDeadline = deadline
+	e.instance.Logger.WithFields(logrus.Fields{
+		"instance":        e.instance.name,
+		"active_deadline": deadline.String(),
+	}).Debug("set active deadline")
+	return nil
+}
+
+// Run runs the instance to completion and returns the outcome of its last attempt.
+// A run that fails is not an error: the exit code and the logs are returned, and Succeeded is false.
+// The instance is 'Started' while it runs, and 'Stopped' once it has finished, so it can be run again.
+// It can only be used with the workload kind WorkloadJob, and an instance without sidecars,
+// as the sidecars would keep the pod running.
+// This function can only be called in the states 'Committed' and 'Stopped'
+func (e *execution) Run(ctx context.Context) (*RunResult, error) {
+	if e.workloadKind != WorkloadJob {
+		return nil, ErrRunningNotAllowedForWorkloadKind.WithParams(e.instance.name, e.workloadKind.String())
+	}
+	if !e.instance.IsInState(StateCommitted, StateStopped) {
+		return nil, ErrRunningNotAllowed.WithParams(e.instance.name, e.instance.state.String())
+	}
+	if len(e.instance.sidecars.sidecars) != 0 {
+		return nil, ErrRunningWithSidecarsNotAllowed.WithParams(e.instance.name)
+	}
+	if e.instance.build.imageName == "" {
+		return nil, ErrImageNotSetForInstance.WithParams(e.instance.name)
+	}
+
+	if e.instance.state == StateCommitted {
+		if err := e.deployResourcesForCommittedState(ctx); err != nil {
+			return nil, ErrDeployingResourcesForInstance.WithParams(e.instance.name).Wrap(err)
+		}
+	}
+	if err := e.deployPod(ctx); err != nil {
+		return nil, ErrDeployingPodForInstance.WithParams(e.instance.name).Wrap(err)
+	}
+	e.instance.SetState(StateStarted)
+
+	result, err := e.waitForRun(ctx)
+	// the job is removed even if the run could not be awaited, so the instance can be run again.
+	// The context of the run may be done, so the removal gets its own.
+	cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), runCleanupTimeout)
+	defer cancel()
+	if dErr := e.destroyPod(cleanupCtx); dErr != nil && err == nil {
+		err = ErrDestroyingPod.WithParams(e.instance.name).Wrap(dErr)
+	}
+	e.instance.SetState(StateStopped)
+	if err != nil {
+		return nil, err
+	}
+
+	e.instance.Logger.WithFields(logrus.Fields{
+		"instance":  e.instance.name,
+		"succeeded": result.Succeeded,
+		"exit_code": result.ExitCode,
+		"attempts":  result.Attempts,
+	}).Debug("instance run finished")
+	return result, nil
+}
+
+// waitForRun waits for the Job of the instance to finish and returns the outcome of its last attempt
+func (e *execution) waitForRun(ctx context.Context) (*RunResult, error) {
+	job, err := e.instance.K8sClient.WaitForJob(ctx, e.instance.name)
+	if err != nil {
+		return nil, ErrWaitingForRun.WithParams(e.instance.name).Wrap(err)
+	}
+
+	result := &RunResult{
+		Succeeded: job.Status.Succeeded > 0,
+		ExitCode:  -1,
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == v1.ConditionTrue {
+			result.Message = condition.Message
+		}
+	}
+
+	pods, err := e.instance.K8sClient.ListPodsFromJob(ctx, e.instance.name)
+	if err != nil {
+		return nil, ErrGettingRunResult.WithParams(e.instance.name).Wrap(err)
+	}
+	result.Attempts = len(pods)
+	if len(pods) == 0 {
+		return result, nil
+	}
+
+	last := pods[len(pods)-1]
+	for _, status := range last.Status.ContainerStatuses {
+		if status.Name != e.instance.name || status.State.Terminated == nil {
+			continue
+		}
+		result.ExitCode = status.State.Terminated.ExitCode
+		if status.State.Terminated.Message != "" {
+			result.Message = status.State.Terminated.Message
+		}
+	}
+
+	logs, err := e.instance.K8sClient.GetPodLogStream(ctx, last.Name, v1.PodLogOptions{Container: e.instance.name})
+	if err != nil {
+		return nil, ErrGettingRunResult.WithParams(e.instance.name).Wrap(err)
+	}
+	defer logs.Close()
+	content, err := io.ReadAll(logs)
+	if err != nil {
+		return nil, ErrGettingRunResult.WithParams(e.instance.name).Wrap(err)
+	}
+	result.Logs = string(content)
+	return result, nil
+}
+
+// prepareJobConfig prepares the Job config for the instance
+func (e *execution) prepareJobConfig() k8s.JobConfig {
+	return k8s.JobConfig{
+		Namespace:      e.instance.K8sClient.Namespace(),
+		Name:           e.instance.name,
+		Labels:         e.Labels(),
+		BackoffLimit:   e.backoffLimit,
+		ActiveDeadline: e.activeDeadline,
+		PodConfig:      e.preparePodConfig(),
+	}
+}
@@ -0,0 +1,117 @@
+package instance
+
+import (
+	"context"
+	"sort"
+
+	"github.com/celestiaorg/knuu/pkg/system"
+)
+
+// Filter selects instances by state, type and labels.
+// An empty field matches all the instances, a filled one matches the instances
+// that are in one of the states, of one of the types and that have all the labels.
+type Filter struct {
+	States []InstanceState
+	Types  []InstanceType
+	Labels map[string]string
+}
+
+// Matches reports whether the instance is selected by the filter
+func (f Filter) Matches(i *Instance) bool {
+	if len(f.States) != 0 && !i.IsInState(f.States...) {
+		return false
+	}
+	if len(f.Types) != 0 && !containsType(f.Types, i.instanceType) {
+		return false
+	}
+	for k, v := range f.Labels {
+		if label, ok := i.execution.labels[k]; !ok || label != v {
+			return false
+		}
+	}
+	return true
+}
+
+func containsType(types []InstanceType, t InstanceType) bool {
+	for _, typ := range types {
+		if typ == t {
+			return true
+		}
+	}
+	return false
+}
+
+// List is a list of instances on which operations can be run in bulk.
+// The operations run in the order of the list and stop at the first error.
+type List []*Instance
+
+// Instances returns the instances registered in the system dependencies, sorted by name.
+// Every instance created with New is registered, including the sidecars and the destroyed ones.
+func Instances(sysDeps *system.SystemDependencies) List {
+	var list List
+	sysDeps.RangeInstances(func(_ string, value interface{}) bool {
+		// names reserved by other components, e.g. preloaders, have no instance
+		if i, ok := value.(*Instance); ok {
+			list = append(list, i)
+		}
+		return true
+	})
+	sort.Slice(list, func(a, b int) bool {
+		return list[a].name < list[b].name
+	})
+	return list
+}
+
+// Filter returns the instances of the list that are selected by the filter
+func (l List) Filter(f Filter) List {
+	var filtered List
+	for _, i := range l {
+		if f.Matches(i) {
+			filtered = append(filtered, i)
+		}
+	}
+	return filtered
+}
+
+// Names returns the names of the instances of the list
+func (l List) Names() []string {
+	names := make([]string, 0, len(l))
+	for _, i := range l {
+		names = append(names, i.name)
+	}
+	return names
+}
+
+// ExecuteCommand executes the command in all the instances of the list
+// and returns the outputs by instance name.
+func (l List) ExecuteCommand(ctx context.Context, command ...string) (map[string]string, error) {
+	outputs := make(map[string]string, len(l))
+	for _, i := range l {
+		output, err := i.execution.ExecuteCommand(ctx, command...)
+		if err != nil {
+			return outputs, ErrExecutingCommandOnInstances.WithParams(i.name).Wrap(err)
+		}
+		outputs[i.name] = output
+	}
+	return outputs, nil
+}
+
+// Stop stops all the instances of the list
+func (l List) Stop(ctx context.Context) error {
+	for _, i := range l {
+		if err := i.execution.Stop(ctx); err != nil {
+			return ErrStoppingInstances.WithParams(i.name).Wrap(err)
+		}
+	}
+	return nil
+}
+
+// Destroy destroys all the instances of the list
+func (l List) Destroy(ctx context.Context) error {
+	for _, i := range l {
+		if err := i.execution.Destroy(ctx); err != nil {
+			return ErrDestroyingInstances.WithParams(i.name).Wrap(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package instance
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+)
+
+// signalNameRegex matches the names of the signals accepted by kill -s, e.g. HUP or SIGHUP
+var signalNameRegex = regexp.MustCompile(`^[A-Z][A-Z0-9+-]*$`)
+
+// LiveFileOptions are the options of a file added with AddLiveFile
+type LiveFileOptions struct {
+	// Secret stores the file in a Secret instead of a ConfigMap, e.g. for keys or binary content
+	Secret bool
+	// ReloadSignal is sent to the main process of the container, PID 1, once an update of the file appeared, e.g. "HUP"
+	ReloadSignal string
+	// ReloadCommand is run in the container once an update of the file appeared, after the signal if both are set
+	ReloadCommand []string
+}
+
+type liveFile struct {
+	k8s.LiveFile
+	content []byte
+	opts    LiveFileOptions
+}
+
+// AddLiveFile adds a file with the given content to the instance, that can be updated with UpdateFile once it is started.
+// The file is stored in a ConfigMap, or a Secret, and its directory is mounted in the container,
+// so the directory only contains the live files added to it and hides the content of the image.
+// The content of a file stored in a ConfigMap has to be UTF-8 text.
+// This function can only be called in the states 'Preparing' and 'Committed'
+func (s *storage) AddLiveFile(content []byte, dest string, opts LiveFileOptions) error {
+	if !s.instance.IsInState(StatePreparing, StateCommitted) {
+		return ErrAddingLiveFileNotAllowed.WithParams(s.instance.state.String())
+	}
+	dir := filepath.Dir(dest)
+	if !filepath.IsAbs(dest) || dir == "/" {
+		return ErrInvalidLiveFilePath.WithParams(dest)
+	}
+	for _, f := range s.liveFiles {
+		if f.Dest == dest {
+			return ErrLiveFileAlreadyExists.WithParams(dest, s.instance.name)
+		}
+		if filepath.Dir(f.Dest) == dir && f.Secret != opts.Secret {
+			return ErrLiveFilesMixedInDirectory.WithParams(dir)
+		}
+	}
+	if !opts.Secret && !utf8.Valid(content) {
+		return ErrLiveFileNotUTF8.WithParams(dest)
+	}
+	if opts.ReloadSignal != "" && !signalNameRegex.MatchString(opts.ReloadSignal) {
+		return ErrInvalidReloadSignal.WithParams(opts.ReloadSignal)
+	}
+
+	s.liveFiles = append(s.liveFiles, &liveFile{
+		LiveFile: k8s.LiveFile{
+			Key:    strconv.Itoa(len(s.liveFiles)),
+			Dest:   dest,
+			Secret: opts.Secret,
+		},
+		content: content,
+		opts:    opts,
+	})
+	s.instance.Logger.WithFields(logrus.Fields{
+		"file":     dest,
+		"instance": s.instance.name,
+		"secret":   opts.Secret,
+	}).Debug("added live file")
+	return nil
+}
+
+// UpdateFile replaces the content of a file added with AddLiveFile, in its ConfigMap or Secret,
+// and waits until the new content appears in all the pods of the instance.
+// The kubelet swaps the files atomically, but can take up to a minute to notice the update.
+// The reload signal and command of the file are then sent to or run in every pod.
+// This function can only be called in the state 'Started'
+func (s *storage) UpdateFile(ctx context.Context, dest string, content []byte) error {
+	if !s.instance.IsInState(StateStarted) {
+		return ErrUpdatingFileNotAllowed.WithParams(s.instance.state.String())
+	}
+	var file *liveFile
+	for _, f := range s.liveFiles {
+		if f.Dest == dest {
+			file = f
+		}
+	}
+	if file == nil {
+		return ErrLiveFileNotFound.WithParams(dest, s.instance.name)
+	}
+	if !file.Secret && !utf8.Valid(content) {
+		return ErrLiveFileNotUTF8.WithParams(dest)
+	}
+
+	previous := file.content
+	file.content = content
+	if err := s.deployLiveFiles(ctx); err != nil {
+		file.content = previous
+		return ErrUpdatingLiveFile.WithParams(dest, s.instance.name).Wrap(err)
+	}
+
+	pods, err := s.instance.pods(ctx)
+	if err != nil {
+		return ErrUpdatingLiveFile.WithParams(dest, s.instance.name).Wrap(err)
+	}
+	for _, pod := range pods {
+		if err := s.waitForLiveFile(ctx, pod.Name, file); err != nil {
+			return ErrWaitingForLiveFile.WithParams(dest, pod.Name).Wrap(err)
+		}
+		if err := s.reload(ctx, pod.Name, file.opts); err != nil {
+			return ErrReloadingLiveFile.WithParams(dest, pod.Name).Wrap(err)
+		}
+	}
+
+	s.instance.Logger.WithFields(logrus.Fields{
+		"file":     dest,
+		"instance": s.instance.name,
+		"pods":     len(pods),
+	}).Debug("updated live file")
+	return nil
+}
+
+// waitForLiveFile waits until the content of the file in the pod is the one of the live file
+func (s *storage) waitForLiveFile(ctx context.Context, podName string, file *liveFile) error {
+	var buf bytes.Buffer
+	for {
+		buf.Reset()
+		err := s.instance.K8sClient.StreamCommandInPod(ctx, podName, s.instance.name, []string{"cat", file.Dest}, nil, &buf)
+		if err == nil && bytes.Equal(buf.Bytes(), file.content) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return err
+			}
+			return ctx.Err()
+		case <-time.After(waitForInstanceRetry):
+		}
+	}
+}
+
+// reload sends the reload signal of a live file to the main process of the pod, and runs its reload command
+func (s *storage) reload(ctx context.Context, podName string, opts LiveFileOptions) error {
+	var commands [][]string
+	if opts.ReloadSignal != "" {
+		commands = append(commands, []string{"kill", "-s", opts.ReloadSignal, "1"})
+	}
+	if len(opts.ReloadCommand) != 0 {
+		commands = append(commands, opts.ReloadCommand)
+	}
+	for _, command := range commands {
+		eErr := ErrExecutingCommandInInstance.WithParams(command, s.instance.name)
+		if _, err := s.instance.execution.executeCommandInPod(ctx, podName, eErr, command); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deployLiveFiles writes the live files of the instance to their ConfigMap and their Secret
+func (s *storage) deployLiveFiles(ctx context.Context) error {
+	var (
+		name       = k8s.LiveFilesName(s.instance.name)
+		data       = make(map[string]string)
+		secretData = make(map[string][]byte)
+	)
+	for _, f := range s.liveFiles {
+		if f.Secret {
+			secretData[f.Key] = f.content
+		} else {
+			data[f.Key] = string(f.content)
+		}
+	}
+
+	if len(data) != 0 {
+		if _, err := s.instance.K8sClient.CreateOrUpdateConfigMap(ctx, name, s.instance.execution.Labels(), data); err != nil {
+			return ErrFailedToCreateConfigMap.Wrap(err)
+		}
+	}
+	if len(secretData) != 0 {
+		if _, err := s.instance.K8sClient.CreateOrUpdateSecret(ctx, name, s.instance.execution.Labels(), secretData); err != nil {
+			return ErrFailedToCreateSecret.Wrap(err)
+		}
+	}
+	s.instance.Logger.WithFields(logrus.Fields{
+		"instance": s.instance.name,
+		"files":    len(s.liveFiles),
+	}).Debug("deployed live files")
+	return nil
+}
+
+// destroyLiveFiles deletes the ConfigMap and the Secret of the live files of the instance
+func (s *storage) destroyLiveFiles(ctx context.Context) error {
+	name := k8s.LiveFilesName(s.instance.name)
+	for _, f := range s.liveFiles {
+		if !f.Secret {
+			if err := s.instance.K8sClient.DeleteConfigMap(ctx, name); err != nil {
+				return ErrFailedToDeleteConfigMap.Wrap(err)
+			}
+			break
+		}
+	}
+	if err := s.instance.K8sClient.DeleteSecret(ctx, name); err != nil {
+		return ErrFailedToDeleteSecret.Wrap(err)
+	}
+	s.instance.Logger.WithField("instance", s.instance.name).Debug("destroyed live files")
+	return nil
+}
+
+// k8sLiveFiles returns the live files of the instance, for its container config
+func (s *storage) k8sLiveFiles() []*k8s.LiveFile {
+	liveFiles := make([]*k8s.LiveFile, 0, len(s.liveFiles))
+	for _, f := range s.liveFiles {
+		liveFile := f.LiveFile
+		liveFiles = append(liveFiles, &liveFile)
+	}
+	return liveFiles
+}
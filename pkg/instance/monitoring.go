@@ -20,12 +20,38 @@ func (i *Instance) Monitoring() *monitoring {
 }
 
 func (m *monitoring) Logs(ctx context.Context) (io.ReadCloser, error) {
+	if m.instance.execution.WorkloadKind() != WorkloadReplicaSet {
+		pod, err := m.instance.firstPod(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return m.instance.K8sClient.GetPodLogStream(ctx, pod.Name, v1.PodLogOptions{Container: m.instance.Name()})
+	}
 	if m.instance.sidecars.IsSidecar() {
 		return m.instance.K8sClient.GetLogStream(ctx, m.instance.parentInstance.Name(), m.instance.Name())
 	}
 	return m.instance.K8sClient.GetLogStream(ctx, m.instance.Name(), m.instance.Name())
 }
 
+// ReplicaLogs returns the logs of the given replica of the instance.
+// The replicas are numbered from 0, the oldest first.
+func (m *monitoring) ReplicaLogs(ctx context.Context, replica int) (io.ReadCloser, error) {
+	pod, err := m.instance.replicaPod(ctx, replica)
+	if err != nil {
+		return nil, err
+	}
+	return m.instance.K8sClient.GetPodLogStream(ctx, pod.Name, v1.PodLogOptions{Container: m.instance.Name()})
+}
+
+// NodeLogs returns the logs of the pod of the instance that runs on the given node
+func (m *monitoring) NodeLogs(ctx context.Context, node string) (io.ReadCloser, error) {
+	pod, err := m.instance.nodePod(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+	return m.instance.K8sClient.GetPodLogStream(ctx, pod.Name, v1.PodLogOptions{Container: m.instance.Name()})
+}
+
 // SetLivenessProbe sets the liveness probe of the instance
 // A live probe is a probe that is used to determine if the instance is still alive, and should be restarted if not
 // See usage documentation: https://pkg.go.dev/i.K8sCli.io/api/core/v1@v0.27.3#Probe
@@ -55,6 +55,37 @@ func (n *network) PortForwardTCP(ctx context.Context, port int) (int, error) {
 	if !n.isTCPPortRegistered(port) {
 		return -1, ErrPortNotRegistered.WithParams(port)
 	}
+
+	pod, err := n.instance.firstPod(ctx)
+	if err != nil {
+		return -1, err
+	}
+	return n.portForwardTCP(ctx, pod.Name, port)
+}
+
+// PortForwardReplicaTCP forwards the given port of the given replica to a random port on the host.
+// The replicas are numbered from 0, the oldest first.
+// This function can only be called in the state 'Started'
+func (n *network) PortForwardReplicaTCP(ctx context.Context, replica, port int) (int, error) {
+	if !n.instance.IsState(StateStarted) {
+		return -1, ErrRandomPortForwardingNotAllowed.WithParams(n.instance.state.String())
+	}
+
+	if err := validatePort(port); err != nil {
+		return -1, err
+	}
+	if !n.isTCPPortRegistered(port) {
+		return -1, ErrPortNotRegistered.WithParams(port)
+	}
+
+	pod, err := n.instance.replicaPod(ctx, replica)
+	if err != nil {
+		return -1, err
+	}
+	return n.portForwardTCP(ctx, pod.Name, port)
+}
+
+func (n *network) portForwardTCP(ctx context.Context, podName string, port int) (int, error) {
 	// Get a random port on the host
 	localPort, err := getFreePortTCP()
 	if err != nil {
@@ -62,13 +93,8 @@ func (n *network) PortForwardTCP(ctx context.Context, port int) (int, error) {
 	}
 
 	// Forward the port
-	pod, err := n.instance.K8sClient.GetFirstPodFromReplicaSet(ctx, n.instance.name)
-	if err != nil {
-		return -1, ErrGettingPodFromReplicaSet.WithParams(n.instance.name).Wrap(err)
-	}
-
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		err := n.instance.K8sClient.PortForwardPod(ctx, pod.Name, localPort, port)
+		err := n.instance.K8sClient.PortForwardPod(ctx, podName, localPort, port)
 		if err == nil {
 			break
 		}
@@ -96,6 +122,34 @@ func (n *network) PortForwardTCP(ctx context.Context, port int) (int, error) {
 	return localPort, nil
 }
 
+// GetReplicaIP returns the IP of the pod of the given replica.
+// Unlike GetIP, which returns the IP of the service shared by all the replicas, it addresses a single replica.
+// The replicas are numbered from 0, the oldest first.
+func (n *network) GetReplicaIP(ctx context.Context, replica int) (string, error) {
+	pod, err := n.instance.replicaPod(ctx, replica)
+	if err != nil {
+		return "", err
+	}
+	if pod.Status.PodIP == "" {
+		return "", ErrGettingReplicaIP.WithParams(replica, n.instance.name)
+	}
+	return pod.Status.PodIP, nil
+}
+
+// GetReplicaHostname returns the DNS name of the given replica, e.g. 'name-0.name-headless',
+// which can be resolved from the other pods of the namespace.
+// It stays the same when the replica is restarted, but is only available for instances that run as a StatefulSet.
+func (n *network) GetReplicaHostname(replica int) (string, error) {
+	owner := n.instance.podOwner()
+	if owner.execution.workloadKind != WorkloadStatefulSet {
+		return "", ErrReplicaHostnameNotSupported.WithParams(owner.name)
+	}
+	if replica < 0 || replica >= int(owner.execution.replicas) {
+		return "", ErrReplicaNotFound.WithParams(replica, owner.name, owner.execution.replicas)
+	}
+	return owner.replicaHostname(replica), nil
+}
+
 // AddPortUDP adds a UDP port to the instance
 // This function can be called in the states 'Preparing', 'Committed' and 'Stopped'
 func (n *network) AddPortUDP(port int) error {
@@ -204,6 +258,25 @@ func (n *network) destroyService(ctx context.Context) error {
 	return n.instance.K8sClient.DeleteService(ctx, n.instance.name)
 }
 
+// deployHeadlessService deploys the service that gives their DNS names to the replicas of a StatefulSet
+func (n *network) deployHeadlessService(ctx context.Context) error {
+	labels := n.instance.execution.Labels()
+	serviceName := n.instance.headlessServiceName()
+	if _, err := n.instance.K8sClient.CreateHeadlessService(ctx, serviceName, labels, labels); err != nil {
+		return err
+	}
+	n.instance.Logger.WithFields(logrus.Fields{
+		"instance": n.instance.name,
+		"service":  serviceName,
+	}).Debug("started headless service")
+	return nil
+}
+
+// destroyHeadlessService destroys the headless service of a StatefulSet
+func (n *network) destroyHeadlessService(ctx context.Context) error {
+	return n.instance.K8sClient.DeleteService(ctx, n.instance.headlessServiceName())
+}
+
 // isTCPPortRegistered returns true if the given port is registered
 // with the instance, and false otherwise
 func (n *network) isTCPPortRegistered(port int) bool {
@@ -295,16 +368,12 @@ func (n *network) deployOrPatchService(ctx context.Context, portsTCP, portsUDP [
 }
 
 func (n *network) enableIfDisabled(ctx context.Context) error {
-	disableNetwork, err := n.IsDisabled(ctx)
-	if err != nil {
-		n.instance.Logger.WithError(err).WithField("instance", n.instance.name).Error("error checking network status for instance")
-		return ErrCheckingNetworkStatusForInstance.WithParams(n.instance.name).Wrap(err)
-	}
-
-	if !disableNetwork {
+	// the network policy outlives the pod, so it is handled directly rather than with IsDisabled and Enable,
+	// which are only allowed for started instances
+	if !n.instance.K8sClient.NetworkPolicyExists(ctx, n.instance.name) {
 		return nil
 	}
-	if err := n.Enable(ctx); err != nil {
+	if err := n.instance.K8sClient.DeleteNetworkPolicy(ctx, n.instance.name); err != nil {
 		n.instance.Logger.WithError(err).WithField("instance", n.instance.name).Error("error enabling network for instance")
 		return ErrEnablingNetworkForInstance.WithParams(n.instance.name).Wrap(err)
 	}
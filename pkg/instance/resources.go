@@ -75,20 +75,40 @@ func (r *resources) deployResources(ctx context.Context) error {
 		if err := r.deployService(ctx); err != nil {
 			return err
 		}
+		if r.instance.execution.workloadKind == WorkloadStatefulSet {
+			if err := r.instance.network.deployHeadlessService(ctx); err != nil {
+				return ErrDeployingHeadlessService.WithParams(r.instance.name).Wrap(err)
+			}
+		}
 	}
 
 	if err := r.deployStorage(ctx); err != nil {
 		return err
 	}
+	if r.instance.hasSecrets() {
+		if err := r.deploySecrets(ctx); err != nil {
+			return ErrDeployingSecretsForInstance.WithParams(r.instance.name).Wrap(err)
+		}
+	}
 	return nil
 }
 
 func (r *resources) deployStorage(ctx context.Context) error {
-	if len(r.instance.storage.volumes) != 0 {
-		if err := r.instance.storage.deployVolume(ctx); err != nil {
+	// the volumes of a StatefulSet are claimed by each of its replicas, and the ones of a DaemonSet are node-local
+	kind := r.instance.execution.WorkloadKind()
+	if len(r.instance.storage.volumes) != 0 && kind != WorkloadStatefulSet && kind != WorkloadDaemonSet {
+		if err := r.instance.storage.deployVolumes(ctx); err != nil {
 			return ErrDeployingVolumeForInstance.WithParams(r.instance.name).Wrap(err)
 		}
 	}
+	if err := r.instance.storage.deploySeeds(ctx); err != nil {
+		return err
+	}
+	if len(r.instance.storage.liveFiles) != 0 {
+		if err := r.instance.storage.deployLiveFiles(ctx); err != nil {
+			return ErrDeployingLiveFilesForInstance.WithParams(r.instance.name).Wrap(err)
+		}
+	}
 	if len(r.instance.storage.files) == 0 {
 		return nil
 	}
@@ -116,8 +136,19 @@ func (r *resources) deployService(ctx context.Context) error {
 
 // destroyResources destroys the resources for the instance
 func (r *resources) destroyResources(ctx context.Context) error {
-	if len(r.instance.storage.volumes) != 0 {
-		if err := r.instance.storage.destroyVolume(ctx); err != nil {
+	switch {
+	case r.instance.execution.WorkloadKind() == WorkloadStatefulSet:
+		// the claims of the sidecars are destroyed with the ones of their instance
+		if !r.instance.sidecars.IsSidecar() {
+			if err := r.instance.storage.destroyReplicaVolumes(ctx); err != nil {
+				return ErrDestroyingReplicaVolumes.WithParams(r.instance.name).Wrap(err)
+			}
+			if err := r.instance.network.destroyHeadlessService(ctx); err != nil {
+				return ErrDestroyingHeadlessService.WithParams(r.instance.name).Wrap(err)
+			}
+		}
+	case len(r.instance.storage.volumes) != 0:
+		if err := r.instance.storage.destroyVolumes(ctx); err != nil {
 			return ErrDestroyingVolumeForInstance.WithParams(r.instance.name).Wrap(err)
 		}
 	}
@@ -128,6 +159,16 @@ func (r *resources) destroyResources(ctx context.Context) error {
 			return ErrDestroyingFilesForInstance.WithParams(r.instance.name).Wrap(err)
 		}
 	}
+	if len(r.instance.storage.liveFiles) != 0 {
+		if err := r.instance.storage.destroyLiveFiles(ctx); err != nil {
+			return ErrDestroyingLiveFilesForInstance.WithParams(r.instance.name).Wrap(err)
+		}
+	}
+	if r.instance.hasSecrets() {
+		if err := r.destroySecrets(ctx); err != nil {
+			return ErrDestroyingSecretsForInstance.WithParams(r.instance.name).Wrap(err)
+		}
+	}
 	if r.instance.network.kubernetesService != nil {
 		err := r.instance.network.destroyService(ctx)
 		if err != nil {
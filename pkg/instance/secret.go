@@ -0,0 +1,142 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+)
+
+const (
+	// the keys of the secret environment variables and files in the Secret of the instance
+	secretEnvKeyPrefix  = "env-"
+	secretFileKeyPrefix = "file-"
+
+	// defaultSecretFileMode is the mode of the secret files added without one, readable by their owner and the group of the pod
+	defaultSecretFileMode = 0o440
+)
+
+type secretFile struct {
+	k8s.SecretFile
+	content []byte
+}
+
+// AddSecretEnv sets an environment variable of the instance from a Secret of the instance,
+// so the value is never written to the image nor to the pod spec, and it is not logged.
+// This function can only be called in the states 'Preparing' and 'Committed'
+func (s *security) AddSecretEnv(key, value string) error {
+	if !s.instance.IsInState(StatePreparing, StateCommitted) {
+		return ErrAddingSecretNotAllowed.WithParams(s.instance.state.String())
+	}
+	if errs := validation.IsEnvVarName(key); len(errs) > 0 {
+		return ErrInvalidSecretEnvName.WithParams(key, strings.Join(errs, ", "))
+	}
+
+	s.secretEnv[key] = value
+	s.instance.Logger.WithFields(logrus.Fields{
+		"instance": s.instance.name,
+		"key":      key,
+		// value is not logged to avoid leaking it
+	}).Debug("added secret environment variable")
+	return nil
+}
+
+// AddSecretFile adds a file with the given content and mode to the instance from a Secret of the instance,
+// so the content is never written to the image nor to a ConfigMap, and it is not logged.
+// The file belongs to root and to the group of the pod, a mode of 0 gives the file the mode 0440.
+// This function can only be called in the states 'Preparing' and 'Committed'
+func (s *storage) AddSecretFile(dest string, content []byte, mode os.FileMode) error {
+	if !s.instance.IsInState(StatePreparing, StateCommitted) {
+		return ErrAddingSecretNotAllowed.WithParams(s.instance.state.String())
+	}
+	if !filepath.IsAbs(dest) {
+		return ErrInvalidSecretFilePath.WithParams(dest)
+	}
+	for _, f := range s.secretFiles {
+		if f.Dest == dest {
+			return ErrSecretFileAlreadyExists.WithParams(dest, s.instance.name)
+		}
+	}
+	if mode == 0 {
+		mode = defaultSecretFileMode
+	}
+
+	s.secretFiles = append(s.secretFiles, &secretFile{
+		SecretFile: k8s.SecretFile{
+			Key:  fmt.Sprintf("%s%d", secretFileKeyPrefix, len(s.secretFiles)),
+			Dest: dest,
+			Mode: mode.Perm(),
+		},
+		content: content,
+	})
+	s.instance.Logger.WithFields(logrus.Fields{
+		"instance": s.instance.name,
+		"file":     dest,
+	}).Debug("added secret file")
+	return nil
+}
+
+// hasSecrets reports whether the instance has secret environment variables or files, stored in its Secret
+func (i *Instance) hasSecrets() bool {
+	return len(i.security.secretEnv) != 0 || len(i.storage.secretFiles) != 0
+}
+
+// deploySecrets writes the secret environment variables and files of the instance to its Secret,
+// which is labelled like the other resources of the instance so it is cleaned up with them
+func (r *resources) deploySecrets(ctx context.Context) error {
+	data := make(map[string][]byte)
+	for key, value := range r.instance.security.secretEnv {
+		data[secretEnvKeyPrefix+key] = []byte(value)
+	}
+	for _, f := range r.instance.storage.secretFiles {
+		data[f.Key] = f.content
+	}
+
+	name := k8s.SecretsName(r.instance.name)
+	if _, err := r.instance.K8sClient.CreateOrUpdateSecret(ctx, name, r.instance.execution.Labels(), data); err != nil {
+		return ErrFailedToCreateSecret.Wrap(err)
+	}
+	r.instance.Logger.WithFields(logrus.Fields{
+		"instance": r.instance.name,
+		"secret":   name,
+	}).Debug("deployed secrets")
+	return nil
+}
+
+// destroySecrets deletes the Secret of the instance
+func (r *resources) destroySecrets(ctx context.Context) error {
+	name := k8s.SecretsName(r.instance.name)
+	if err := r.instance.K8sClient.DeleteSecret(ctx, name); err != nil {
+		return ErrFailedToDeleteSecret.Wrap(err)
+	}
+	r.instance.Logger.WithFields(logrus.Fields{
+		"instance": r.instance.name,
+		"secret":   name,
+	}).Debug("destroyed secrets")
+	return nil
+}
+
+// k8sSecretEnv returns the keys of the secret environment variables of the instance in its Secret, by variable
+func (s *security) k8sSecretEnv() map[string]string {
+	secretEnv := make(map[string]string, len(s.secretEnv))
+	for key := range s.secretEnv {
+		secretEnv[key] = secretEnvKeyPrefix + key
+	}
+	return secretEnv
+}
+
+// k8sSecretFiles returns the secret files of the instance, for its container config
+func (s *storage) k8sSecretFiles() []*k8s.SecretFile {
+	secretFiles := make([]*k8s.SecretFile, 0, len(s.secretFiles))
+	for _, f := range s.secretFiles {
+		secretFile := f.SecretFile
+		secretFiles = append(secretFiles, &secretFile)
+	}
+	return secretFiles
+}
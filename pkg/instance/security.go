@@ -20,6 +20,9 @@ type security struct {
 
 	// PolicyRules is the list of policy rules to add to the instance
 	policyRules []rbacv1.PolicyRule
+
+	// secretEnv is the environment variables set from the Secret of the instance
+	secretEnv map[string]string
 }
 
 func (i *Instance) Security() *security {
@@ -105,10 +108,16 @@ func (s *security) clone() *security {
 	policyRulesCopy := make([]rbacv1.PolicyRule, len(s.policyRules))
 	copy(policyRulesCopy, s.policyRules)
 
+	secretEnvCopy := make(map[string]string, len(s.secretEnv))
+	for key, value := range s.secretEnv {
+		secretEnvCopy[key] = value
+	}
+
 	return &security{
 		instance:        nil,
 		privileged:      s.privileged,
 		capabilitiesAdd: capabilitiesAddCopy,
 		policyRules:     policyRulesCopy,
+		secretEnv:       secretEnvCopy,
 	}
 }
@@ -0,0 +1,188 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+	"github.com/celestiaorg/knuu/pkg/minio"
+)
+
+// VolumeSeed is the content a volume is populated with on the first start of the instance, see SeedVolume
+type VolumeSeed struct {
+	dir     string
+	archive string
+	bucket  string
+	object  string
+}
+
+// SeedFromDir seeds a volume with the content of a local directory
+func SeedFromDir(dir string) VolumeSeed {
+	return VolumeSeed{dir: dir}
+}
+
+// SeedFromArchive seeds a volume with the content of a local tar archive, gzipped if its name ends with .tar.gz or .tgz
+func SeedFromArchive(path string) VolumeSeed {
+	return VolumeSeed{archive: path}
+}
+
+// SeedFromMinio seeds a volume with the content of a tar archive stored in the given bucket of the Minio of the scope,
+// gzipped if its name ends with .tar.gz or .tgz
+func SeedFromMinio(bucket, object string) VolumeSeed {
+	return VolumeSeed{bucket: bucket, object: object}
+}
+
+// String describes the seed in the errors and the logs
+func (v VolumeSeed) String() string {
+	switch {
+	case v.dir != "":
+		return fmt.Sprintf("dir '%s'", v.dir)
+	case v.archive != "":
+		return fmt.Sprintf("archive '%s'", v.archive)
+	case v.object != "":
+		return fmt.Sprintf("minio object '%s/%s'", v.bucket, v.object)
+	}
+	return "empty"
+}
+
+func (v VolumeSeed) gzipped() bool {
+	name := v.archive + v.object
+	return strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz")
+}
+
+func isTarArchive(name string) bool {
+	return strings.HasSuffix(name, ".tar") || strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz")
+}
+
+// validate checks that the source of the seed of the volume at the given path exists
+func (v VolumeSeed) validate(path string) error {
+	switch {
+	case v.dir != "":
+		info, err := os.Stat(v.dir)
+		if err != nil {
+			return ErrInvalidVolumeSeed.WithParams(path, v.String(), "can not be read").Wrap(err)
+		}
+		if !info.IsDir() {
+			return ErrInvalidVolumeSeed.WithParams(path, v.String(), "is not a directory")
+		}
+	case v.archive != "":
+		info, err := os.Stat(v.archive)
+		if err != nil {
+			return ErrInvalidVolumeSeed.WithParams(path, v.String(), "can not be read").Wrap(err)
+		}
+		if info.IsDir() || !isTarArchive(v.archive) {
+			return ErrInvalidVolumeSeed.WithParams(path, v.String(), "is not a .tar, .tar.gz or .tgz file")
+		}
+	case v.object != "":
+		if v.bucket == "" {
+			return ErrInvalidVolumeSeed.WithParams(path, v.String(), "has no bucket")
+		}
+		if !isTarArchive(v.object) {
+			return ErrInvalidVolumeSeed.WithParams(path, v.String(), "is not a .tar, .tar.gz or .tgz object")
+		}
+	default:
+		return ErrInvalidVolumeSeed.WithParams(path, "the seed", "has no source")
+	}
+	return nil
+}
+
+// SeedVolume populates the volume mounted at the given path with the content of the seed on the first start of the instance,
+// e.g. a chain snapshot that is too large to be added with AddFile.
+// The seed is uploaded to the Minio of the scope when the instance is started, and an init container extracts it to the volume.
+// The volume is then marked as seeded with a .knuu-seeded file at its root,
+// so the data is kept when the instance is stopped and started again.
+// Each replica of a StatefulSet has its own volumes, that are seeded on the first start of the replica.
+// This function can only be called in the states 'Preparing' and 'Committed'
+func (s *storage) SeedVolume(path string, seed VolumeSeed) error {
+	if !s.instance.IsInState(StatePreparing, StateCommitted) {
+		return ErrSeedingVolumeNotAllowed.WithParams(s.instance.state.String())
+	}
+	if s.instance.sidecars.isSidecar {
+		return ErrSeedingVolumeNotAllowedForSidecar.WithParams(s.instance.name)
+	}
+	volume := s.volumeAt(path)
+	if volume == nil {
+		return ErrVolumeNotFound.WithParams(path, s.instance.name)
+	}
+	if _, ok := s.seeds[volume.Name]; ok {
+		return ErrVolumeAlreadySeeded.WithParams(path, s.instance.name)
+	}
+	if err := seed.validate(path); err != nil {
+		return err
+	}
+	if s.instance.MinioClient == nil {
+		return ErrMinioNotInitialized
+	}
+
+	if s.seeds == nil {
+		s.seeds = make(map[string]VolumeSeed)
+	}
+	s.seeds[volume.Name] = seed
+	s.instance.Logger.WithFields(logrus.Fields{
+		"instance": s.instance.name,
+		"volume":   path,
+		"seed":     seed.String(),
+	}).Debug("set seed of volume")
+	return nil
+}
+
+// deploySeeds uploads the seeds of the volumes to Minio and sets the URLs the pods download them from.
+// A seed is only uploaded once, on the first start of the instance.
+func (s *storage) deploySeeds(ctx context.Context) error {
+	for _, volume := range s.volumes {
+		seed, ok := s.seeds[volume.Name]
+		if !ok || volume.Seed != nil {
+			continue
+		}
+		url, err := s.uploadSeed(ctx, volume, seed)
+		if err != nil {
+			return ErrUploadingVolumeSeed.WithParams(volume.Path, s.instance.name).Wrap(err)
+		}
+		volume.Seed = &k8s.VolumeSeed{URL: url, Gzipped: seed.gzipped(), CredentialsSecret: minio.CredentialsSecretName}
+		s.instance.Logger.WithFields(logrus.Fields{
+			"instance": s.instance.name,
+			"volume":   volume.Path,
+			"seed":     seed.String(),
+		}).Debug("uploaded seed of volume")
+	}
+	return nil
+}
+
+// uploadSeed uploads the local seed of the volume to Minio, and returns the URL to download it.
+// The URL does not expire, the seed container signs its request with the credentials of Minio.
+func (s *storage) uploadSeed(ctx context.Context, volume *k8s.Volume, seed VolumeSeed) (string, error) {
+	bucket, object := seed.bucket, seed.object
+	switch {
+	case seed.dir != "":
+		bucket = volumesBucketName
+		object = fmt.Sprintf("%s/%s/%s/seed.tar", s.instance.Scope, s.instance.name, volume.Name)
+		// the directory is archived while it is uploaded, so it does not have to fit in memory
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(writeTar(pw, seed.dir, ""))
+		}()
+		err := s.instance.MinioClient.Push(ctx, pr, object, bucket)
+		pr.CloseWithError(err)
+		if err != nil {
+			return "", err
+		}
+	case seed.archive != "":
+		bucket = volumesBucketName
+		object = fmt.Sprintf("%s/%s/%s/%s", s.instance.Scope, s.instance.name, volume.Name, filepath.Base(seed.archive))
+		f, err := os.Open(seed.archive)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		if err := s.instance.MinioClient.Push(ctx, f, object, bucket); err != nil {
+			return "", err
+		}
+	}
+	return s.instance.MinioClient.ObjectURL(object, bucket)
+}
@@ -35,6 +35,15 @@ func (s *sidecars) IsSidecar() bool {
 	return s.isSidecar
 }
 
+// Instances returns the instances of the sidecars added to the instance
+func (s *sidecars) Instances() []*Instance {
+	instances := make([]*Instance, 0, len(s.sidecars))
+	for _, sc := range s.sidecars {
+		instances = append(instances, sc.Instance())
+	}
+	return instances
+}
+
 // Add adds a sidecar to the instance
 // This function can only be called in the state 'Preparing', 'Committed' or 'Stopped'
 func (s *sidecars) Add(ctx context.Context, sc SidecarManager) error {
@@ -94,7 +103,7 @@ func (s *sidecars) setStateForSidecars(state InstanceState) {
 	// We don't handle errors here, as the function can't return an error
 	_ = s.applyFunctionToSidecars(
 		func(sc SidecarManager) error {
-			sc.Instance().state = state
+			sc.Instance().SetState(state)
 			return nil
 		})
 }
@@ -1,6 +1,10 @@
 package instance
 
-import "github.com/sirupsen/logrus"
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
 
 // InstanceState represents the state of the instance
 type InstanceState int
@@ -33,12 +37,50 @@ func (i *Instance) IsInState(states ...InstanceState) bool {
 	return false
 }
 
+// StateTransition is a change of the state of an instance
+type StateTransition struct {
+	Instance *Instance
+	From     InstanceState
+	To       InstanceState
+	Time     time.Time
+}
+
+// StateObserver is called on the state transitions of instances
+type StateObserver func(StateTransition)
+
 func (i *Instance) SetState(state InstanceState) {
+	from := i.state
 	i.state = state
 	i.Logger.WithFields(logrus.Fields{
 		"instance": i.name,
 		"state":    i.state.String(),
 	}).Debug("set state of instance")
+
+	if from == state {
+		return
+	}
+	transition := StateTransition{
+		Instance: i,
+		From:     from,
+		To:       state,
+		Time:     time.Now(),
+	}
+	i.stateObserversMu.RLock()
+	observers := i.stateObservers
+	i.stateObserversMu.RUnlock()
+	for _, observer := range observers {
+		observer(transition)
+	}
+	i.SystemDependencies.NotifyStateObservers(transition)
+}
+
+// OnStateChange registers a function that is called on each state transition of the instance.
+// The function is called in the goroutine that changes the state, so it should return quickly.
+// The observers are not copied to the clones of the instance.
+func (i *Instance) OnStateChange(observer StateObserver) {
+	i.stateObserversMu.Lock()
+	defer i.stateObserversMu.Unlock()
+	i.stateObservers = append(i.stateObservers, observer)
 }
 
 func (i *Instance) IsState(state InstanceState) bool {
@@ -8,19 +8,36 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/celestiaorg/knuu/pkg/k8s"
 	"github.com/celestiaorg/knuu/pkg/names"
 )
 
+const (
+	// maxFilesShardSize is the maximum size of the content of a ConfigMap of the files,
+	// below the 1 MiB limit of Kubernetes to leave room for the keys and the metadata
+	maxFilesShardSize = 1<<20 - 64<<10
+	// filesBucketName is the bucket of the Minio of the scope where the files too large for a ConfigMap are uploaded
+	filesBucketName = "knuu-files"
+)
+
 type storage struct {
-	instance *Instance
-	volumes  []*k8s.Volume
-	files    []*k8s.File
-	fsGroup  int64
+	instance     *Instance
+	volumes      []*k8s.Volume
+	volumeMounts []*k8s.VolumeMount // volumes of the instance of a sidecar mounted in the sidecar
+	files        []*k8s.File
+	fsGroup      int64
+	seeds        map[string]VolumeSeed // seeds of the volumes, by volume name
+	liveFiles    []*liveFile
+	secretFiles  []*secretFile
+	// filesArchiveURL is the URL of the archive of the files too large for a ConfigMap, once it is uploaded
+	filesArchiveURL string
 }
 
 func (i *Instance) Storage() *storage {
@@ -98,6 +115,10 @@ func (s *storage) AddFolder(src string, dest string, chown string) error {
 				// create directory at destination path
 				return os.MkdirAll(dstPath, os.ModePerm)
 			}
+			// the symlinks are recreated instead of followed
+			if info.Mode()&os.ModeSymlink != 0 {
+				return s.addSymlink(path, filepath.Join(dest, relPath), chown)
+			}
 			// copy file to destination path
 			return s.AddFile(path, filepath.Join(dest, relPath), chown)
 		})
@@ -139,46 +160,149 @@ func (s *storage) AddFileBytes(bytes []byte, dest string, chown string) error {
 	return s.AddFile(tmpfile.Name(), dest, chown)
 }
 
+// VolumeOptions are the options of a volume added with AddNamedVolume
+type VolumeOptions struct {
+	// Owner is the user and group that own the content of the volume, 0 by default
+	Owner int64
+	// StorageClass is the storage class of the claim of the volume, the default one of the cluster if empty
+	StorageClass string
+	// AccessMode is the access mode of the claim of the volume, ReadWriteOnce if empty
+	AccessMode v1.PersistentVolumeAccessMode
+}
+
 // AddVolume adds a volume to the instance
 // The owner of the volume is set to 0, if you want to set a custom owner use AddVolumeWithOwner
 // This function can only be called in the states 'Preparing', 'Committed' and 'Stopped'
 func (s *storage) AddVolume(path string, size resource.Quantity) error {
-	// temporary feat, we will remove it once we can add multiple volumes
-	if len(s.volumes) > 0 {
-		s.instance.Logger.WithFields(logrus.Fields{
-			"instance": s.instance.name,
-			"volumes":  len(s.volumes),
-		}).Debug("maximum volumes exceeded")
-		return ErrMaximumVolumesExceeded.WithParams(s.instance.name)
-	}
 	return s.AddVolumeWithOwner(path, size, 0)
 }
 
 // AddVolumeWithOwner adds a volume to the instance with the given owner
 // This function can only be called in the states 'Preparing', 'Committed' and 'Stopped'
 func (s *storage) AddVolumeWithOwner(path string, size resource.Quantity, owner int64) error {
+	return s.AddNamedVolume("", path, size, VolumeOptions{Owner: owner})
+}
+
+// AddNamedVolume adds a volume with the given name to the instance, mounted at the given path.
+// Each volume gets its own PersistentVolumeClaim, named after the instance and the volume,
+// and can be mounted in the sidecars of the instance with MountVolume.
+// A volume without a name is named volume-0, volume-1... in the order the volumes are added.
+// This function can only be called in the states 'Preparing', 'Committed' and 'Stopped'
+func (s *storage) AddNamedVolume(name, path string, size resource.Quantity, opts VolumeOptions) error {
 	if !s.instance.IsInState(StatePreparing, StateCommitted, StateStopped) {
 		return ErrAddingVolumeNotAllowed.WithParams(s.instance.state.String())
 	}
-	// temporary feat, we will remove it once we can add multiple volumes
-	if len(s.volumes) > 0 {
-		s.instance.Logger.WithFields(logrus.Fields{
-			"instance": s.instance.name,
-			"volumes":  len(s.volumes),
-		}).Debug("maximum volumes exceeded")
-		return ErrMaximumVolumesExceeded.WithParams(s.instance.name)
+	if name == "" {
+		name = s.nextVolumeName()
 	}
-	volume := s.instance.K8sClient.NewVolume(path, size, owner)
+	if err := s.validateVolume(name, path, opts); err != nil {
+		return err
+	}
+
+	volume := s.instance.K8sClient.NewVolume(path, size, opts.Owner)
+	volume.Name = name
+	volume.StorageClass = opts.StorageClass
+	volume.AccessMode = opts.AccessMode
 	s.volumes = append(s.volumes, volume)
 	s.instance.Logger.WithFields(logrus.Fields{
-		"volume":   path,
-		"size":     size.String(),
-		"owner":    owner,
-		"instance": s.instance.name,
+		"name":          name,
+		"volume":        path,
+		"size":          size.String(),
+		"owner":         opts.Owner,
+		"storage_class": opts.StorageClass,
+		"access_mode":   opts.AccessMode,
+		"instance":      s.instance.name,
 	}).Debug("added volume")
 	return nil
 }
 
+// MountVolume mounts the volume with the given name of the instance the sidecar is added to, at the given path of the sidecar.
+// It can only be used on the instance of a sidecar, the volume is checked when the instance is started.
+// This function can only be called in the states 'Preparing', 'Committed' and 'Stopped'
+func (s *storage) MountVolume(name, path string, readOnly bool) error {
+	if !s.instance.IsInState(StatePreparing, StateCommitted, StateStopped) {
+		return ErrMountingVolumeNotAllowed.WithParams(s.instance.state.String())
+	}
+	if name == "" {
+		return ErrVolumeNameMustBeSet
+	}
+	if path == "" {
+		return ErrVolumePathMustBeSet
+	}
+	for _, mount := range s.volumeMounts {
+		if mount.Path == path {
+			return ErrVolumePathAlreadyUsed.WithParams(path, mount.Volume, s.instance.name)
+		}
+	}
+
+	s.volumeMounts = append(s.volumeMounts, &k8s.VolumeMount{Volume: name, Path: path, ReadOnly: readOnly})
+	s.instance.Logger.WithFields(logrus.Fields{
+		"name":      name,
+		"path":      path,
+		"read_only": readOnly,
+		"instance":  s.instance.name,
+	}).Debug("mounted volume")
+	return nil
+}
+
+// Volumes returns the volumes of the instance, in the order they were added
+func (s *storage) Volumes() []k8s.Volume {
+	volumes := make([]k8s.Volume, 0, len(s.volumes))
+	for _, v := range s.volumes {
+		volumes = append(volumes, *v)
+	}
+	return volumes
+}
+
+// validateVolume checks that a volume with the given name and path can be added to the instance
+func (s *storage) validateVolume(name, path string, opts VolumeOptions) error {
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return ErrInvalidVolumeName.WithParams(name, s.instance.name, strings.Join(errs, ", "))
+	}
+	// the files of the instance are mounted from pod volumes named like volumes
+	podVolume := k8s.VolumeClaimName(s.instance.name, name)
+	if s.instance.isReservedVolume(podVolume) {
+		return ErrInvalidVolumeName.WithParams(name, s.instance.name, "the name is reserved for the files of the instance")
+	}
+	if path == "" {
+		return ErrVolumePathMustBeSet
+	}
+	switch opts.AccessMode {
+	case "", v1.ReadWriteOnce, v1.ReadOnlyMany, v1.ReadWriteMany, v1.ReadWriteOncePod:
+	default:
+		return ErrInvalidVolumeAccessMode.WithParams(opts.AccessMode, name)
+	}
+	for _, v := range s.volumes {
+		if v.Name == name {
+			return ErrVolumeAlreadyExists.WithParams(name, s.instance.name)
+		}
+		if v.Path == path {
+			return ErrVolumePathAlreadyUsed.WithParams(path, v.Name, s.instance.name)
+		}
+	}
+	return nil
+}
+
+// nextVolumeName returns the first name volume-<n> that is not used by a volume of the instance
+func (s *storage) nextVolumeName() string {
+	for n := len(s.volumes); ; n++ {
+		name := fmt.Sprintf("volume-%d", n)
+		if s.volume(name) == nil {
+			return name
+		}
+	}
+}
+
+// volume returns the volume with the given name, or nil if there is none
+func (s *storage) volume(name string) *k8s.Volume {
+	for _, v := range s.volumes {
+		if v.Name == name {
+			return v
+		}
+	}
+	return nil
+}
+
 // GetFileBytes returns the content of the given file
 // This function can only be called in the states 'Preparing' and 'Committed'
 func (s *storage) GetFileBytes(ctx context.Context, file string) ([]byte, error) {
@@ -203,18 +327,28 @@ func (s *storage) GetFileBytes(ctx context.Context, file string) ([]byte, error)
 	return io.ReadAll(rc)
 }
 
+// ReadFileFromRunningInstance returns a reader of the content of the file in the instance.
+// The content is streamed from the output of cat in the first pod of the instance, so it can be binary
+// and does not have to fit in memory. An error of cat is returned by the reader.
+// This function can only be called in the state 'Started'
 func (s *storage) ReadFileFromRunningInstance(ctx context.Context, filePath string) (io.ReadCloser, error) {
 	if !s.instance.IsInState(StateStarted) {
 		return nil, ErrReadingFileNotAllowed.WithParams(s.instance.state.String())
 	}
-
-	// Not the best solution, we need to find a better one.
-	// Tested with a 110MB+ file and it worked.
-	fileContent, err := s.instance.execution.ExecuteCommand(ctx, "cat", filePath)
+	pod, err := s.instance.firstPod(ctx)
 	if err != nil {
 		return nil, ErrReadingFileFromInstance.WithParams(filePath, s.instance.name).Wrap(err)
 	}
-	return io.NopCloser(strings.NewReader(fileContent)), nil
+
+	pr, pw := io.Pipe()
+	go func() {
+		err := s.instance.K8sClient.StreamCommandInPod(ctx, pod.Name, s.instance.name, []string{"cat", filePath}, nil, pw)
+		if err != nil {
+			err = ErrReadingFileFromInstance.WithParams(filePath, s.instance.name).Wrap(err)
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr, nil
 }
 
 func (s *storage) checkSrcExists(src string) error {
@@ -249,48 +383,105 @@ func (s *storage) copyFileToBuildDir(src, dest string) (string, error) {
 		return "", ErrCreatingDirectory.Wrap(err)
 	}
 
-	dst, err := os.Create(dstPath)
-	if err != nil {
-		return "", ErrFailedToCreateDestFile.WithParams(dstPath).Wrap(err)
-	}
-	defer dst.Close()
-
 	srcFile, err := os.Open(src)
 	if err != nil {
 		return "", ErrFailedToOpenSrcFile.WithParams(src).Wrap(err)
 	}
 	defer srcFile.Close()
+	srcInfo, err := srcFile.Stat()
+	if err != nil {
+		return "", ErrFailedToOpenSrcFile.WithParams(src).Wrap(err)
+	}
+
+	dst, err := os.Create(dstPath)
+	if err != nil {
+		return "", ErrFailedToCreateDestFile.WithParams(dstPath).Wrap(err)
+	}
+	defer dst.Close()
 
 	if _, err := io.Copy(dst, srcFile); err != nil {
 		return "", ErrFailedToCopyFile.WithParams(src, dstPath).Wrap(err)
 	}
+	// the mode of the source is kept, so it is the one of the file in the image and in the pod
+	if err := dst.Chmod(srcInfo.Mode().Perm()); err != nil {
+		return "", ErrFailedToCopyFile.WithParams(src, dstPath).Wrap(err)
+	}
 
 	return dstPath, nil
 }
 
+// addSymlink adds a symlink to the instance at dest, with the target of the symlink src.
+// Once the instance is committed, the symlinks can only be added in its volumes, where the init container creates them.
+func (s *storage) addSymlink(src, dest, chown string) error {
+	target, err := os.Readlink(src)
+	if err != nil {
+		return ErrReadingSymlink.WithParams(src).Wrap(err)
+	}
+
+	if s.instance.state == StatePreparing {
+		s.instance.build.addSymlinkToBuilder(target, dest, chown)
+		return nil
+	}
+	if s.volumeContaining(dest) == nil {
+		return ErrSymlinkNotInVolume.WithParams(dest, s.instance.name)
+	}
+	owner, group, err := s.fileOwner(chown)
+	if err != nil {
+		return err
+	}
+	s.files = append(s.files, &k8s.File{
+		Source:  src,
+		Dest:    dest,
+		Owner:   owner,
+		Group:   group,
+		Symlink: target,
+	})
+	return nil
+}
+
 func (s *storage) addFileToInstance(dstPath, dest, chown string) error {
 	srcInfo, err := os.Stat(dstPath)
 	if os.IsNotExist(err) || srcInfo.IsDir() {
 		return ErrSrcDoesNotExistOrIsDirectory.WithParams(dstPath).Wrap(err)
 	}
+	// the files too large for a configmap are uploaded to Minio when the instance is deployed
+	if srcInfo.Size() > maxFilesShardSize && s.instance.MinioClient == nil {
+		return ErrFileTooLargeForConfigMap.WithParams(dstPath, maxFilesShardSize)
+	}
 
 	file := s.instance.K8sClient.NewFile(dstPath, dest)
+	owner, group, err := s.fileOwner(chown)
+	if err != nil {
+		return err
+	}
+	file.Owner, file.Group = owner, group
+	file.Mode = srcInfo.Mode().Perm()
+	s.files = append(s.files, file)
+	return nil
+}
+
+// fileOwner returns the user and the group IDs of a file from its chown argument.
+// The group of the files is the fsGroup of the pod, so all the files must have the same group.
+func (s *storage) fileOwner(chown string) (owner, group int64, err error) {
 	parts := strings.Split(chown, ":")
 	if len(parts) != 2 {
-		return ErrInvalidFormat
+		return 0, 0, ErrInvalidFormat
 	}
 
-	group, err := strconv.ParseInt(parts[1], 10, 64)
+	owner, err = strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, ErrFailedToConvertToInt64.Wrap(err)
+	}
+	group, err = strconv.ParseInt(parts[1], 10, 64)
 	if err != nil {
-		return ErrFailedToConvertToInt64.Wrap(err)
+		return 0, 0, ErrFailedToConvertToInt64.Wrap(err)
 	}
 
 	if s.fsGroup != 0 && s.fsGroup != group {
-		return ErrAllFilesMustHaveSameGroup
+		return 0, 0, ErrAllFilesMustHaveSameGroup
 	}
 	s.fsGroup = group
-	s.files = append(s.files, file)
-	return nil
+	return owner, group, nil
 }
 
 // checkStateForAddingFile checks if the current state allows adding a file
@@ -301,81 +492,199 @@ func (s *storage) checkStateForAddingFile() error {
 	return nil
 }
 
-// deployVolume deploys the volume for the instance
-func (s *storage) deployVolume(ctx context.Context) error {
-	totalSize := resource.Quantity{}
+// deployVolumes deploys a claim for each volume of the instance
+func (s *storage) deployVolumes(ctx context.Context) error {
 	for _, volume := range s.volumes {
-		totalSize.Add(volume.Size)
+		claimName := k8s.VolumeClaimName(s.instance.name, volume.Name)
+		labels := s.instance.execution.Labels()
+		labels[labelVolumeKey] = volume.Name
+		err := s.instance.K8sClient.CreatePersistentVolumeClaim(ctx, claimName, labels, volume.Size, volume.StorageClass, volume.AccessMode)
+		if err != nil {
+			return ErrFailedToCreatePersistentVolumeClaim.WithParams(claimName).Wrap(err)
+		}
+		s.instance.Logger.WithFields(logrus.Fields{
+			"claim":    claimName,
+			"size":     volume.Size.String(),
+			"instance": s.instance.name,
+		}).Debug("deployed persistent volume")
 	}
-	s.instance.K8sClient.CreatePersistentVolumeClaim(ctx, s.instance.name, s.instance.execution.Labels(), totalSize)
-	s.instance.Logger.WithFields(logrus.Fields{
-		"total_size": totalSize.String(),
-		"instance":   s.instance.name,
-	}).Debug("deployed persistent volume")
+	return nil
+}
 
+// destroyVolumes destroys the claims of the volumes of the instance
+func (s *storage) destroyVolumes(ctx context.Context) error {
+	for _, volume := range s.volumes {
+		claimName := k8s.VolumeClaimName(s.instance.name, volume.Name)
+		if err := s.instance.K8sClient.DeletePersistentVolumeClaim(ctx, claimName); err != nil {
+			return ErrFailedToDeletePersistentVolumeClaim.Wrap(err)
+		}
+	}
+	s.instance.Logger.WithField("instance", s.instance.name).Debug("destroyed persistent volumes")
 	return nil
 }
 
-// destroyVolume destroys the volume for the instance
-func (s *storage) destroyVolume(ctx context.Context) error {
-	err := s.instance.K8sClient.DeletePersistentVolumeClaim(ctx, s.instance.name)
+// destroyReplicaVolumes destroys the volumes claimed by the replicas of the StatefulSet of the instance,
+// which are kept when the StatefulSet is deleted
+func (s *storage) destroyReplicaVolumes(ctx context.Context) error {
+	pvcs, err := s.instance.K8sClient.ListPersistentVolumeClaims(ctx, map[string]string{
+		labelScopeKey: s.instance.Scope,
+		labelNameKey:  s.instance.name,
+	})
 	if err != nil {
-		return ErrFailedToDeletePersistentVolumeClaim.Wrap(err)
+		return err
+	}
+	for _, pvc := range pvcs {
+		if err := s.instance.K8sClient.DeletePersistentVolumeClaim(ctx, pvc.Name); err != nil {
+			return ErrFailedToDeletePersistentVolumeClaim.Wrap(err)
+		}
 	}
-	s.instance.Logger.WithField("instance", s.instance.name).Debug("destroyed persistent volume")
+	s.instance.Logger.WithFields(logrus.Fields{
+		"instance": s.instance.name,
+		"claims":   len(pvcs),
+	}).Debug("destroyed persistent volumes of the replicas")
 	return nil
 }
 
-// deployFiles deploys the files for the instance
+// deployFiles deploys the files for the instance.
+// The files are stored in ConfigMaps, sharded so that each ConfigMap stays below the size limit of Kubernetes,
+// and the files too large for a ConfigMap are uploaded to Minio in a tar archive that an init container extracts.
 func (s *storage) deployFiles(ctx context.Context) error {
-	data := map[string]string{}
-
+	var (
+		shards       []map[string]string
+		binaryShards []map[string][]byte
+		sizes        []int
+		remote       []*k8s.File
+	)
 	for i, file := range s.files {
-		// read out file content and assign to variable
-		srcFile, err := os.Open(file.Source)
+		// the symlinks are created by the init container
+		if file.Symlink != "" {
+			continue
+		}
+		info, err := os.Stat(file.Source)
 		if err != nil {
 			return ErrFailedToOpenFile.Wrap(err)
 		}
-		defer srcFile.Close()
+		if info.Size() > maxFilesShardSize {
+			file.Remote, file.Shard = true, 0
+			remote = append(remote, file)
+			continue
+		}
 
-		fileContentBytes, err := io.ReadAll(srcFile)
+		fileContentBytes, err := os.ReadFile(file.Source)
 		if err != nil {
 			return ErrFailedToReadFile.Wrap(err)
 		}
-
-		var (
-			fileContent = string(fileContentBytes)
-			keyName     = fmt.Sprintf("%d", i)
-		)
-
-		data[keyName] = fileContent
+		// the file goes to the first shard with enough room left
+		shard := 0
+		for shard < len(shards) && sizes[shard]+len(fileContentBytes) > maxFilesShardSize {
+			shard++
+		}
+		if shard == len(shards) {
+			shards = append(shards, map[string]string{})
+			binaryShards = append(binaryShards, map[string][]byte{})
+			sizes = append(sizes, 0)
+		}
+		// the data of a configmap has to be UTF-8, the other files are kept as they are in the binary data
+		if key := fmt.Sprintf("%d", i); utf8.Valid(fileContentBytes) {
+			shards[shard][key] = string(fileContentBytes)
+		} else {
+			binaryShards[shard][key] = fileContentBytes
+		}
+		sizes[shard] += len(fileContentBytes)
+		file.Remote, file.Shard = false, shard
 	}
 
 	// If the configmap already exists, we update it
 	// This ensures long-running tests and image upgrade tests function correctly.
-	_, err := s.instance.K8sClient.CreateOrUpdateConfigMap(ctx, s.instance.name, s.instance.execution.Labels(), data)
-	if err != nil {
-		return ErrFailedToCreateConfigMap.Wrap(err)
+	for shard, data := range shards {
+		name := k8s.FilesConfigMapName(s.instance.name, shard)
+		_, err := s.instance.K8sClient.CreateOrUpdateConfigMapWithBinaryData(ctx, name, s.instance.execution.Labels(), data, binaryShards[shard])
+		if err != nil {
+			return ErrFailedToCreateConfigMap.Wrap(err)
+		}
+		s.instance.Logger.WithField("configmap", name).Debug("deployed configmap")
 	}
 
-	s.instance.Logger.WithField("configmap", s.instance.name).Debug("deployed configmap")
+	if len(remote) != 0 {
+		url, err := s.uploadRemoteFiles(ctx, remote)
+		if err != nil {
+			return err
+		}
+		s.filesArchiveURL = url
+	}
 	return nil
 }
 
+// uploadRemoteFiles uploads the tar archive of the given files to Minio, and returns the URL to download it.
+// The URL does not expire, the files init container signs its request with the credentials of Minio.
+// The archive contains each file under its key, and is written while it is uploaded, so it does not have to fit in memory.
+func (s *storage) uploadRemoteFiles(ctx context.Context, files []*k8s.File) (string, error) {
+	if s.instance.MinioClient == nil {
+		return "", ErrFileTooLargeForConfigMap.WithParams(files[0].Source, maxFilesShardSize)
+	}
+
+	keys := make(map[*k8s.File]string, len(files))
+	for i, file := range s.files {
+		keys[file] = fmt.Sprintf("%d", i)
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeFilesTar(pw, files, keys))
+	}()
+	object := s.filesArchiveObject()
+	err := s.instance.MinioClient.Push(ctx, pr, object, filesBucketName)
+	pr.CloseWithError(err)
+	if err != nil {
+		return "", ErrUploadingFiles.WithParams(s.instance.name).Wrap(err)
+	}
+
+	url, err := s.instance.MinioClient.ObjectURL(object, filesBucketName)
+	if err != nil {
+		return "", ErrUploadingFiles.WithParams(s.instance.name).Wrap(err)
+	}
+	s.instance.Logger.WithFields(logrus.Fields{
+		"instance": s.instance.name,
+		"files":    len(files),
+	}).Debug("uploaded files too large for a configmap")
+	return url, nil
+}
+
 // destroyFiles destroys the files for the instance
 func (s *storage) destroyFiles(ctx context.Context) error {
-	if err := s.instance.K8sClient.DeleteConfigMap(ctx, s.instance.name); err != nil {
-		return ErrFailedToDeleteConfigMap.Wrap(err)
+	destroyed := make(map[int]bool)
+	for _, file := range s.files {
+		if file.Remote || file.Symlink != "" || destroyed[file.Shard] {
+			continue
+		}
+		destroyed[file.Shard] = true
+
+		name := k8s.FilesConfigMapName(s.instance.name, file.Shard)
+		if err := s.instance.K8sClient.DeleteConfigMap(ctx, name); err != nil {
+			return ErrFailedToDeleteConfigMap.Wrap(err)
+		}
+		s.instance.Logger.WithField("configmap", name).Debug("destroyed configmap")
 	}
 
-	s.instance.Logger.WithField("configmap", s.instance.name).Debug("destroyed configmap")
+	if s.filesArchiveURL != "" {
+		object := s.filesArchiveObject()
+		if err := s.instance.MinioClient.Delete(ctx, object, filesBucketName); err != nil {
+			return ErrDeletingFilesArchive.WithParams(s.instance.name).Wrap(err)
+		}
+		s.filesArchiveURL = ""
+		s.instance.Logger.WithField("object", object).Debug("destroyed files archive")
+	}
 	return nil
 }
 
+// filesArchiveObject returns the name of the Minio object holding the archive of the files too large for a ConfigMap
+func (s *storage) filesArchiveObject() string {
+	return fmt.Sprintf("%s/%s/files.tar", s.instance.Scope, s.instance.name)
+}
+
 func (s *storage) readFileFromImage(ctx context.Context, filePath string) ([]byte, error) {
 	// Another way to implement this is to download all the layers of the image and then
 	// extract the file from them, but it seems hacky and will run on the user's machine.
-	// Therefore, we will use the tmp instance to get the file from the image
+	// Therefore, we will use a tmp instance that prints the file from the image
 
 	tmpName, err := names.NewRandomK8("tmp-dl")
 	if err != nil {
@@ -390,28 +699,36 @@ func (s *storage) readFileFromImage(ctx context.Context, filePath string) ([]byt
 		return nil, err
 	}
 
-	if err := ti.build.SetStartCommand("sleep", "infinity"); err != nil {
+	if err := ti.build.SetStartCommand("cat", filePath); err != nil {
 		return nil, err
 	}
-
-	if err := ti.build.Commit(ctx); err != nil {
+	if err := ti.execution.SetWorkloadKind(WorkloadJob); err != nil {
 		return nil, err
 	}
 
-	if err := ti.execution.Start(ctx); err != nil {
+	if err := ti.build.Commit(ctx); err != nil {
 		return nil, err
 	}
+
 	defer func() {
+		// nothing has been deployed if the run could not be started
+		if !ti.IsInState(StateStopped) {
+			return
+		}
 		if err := ti.execution.Destroy(ctx); err != nil {
 			ti.Logger.Errorf("failed to destroy tmp instance %s: %v", ti.name, err)
 		}
 	}()
 
-	output, err := ti.execution.ExecuteCommand(ctx, "cat", filePath)
+	result, err := ti.execution.Run(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return []byte(output), nil
+
+	if !result.Succeeded {
+		return nil, ErrReadingFileFromImage.WithParams(filePath, s.instance.build.ImageName(), result.Message)
+	}
+	return []byte(result.Logs), nil
 }
 
 func (s *storage) clone() *storage {
@@ -435,10 +752,39 @@ func (s *storage) clone() *storage {
 		}
 	}
 
+	volumeMountsCopy := make([]*k8s.VolumeMount, len(s.volumeMounts))
+	for i, m := range s.volumeMounts {
+		if m != nil {
+			mountCopy := *m
+			volumeMountsCopy[i] = &mountCopy
+		}
+	}
+
+	seedsCopy := make(map[string]VolumeSeed, len(s.seeds))
+	for name, seed := range s.seeds {
+		seedsCopy[name] = seed
+	}
+
+	liveFilesCopy := make([]*liveFile, len(s.liveFiles))
+	for i, f := range s.liveFiles {
+		fileCopy := *f
+		liveFilesCopy[i] = &fileCopy
+	}
+
+	secretFilesCopy := make([]*secretFile, len(s.secretFiles))
+	for i, f := range s.secretFiles {
+		fileCopy := *f
+		secretFilesCopy[i] = &fileCopy
+	}
+
 	return &storage{
-		instance: nil,
-		volumes:  volumesCopy,
-		files:    filesCopy,
-		fsGroup:  s.fsGroup,
+		instance:     nil,
+		volumes:      volumesCopy,
+		volumeMounts: volumeMountsCopy,
+		files:        filesCopy,
+		fsGroup:      s.fsGroup,
+		seeds:        seedsCopy,
+		liveFiles:    liveFilesCopy,
+		secretFiles:  secretFilesCopy,
 	}
 }
@@ -0,0 +1,224 @@
+package instance
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+)
+
+// writeTar writes a tar archive of src, a file or a directory, to the writer.
+// src is archived under the given name, the content of a directory being under name/.
+// With an empty name, only the content of the directory is archived.
+func writeTar(w io.Writer, src, name string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(src, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, file)
+		if err != nil {
+			return err
+		}
+		entryName := path.Join(name, filepath.ToSlash(rel))
+		if rel == "." {
+			if name == "" {
+				return nil
+			}
+			entryName = name
+		}
+
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = entryName
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// readTar extracts the entry with the given name of the tar archive, a file or a directory, to dest.
+// The entries outside of dest are rejected, and the symlinks that point outside of dest are skipped.
+func readTar(r io.Reader, dest, name string, logger *logrus.Logger) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		entryName := strings.TrimSuffix(header.Name, "/")
+		var target string
+		switch {
+		case entryName == name:
+			target = dest
+		case strings.HasPrefix(entryName, name+"/"):
+			target = filepath.Join(dest, filepath.FromSlash(strings.TrimPrefix(entryName, name+"/")))
+		default:
+			return ErrUnexpectedTarEntry.WithParams(header.Name, name)
+		}
+		if !isWithin(dest, target) {
+			return ErrTarEntryOutsideDestination.WithParams(header.Name, dest)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, os.FileMode(header.Mode).Perm()); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if err := writeFile(target, tr, os.FileMode(header.Mode).Perm()); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			linkTarget := header.Linkname
+			if !filepath.IsAbs(linkTarget) {
+				linkTarget = filepath.Join(filepath.Dir(target), linkTarget)
+			}
+			if !isWithin(dest, linkTarget) {
+				logger.WithFields(logrus.Fields{
+					"entry": header.Name,
+					"link":  header.Linkname,
+				}).Warn("skipping symlink that points outside of the destination")
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		default:
+			logger.WithField("entry", header.Name).Debug("skipping entry that is not a file, a directory or a symlink")
+		}
+	}
+}
+
+// renameTar copies the tar archive from the reader to the writer, renaming its entry from to the entry to,
+// the content of a directory being moved with it
+func renameTar(r io.Reader, w io.Writer, from, to string) error {
+	tr := tar.NewReader(r)
+	tw := tar.NewWriter(w)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return tw.Close()
+		}
+		if err != nil {
+			return err
+		}
+
+		switch entryName := strings.TrimSuffix(header.Name, "/"); {
+		case entryName == from:
+			header.Name = to + strings.TrimPrefix(header.Name, from)
+		case strings.HasPrefix(entryName, from+"/"):
+			header.Name = to + "/" + strings.TrimPrefix(header.Name, from+"/")
+		default:
+			return ErrUnexpectedTarEntry.WithParams(header.Name, from)
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// writeFilesTar writes a tar archive of the files to the writer, each file under its key and with its mode.
+// The files without a mode are readable and executable by anyone, like the files mounted from a ConfigMap.
+func writeFilesTar(w io.Writer, files []*k8s.File, keys map[*k8s.File]string) error {
+	tw := tar.NewWriter(w)
+	for _, file := range files {
+		if err := writeFileToTar(tw, file.Source, keys[file], file.Mode); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+func writeFileToTar(tw *tar.Writer, src, name string, mode os.FileMode) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if mode == 0 {
+		mode = 0o777
+	}
+	header := &tar.Header{
+		Name:     name,
+		Typeflag: tar.TypeReg,
+		Mode:     int64(mode.Perm()),
+		Size:     info.Size(),
+		ModTime:  info.ModTime(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+func writeFile(target string, r io.Reader, mode os.FileMode) error {
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// isWithin reports whether the path is dir or is inside of dir
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,125 @@
+package instance
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+)
+
+func TestWriteTar(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "data", "blocks"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data", "blocks", "1.db"), []byte("block"), 0o644))
+	require.NoError(t, os.Symlink("blocks/1.db", filepath.Join(dir, "data", "latest")))
+
+	var buf bytes.Buffer
+	require.NoError(t, writeTar(&buf, dir, ""))
+
+	entries := map[string]string{}
+	tr := tar.NewReader(&buf)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		entries[header.Name] = string(content) + header.Linkname
+	}
+	assert.Equal(t, map[string]string{
+		"data/":            "",
+		"data/blocks/":     "",
+		"data/blocks/1.db": "block",
+		"data/latest":      "blocks/1.db",
+	}, entries)
+}
+
+func TestWriteFilesTar(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "snapshot.db"), []byte{0, 255}, 0o600))
+	file := &k8s.File{Source: filepath.Join(dir, "snapshot.db"), Dest: "/data/snapshot.db", Remote: true}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeFilesTar(&buf, []*k8s.File{file}, map[*k8s.File]string{file: "3"}))
+
+	// the file is stored under its key, with the mode of the files of a configmap
+	tr := tar.NewReader(&buf)
+	header, err := tr.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "3", header.Name)
+	assert.Equal(t, int64(0o777), header.Mode)
+	content, err := io.ReadAll(tr)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 255}, content)
+	_, err = tr.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReadTar(t *testing.T) {
+	t.Parallel()
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "blocks"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "blocks", "1.db"), []byte{0, 1, 2, 255}, 0o600))
+	require.NoError(t, os.Symlink("blocks/1.db", filepath.Join(src, "latest")))
+	require.NoError(t, os.Symlink("/etc/passwd", filepath.Join(src, "passwd")))
+
+	var buf bytes.Buffer
+	require.NoError(t, writeTar(&buf, src, "data"))
+
+	dest := filepath.Join(t.TempDir(), "copy")
+	require.NoError(t, readTar(&buf, dest, "data", logrus.New()))
+	content, err := os.ReadFile(filepath.Join(dest, "blocks", "1.db"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 1, 2, 255}, content)
+	info, err := os.Stat(filepath.Join(dest, "blocks", "1.db"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	link, err := os.Readlink(filepath.Join(dest, "latest"))
+	require.NoError(t, err)
+	assert.Equal(t, "blocks/1.db", link)
+	// a symlink outside of the destination is skipped
+	_, err = os.Lstat(filepath.Join(dest, "passwd"))
+	assert.True(t, os.IsNotExist(err))
+
+	// a single file is extracted to the destination itself
+	buf.Reset()
+	require.NoError(t, writeTar(&buf, filepath.Join(src, "blocks", "1.db"), "1.db"))
+	require.NoError(t, readTar(&buf, filepath.Join(dest, "block.db"), "1.db", logrus.New()))
+	content, err = os.ReadFile(filepath.Join(dest, "block.db"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 1, 2, 255}, content)
+
+	// entries that escape the destination are rejected
+	buf.Reset()
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "data/../../escape", Typeflag: tar.TypeReg, Mode: 0o644}))
+	require.NoError(t, tw.Close())
+	assert.ErrorIs(t, readTar(&buf, dest, "data", logrus.New()), ErrTarEntryOutsideDestination)
+
+	// so are entries of other paths
+	buf.Reset()
+	tw = tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "other", Typeflag: tar.TypeReg, Mode: 0o644}))
+	require.NoError(t, tw.Close())
+	assert.ErrorIs(t, readTar(&buf, dest, "data", logrus.New()), ErrUnexpectedTarEntry)
+}
+
+func TestVolumeSeedGzipped(t *testing.T) {
+	t.Parallel()
+	assert.True(t, SeedFromArchive("snapshot.tar.gz").gzipped())
+	assert.True(t, SeedFromMinio("snapshots", "snapshot.tgz").gzipped())
+	assert.False(t, SeedFromMinio("snapshots", "snapshot.tar").gzipped())
+	assert.False(t, SeedFromDir("snapshot.tar.gz").gzipped())
+}
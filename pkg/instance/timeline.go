@@ -0,0 +1,80 @@
+package instance
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Timeline records the state transitions of instances, in the order they happen
+type Timeline struct {
+	mu          sync.RWMutex
+	transitions []StateTransition
+}
+
+// timelineEntry is a state transition, as exported by WriteJSON
+type timelineEntry struct {
+	Instance string    `json:"instance"`
+	Sidecar  bool      `json:"sidecar,omitempty"`
+	From     string    `json:"from"`
+	To       string    `json:"to"`
+	Time     time.Time `json:"time"`
+}
+
+func NewTimeline() *Timeline {
+	return &Timeline{}
+}
+
+// Record adds a transition to the timeline, it can be registered as a StateObserver
+func (t *Timeline) Record(transition StateTransition) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.transitions = append(t.transitions, transition)
+}
+
+// Transitions returns the recorded transitions, in the order they happened
+func (t *Timeline) Transitions() []StateTransition {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	transitions := make([]StateTransition, len(t.transitions))
+	copy(transitions, t.transitions)
+	return transitions
+}
+
+// Duration returns the time the instance with the given name took to go from the state 'from' to the state 'to',
+// e.g. from 'Committed' to 'Started'. The last time the instance entered 'from' before it first entered 'to' is used.
+// It returns false if the instance did not go through both states.
+func (t *Timeline) Duration(name string, from, to InstanceState) (time.Duration, bool) {
+	var fromTime time.Time
+	for _, tr := range t.Transitions() {
+		if tr.Instance.Name() != name {
+			continue
+		}
+		switch {
+		case tr.To == from:
+			fromTime = tr.Time
+		case tr.To == to && !fromTime.IsZero():
+			return tr.Time.Sub(fromTime), true
+		}
+	}
+	return 0, false
+}
+
+// WriteJSON exports the timeline as a JSON array of transitions
+func (t *Timeline) WriteJSON(w io.Writer) error {
+	transitions := t.Transitions()
+	entries := make([]timelineEntry, 0, len(transitions))
+	for _, tr := range transitions {
+		entries = append(entries, timelineEntry{
+			Instance: tr.Instance.Name(),
+			Sidecar:  tr.Instance.Sidecars().IsSidecar(),
+			From:     tr.From.String(),
+			To:       tr.To.String(),
+			Time:     tr.Time,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
@@ -23,3 +23,14 @@ func (s InstanceType) String() string {
 	return "Unknown"
 
 }
+
+// parseInstanceType returns the type that has the given string representation
+func parseInstanceType(s string) InstanceType {
+	switch s {
+	case BasicInstance.String():
+		return BasicInstance
+	case TimeoutHandlerInstance.String():
+		return TimeoutHandlerInstance
+	}
+	return UnknownInstance
+}
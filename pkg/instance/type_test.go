@@ -42,3 +42,15 @@ func TestInstanceType(t *testing.T) {
 		})
 	}
 }
+
+func TestParseInstanceType(t *testing.T) {
+	t.Parallel()
+	for _, want := range []InstanceType{BasicInstance, TimeoutHandlerInstance, UnknownInstance} {
+		if got := parseInstanceType(want.String()); got != want {
+			t.Errorf("got %q; want %q", got, want)
+		}
+	}
+	if got := parseInstanceType("something-else"); got != UnknownInstance {
+		t.Errorf("got %q; want %q", got, UnknownInstance)
+	}
+}
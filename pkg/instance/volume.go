@@ -0,0 +1,188 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+	"github.com/celestiaorg/knuu/pkg/names"
+)
+
+const (
+	// volumeHelperImage is the image of the pods that access the volumes of stopped instances, it has to provide tar
+	volumeHelperImage = "busybox:1.36"
+	// volumeHelperSleepSeconds is how long a volume helper pod lives if it is not deleted, e.g. when the test is killed
+	volumeHelperSleepSeconds = "3600"
+	// volumesBucketName is the bucket of the Minio of the scope where the volumes are exported and seeded from
+	volumesBucketName = "knuu-volumes"
+)
+
+// ExportVolume writes a tar archive of the content of the volume mounted at the given path to the writer.
+// The archive is streamed, so the volume does not have to fit in memory.
+// The volume of a started instance is read from its first pod, which needs tar in its image.
+// The volume of a stopped instance is read from a helper pod that mounts its claim,
+// the one of the first replica for a StatefulSet. The node-local volumes of a DaemonSet can only be exported while it is started.
+// This function can only be called in the states 'Started' and 'Stopped'
+func (s *storage) ExportVolume(ctx context.Context, path string, w io.Writer) error {
+	if !s.instance.IsInState(StateStarted, StateStopped) {
+		return ErrExportingVolumeNotAllowed.WithParams(s.instance.state.String())
+	}
+	volume := s.volumeAt(path)
+	if volume == nil {
+		return ErrVolumeNotFound.WithParams(path, s.instance.name)
+	}
+
+	cmd := []string{"tar", "-cf", "-", "-C", volume.Path, "."}
+	if s.instance.state == StateStarted {
+		pod, err := s.instance.firstPod(ctx)
+		if err != nil {
+			return ErrExportingVolume.WithParams(path, s.instance.name).Wrap(err)
+		}
+		if err := s.instance.K8sClient.StreamCommandInPod(ctx, pod.Name, s.instance.name, cmd, nil, w); err != nil {
+			return ErrExportingVolume.WithParams(path, s.instance.name).Wrap(err)
+		}
+	} else {
+		err := s.withVolumeHelperPod(ctx, volume, func(podName, containerName string) error {
+			return s.instance.K8sClient.StreamCommandInPod(ctx, podName, containerName, cmd, nil, w)
+		})
+		if err != nil {
+			return ErrExportingVolume.WithParams(path, s.instance.name).Wrap(err)
+		}
+	}
+
+	s.instance.Logger.WithFields(logrus.Fields{
+		"instance": s.instance.name,
+		"volume":   path,
+		"state":    s.instance.state.String(),
+	}).Debug("exported volume")
+	return nil
+}
+
+// ExportVolumeToMinio exports the volume mounted at the given path like ExportVolume,
+// and streams the archive to the Minio of the scope under the given name.
+// It returns the URL to download the archive.
+// This function can only be called in the states 'Started' and 'Stopped'
+func (s *storage) ExportVolumeToMinio(ctx context.Context, path, name string) (string, error) {
+	if s.instance.MinioClient == nil {
+		return "", ErrMinioNotInitialized
+	}
+	key := fmt.Sprintf("%s/%s", s.instance.Scope, name)
+
+	pr, pw := io.Pipe()
+	exportErr := make(chan error, 1)
+	go func() {
+		err := s.ExportVolume(ctx, path, pw)
+		pw.CloseWithError(err)
+		exportErr <- err
+	}()
+	pushErr := s.instance.MinioClient.Push(ctx, pr, key, volumesBucketName)
+	// unblocks the export if the push stopped before reading the whole archive
+	pr.CloseWithError(pushErr)
+	if err := <-exportErr; err != nil {
+		return "", err
+	}
+	if pushErr != nil {
+		return "", ErrPushingVolumeExport.WithParams(path, s.instance.name).Wrap(pushErr)
+	}
+
+	url, err := s.instance.MinioClient.GetURL(ctx, key, volumesBucketName)
+	if err != nil {
+		return "", ErrPushingVolumeExport.WithParams(path, s.instance.name).Wrap(err)
+	}
+	return url, nil
+}
+
+// volumeAt returns the volume mounted at the given path, or nil if there is none
+func (s *storage) volumeAt(path string) *k8s.Volume {
+	for _, v := range s.volumes {
+		if v.Path == path {
+			return v
+		}
+	}
+	return nil
+}
+
+// claimName returns the name of the claim that holds the data of the volume:
+// the one deployed for the instance, or the one of the first replica of a StatefulSet
+func (s *storage) claimName(volume *k8s.Volume) (string, error) {
+	owner := s.instance.podOwner()
+	claimName := k8s.VolumeClaimName(s.instance.name, volume.Name)
+	switch owner.execution.workloadKind {
+	case WorkloadStatefulSet:
+		return k8s.StatefulSetClaimName(claimName, owner.name, 0), nil
+	case WorkloadDaemonSet:
+		return "", ErrVolumeIsNodeLocal.WithParams(volume.Path, s.instance.name)
+	}
+	return claimName, nil
+}
+
+// withVolumeHelperPod runs fn with a helper pod that mounts the claim of the volume at the path of the volume.
+// The pod is deleted once fn returns.
+func (s *storage) withVolumeHelperPod(ctx context.Context, volume *k8s.Volume, fn func(podName, containerName string) error) error {
+	claimName, err := s.claimName(volume)
+	if err != nil {
+		return err
+	}
+	podName, err := names.NewRandomK8(s.instance.name + "-volume")
+	if err != nil {
+		return err
+	}
+
+	// the helper has its own name, so it is not taken for a pod of the instance
+	labels := s.instance.execution.Labels()
+	for _, key := range []string{labelAppKey, labelNameKey, labelK8sNameKey} {
+		labels[key] = podName
+	}
+	delete(labels, labelParentKey)
+
+	helperVolume := *volume
+	helperVolume.ClaimName = claimName
+	_, err = s.instance.K8sClient.DeployPod(ctx, k8s.PodConfig{
+		Namespace: s.instance.K8sClient.Namespace(),
+		Name:      podName,
+		Labels:    labels,
+		FsGroup:   s.fsGroup,
+		ContainerConfig: k8s.ContainerConfig{
+			Name:    podName,
+			Image:   volumeHelperImage,
+			Command: []string{"sleep", volumeHelperSleepSeconds},
+			Volumes: []*k8s.Volume{&helperVolume},
+		},
+	}, false)
+	if err != nil {
+		return ErrDeployingVolumeHelperPod.WithParams(claimName).Wrap(err)
+	}
+	defer func() {
+		if err := s.instance.K8sClient.DeletePod(ctx, podName); err != nil {
+			s.instance.Logger.WithError(err).WithField("pod", podName).Error("failed to delete volume helper pod")
+		}
+	}()
+
+	if err := s.waitForPodRunning(ctx, podName); err != nil {
+		return ErrDeployingVolumeHelperPod.WithParams(claimName).Wrap(err)
+	}
+	return fn(podName, podName)
+}
+
+// waitForPodRunning waits until the pod with the given name is running
+func (s *storage) waitForPodRunning(ctx context.Context, podName string) error {
+	for {
+		running, err := s.instance.K8sClient.IsPodRunning(ctx, podName)
+		if err != nil {
+			return err
+		}
+		if running {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(waitForInstanceRetry):
+		}
+	}
+}
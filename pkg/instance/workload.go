@@ -0,0 +1,50 @@
+package instance
+
+import (
+	"fmt"
+)
+
+// headlessServiceNameSuffix is the suffix of the headless service that gives their DNS names to the pods of a StatefulSet
+const headlessServiceNameSuffix = "-headless"
+
+// WorkloadKind is the kind of Kubernetes workload that runs the pods of an instance
+type WorkloadKind int
+
+// Possible kinds of workload
+const (
+	// WorkloadReplicaSet runs identical replicas, that share the volume of the instance
+	WorkloadReplicaSet WorkloadKind = iota
+	// WorkloadStatefulSet runs replicas with stable names, name-0, name-1..., that each have their own volumes.
+	// The volumes of a replica are kept when the instance is stopped or scaled down, and reused when it comes back.
+	WorkloadStatefulSet
+	// WorkloadJob runs a single pod to completion, with Run instead of Start
+	WorkloadJob
+	// WorkloadDaemonSet runs a pod on every node of the cluster, the number of replicas is ignored.
+	// The volumes are node-local and are lost when the pod is removed.
+	WorkloadDaemonSet
+)
+
+// String returns the string representation of the kind
+func (w WorkloadKind) String() string {
+	switch w {
+	case WorkloadReplicaSet:
+		return "ReplicaSet"
+	case WorkloadStatefulSet:
+		return "StatefulSet"
+	case WorkloadJob:
+		return "Job"
+	case WorkloadDaemonSet:
+		return "DaemonSet"
+	}
+	return "Unknown"
+}
+
+// headlessServiceName returns the name of the headless service of the instance, when it runs as a StatefulSet
+func (i *Instance) headlessServiceName() string {
+	return i.name + headlessServiceNameSuffix
+}
+
+// replicaHostname returns the DNS name of the pod of the given replica of a StatefulSet, within the namespace
+func (i *Instance) replicaHostname(replica int) string {
+	return fmt.Sprintf("%s-%d.%s", i.name, replica, i.headlessServiceName())
+}
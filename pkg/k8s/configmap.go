@@ -18,6 +18,19 @@ func (c *Client) GetConfigMap(ctx context.Context, name string) (*v1.ConfigMap,
 	return cm, nil
 }
 
+// ListConfigMaps returns the configmaps in the namespace that match the given labels.
+func (c *Client) ListConfigMaps(ctx context.Context, labels map[string]string) ([]v1.ConfigMap, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+	selector := metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: labels})
+	cmList, err := c.clientset.CoreV1().ConfigMaps(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, ErrListingConfigmaps.Wrap(err)
+	}
+	return cmList.Items, nil
+}
+
 func (c *Client) ConfigMapExists(ctx context.Context, name string) (bool, error) {
 	_, err := c.clientset.CoreV1().ConfigMaps(c.namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
@@ -42,7 +55,7 @@ func (c *Client) CreateConfigMap(
 		return nil, err
 	}
 
-	cm := prepareConfigMap(c.namespace, name, labels, data)
+	cm := prepareConfigMap(c.namespace, name, labels, data, nil)
 	created, err := c.clientset.CoreV1().ConfigMaps(c.namespace).Create(ctx, cm, metav1.CreateOptions{})
 	if err == nil {
 		return created, nil
@@ -66,7 +79,7 @@ func (c *Client) UpdateConfigMap(
 		return nil, err
 	}
 
-	cm := prepareConfigMap(c.namespace, name, labels, data)
+	cm := prepareConfigMap(c.namespace, name, labels, data, nil)
 	updated, err := c.clientset.CoreV1().ConfigMaps(c.namespace).Update(ctx, cm, metav1.UpdateOptions{})
 	if err == nil {
 		return updated, nil
@@ -94,6 +107,40 @@ func (c *Client) CreateOrUpdateConfigMap(
 	return nil, ErrUpdatingConfigmap.WithParams(name).Wrap(err)
 }
 
+// CreateOrUpdateConfigMapWithBinaryData creates the configmap with the given data and binary data,
+// or replaces the labels and the data of the existing one.
+// The binary data holds the content that is not UTF-8 text, which cannot be stored in the data.
+func (c *Client) CreateOrUpdateConfigMapWithBinaryData(
+	ctx context.Context, name string,
+	labels, data map[string]string, binaryData map[string][]byte,
+) (*v1.ConfigMap, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+
+	if err := validateConfigMap(name, labels, data); err != nil {
+		return nil, err
+	}
+	if err := validateConfigMapBinaryKeys(data, binaryData); err != nil {
+		return nil, err
+	}
+
+	cm := prepareConfigMap(c.namespace, name, labels, data, binaryData)
+	updated, err := c.clientset.CoreV1().ConfigMaps(c.namespace).Update(ctx, cm, metav1.UpdateOptions{})
+	if err == nil {
+		return updated, nil
+	}
+	if !apierrs.IsNotFound(err) {
+		return nil, ErrUpdatingConfigmap.WithParams(name).Wrap(err)
+	}
+
+	created, err := c.clientset.CoreV1().ConfigMaps(c.namespace).Create(ctx, cm, metav1.CreateOptions{})
+	if err != nil {
+		return nil, ErrCreatingConfigmap.WithParams(name).Wrap(err)
+	}
+	return created, nil
+}
+
 func (c *Client) DeleteConfigMap(ctx context.Context, name string) error {
 	exists, err := c.ConfigMapExists(ctx, name)
 	if err != nil {
@@ -114,6 +161,7 @@ func (c *Client) DeleteConfigMap(ctx context.Context, name string) error {
 func prepareConfigMap(
 	namespace, name string,
 	labels, data map[string]string,
+	binaryData map[string][]byte,
 ) *v1.ConfigMap {
 	return &v1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -121,6 +169,7 @@ func prepareConfigMap(
 			Namespace: namespace,
 			Labels:    labels,
 		},
-		Data: data,
+		Data:       data,
+		BinaryData: binaryData,
 	}
 }
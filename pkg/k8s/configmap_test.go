@@ -266,3 +266,58 @@ func (s *TestSuite) TestDeleteConfigMap() {
 		})
 	}
 }
+
+func (s *TestSuite) TestCreateOrUpdateConfigMapWithBinaryData() {
+	tests := []struct {
+		name          string
+		configMapName string
+		data          map[string]string
+		binaryData    map[string][]byte
+		setupMock     func()
+		expectedErr   error
+	}{
+		{
+			name:          "successful creation",
+			configMapName: "new-binary-configmap",
+			data:          map[string]string{"0": "text"},
+			binaryData:    map[string][]byte{"1": {0, 255}},
+			setupMock:     func() {},
+		},
+		{
+			name:          "successful update",
+			configMapName: "existing-binary-configmap",
+			binaryData:    map[string][]byte{"0": {1, 254}},
+			setupMock: func() {
+				err := s.createConfigMap("existing-binary-configmap")
+				s.Require().NoError(err)
+			},
+		},
+		{
+			name:          "key in data and binary data",
+			configMapName: "duplicate-key-configmap",
+			data:          map[string]string{"0": "text"},
+			binaryData:    map[string][]byte{"0": {0, 255}},
+			setupMock:     func() {},
+			expectedErr:   k8s.ErrInvalidConfigMapKey,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.setupMock()
+
+			_, err := s.client.CreateOrUpdateConfigMapWithBinaryData(context.Background(), tt.configMapName, nil, tt.data, tt.binaryData)
+			if tt.expectedErr != nil {
+				s.Require().Error(err)
+				s.Assert().ErrorIs(err, tt.expectedErr)
+				return
+			}
+
+			s.Require().NoError(err)
+			cm, err := s.client.GetConfigMap(context.Background(), tt.configMapName)
+			s.Require().NoError(err)
+			s.Assert().Equal(tt.data, cm.Data)
+			s.Assert().Equal(tt.binaryData, cm.BinaryData)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package k8s
 
 import (
 	"context"
+	"sort"
 
 	"github.com/sirupsen/logrus"
 	appv1 "k8s.io/api/apps/v1"
@@ -10,6 +11,13 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+type DaemonSetConfig struct {
+	Name      string            // Name of the DaemonSet
+	Namespace string            // Namespace of the DaemonSet
+	Labels    map[string]string // Labels to apply to the DaemonSet, key/value represents the name/value of the label
+	PodConfig PodConfig         // PodConfig represents the pod configuration
+}
+
 func (c *Client) DaemonSetExists(ctx context.Context, name string) (bool, error) {
 	_, err := c.clientset.AppsV1().DaemonSets(c.namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
@@ -60,6 +68,75 @@ func (c *Client) CreateDaemonSet(
 	return created, nil
 }
 
+// DeployDaemonSet creates a DaemonSet that runs a pod built from the pod config on every node,
+// in the namespace that k8s is initialized with.
+// As the pods run on different nodes, their volumes are node-local empty directories instead of a shared claim.
+func (c *Client) DeployDaemonSet(ctx context.Context, dsConfig DaemonSetConfig, init bool) (*appv1.DaemonSet, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+	dsConfig.Namespace = c.namespace
+	if err := validateDaemonSetConfig(dsConfig); err != nil {
+		return nil, err
+	}
+	ds := c.prepareDaemonSetFromConfig(dsConfig, init)
+
+	created, err := c.clientset.AppsV1().DaemonSets(c.namespace).Create(ctx, ds, metav1.CreateOptions{})
+	if err != nil {
+		return nil, ErrCreatingDaemonset.WithParams(dsConfig.Name).Wrap(err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"name":      dsConfig.Name,
+		"namespace": c.namespace,
+	}).Debug("daemonSet deployed")
+	return created, nil
+}
+
+// IsDaemonSetRunning returns true if the pods of the DaemonSet are ready on all the nodes they are scheduled on
+func (c *Client) IsDaemonSetRunning(ctx context.Context, name string) (bool, error) {
+	ds, err := c.GetDaemonSet(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	return ds.Status.DesiredNumberScheduled > 0 &&
+		ds.Status.NumberReady == ds.Status.DesiredNumberScheduled, nil
+}
+
+// ListDaemonSets returns the DaemonSets in the namespace that match the given labels.
+func (c *Client) ListDaemonSets(ctx context.Context, labels map[string]string) ([]appv1.DaemonSet, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+	selector := metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: labels})
+	dsList, err := c.clientset.AppsV1().DaemonSets(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, ErrListingDaemonSets.Wrap(err)
+	}
+	return dsList.Items, nil
+}
+
+// ListPodsFromDaemonSet returns the pods of the DaemonSet, sorted by the name of their node.
+func (c *Client) ListPodsFromDaemonSet(ctx context.Context, name string) ([]v1.Pod, error) {
+	ds, err := c.GetDaemonSet(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	selector := metav1.FormatLabelSelector(ds.Spec.Selector)
+	pods, err := c.clientset.CoreV1().Pods(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, ErrListingPodsForDaemonSet.WithParams(name).Wrap(err)
+	}
+
+	sort.Slice(pods.Items, func(a, b int) bool {
+		if pods.Items[a].Spec.NodeName != pods.Items[b].Spec.NodeName {
+			return pods.Items[a].Spec.NodeName < pods.Items[b].Spec.NodeName
+		}
+		return pods.Items[a].Name < pods.Items[b].Name
+	})
+	return pods.Items, nil
+}
+
 func (c *Client) UpdateDaemonSet(ctx context.Context,
 	name string,
 	labels map[string]string,
@@ -127,3 +204,37 @@ func prepareDaemonSet(
 		},
 	}
 }
+
+func (c *Client) prepareDaemonSetFromConfig(dsConf DaemonSetConfig, init bool) *appv1.DaemonSet {
+	podSpec := c.preparePodSpec(dsConf.PodConfig, init)
+	for n, volume := range podSpec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			podSpec.Volumes[n].VolumeSource = v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}
+		}
+	}
+
+	ds := &appv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: dsConf.Namespace,
+			Name:      dsConf.Name,
+			Labels:    dsConf.Labels,
+		},
+		Spec: appv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: dsConf.Labels},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   dsConf.Namespace,
+					Labels:      dsConf.Labels,
+					Annotations: dsConf.PodConfig.Annotations,
+				},
+				Spec: podSpec,
+			},
+		},
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"name":      dsConf.Name,
+		"namespace": dsConf.Namespace,
+	}).Debug("prepared daemonSet")
+	return ds
+}
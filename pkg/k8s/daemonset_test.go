@@ -6,6 +6,7 @@ import (
 
 	appv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
@@ -397,3 +398,64 @@ func (s *TestSuite) TestDeleteDaemonSet() {
 		})
 	}
 }
+
+func (s *TestSuite) TestDeployDaemonSet() {
+	dsConfig := k8s.DaemonSetConfig{
+		Name:   "capture",
+		Labels: map[string]string{"app": "capture"},
+		PodConfig: k8s.PodConfig{
+			Namespace: s.namespace,
+			Name:      "capture",
+			Labels:    map[string]string{"app": "capture"},
+			ContainerConfig: k8s.ContainerConfig{
+				Name:    "capture",
+				Image:   "alpine:latest",
+				Volumes: []*k8s.Volume{{Name: "data", Path: "/data", Size: resource.MustParse("1Gi")}},
+			},
+		},
+	}
+
+	ds, err := s.client.DeployDaemonSet(context.Background(), dsConfig, true)
+	s.Require().NoError(err)
+	s.Assert().Equal(s.namespace, ds.Namespace)
+	s.Assert().Equal(dsConfig.Labels, ds.Spec.Selector.MatchLabels)
+	// the volumes are node-local, as a claim can not be shared by the pods of all the nodes
+	s.Require().Len(ds.Spec.Template.Spec.Volumes, 1)
+	s.Assert().Nil(ds.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim)
+	s.Assert().NotNil(ds.Spec.Template.Spec.Volumes[0].EmptyDir)
+
+	_, err = s.client.DeployDaemonSet(context.Background(), dsConfig, true)
+	s.Assert().ErrorIs(err, k8s.ErrCreatingDaemonset)
+
+	dsConfig.Name = "invalid_name"
+	_, err = s.client.DeployDaemonSet(context.Background(), dsConfig, true)
+	s.Assert().ErrorIs(err, k8s.ErrInvalidDaemonSetName)
+}
+
+func (s *TestSuite) TestListPodsFromDaemonSet() {
+	labels := map[string]string{"app": "agent"}
+	_, err := s.client.Clientset().AppsV1().DaemonSets(s.namespace).Create(context.Background(), &appv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: s.namespace, Labels: labels},
+		Spec:       appv1.DaemonSetSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+		Status:     appv1.DaemonSetStatus{DesiredNumberScheduled: 2, NumberReady: 2},
+	}, metav1.CreateOptions{})
+	s.Require().NoError(err)
+
+	for name, node := range map[string]string{"agent-abcde": "node-b", "agent-fghij": "node-a"} {
+		_, err := s.client.Clientset().CoreV1().Pods(s.namespace).Create(context.Background(), &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: s.namespace, Labels: labels},
+			Spec:       v1.PodSpec{NodeName: node},
+		}, metav1.CreateOptions{})
+		s.Require().NoError(err)
+	}
+
+	pods, err := s.client.ListPodsFromDaemonSet(context.Background(), "agent")
+	s.Require().NoError(err)
+	s.Require().Len(pods, 2)
+	s.Assert().Equal("node-a", pods[0].Spec.NodeName)
+	s.Assert().Equal("node-b", pods[1].Spec.NodeName)
+
+	running, err := s.client.IsDaemonSetRunning(context.Background(), "agent")
+	s.Require().NoError(err)
+	s.Assert().True(running)
+}
@@ -0,0 +1,329 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/yaml"
+)
+
+// DryRunClient is a KubeManager that records the objects knuu would create instead of applying them to a cluster.
+// It runs on in-memory clients, so the objects are built by the same code as with a cluster,
+// and the workloads are reported as running as soon as they are created.
+// Commands run in the pods return an empty output.
+type DryRunClient struct {
+	*Client
+
+	mu sync.Mutex
+	// objects holds the last version of each object, in the order they were first created
+	objects []map[string]interface{}
+	index   map[string]int
+}
+
+var _ KubeManager = &DryRunClient{}
+
+// redactedSecretValue replaces the values of the secrets in the recorded objects
+const redactedSecretValue = "REDACTED"
+
+func NewDryRunClient(ctx context.Context, namespace string, logger *logrus.Logger) (*DryRunClient, error) {
+	cs := fake.NewSimpleClientset()
+	dC := dynfake.NewSimpleDynamicClient(runtime.NewScheme())
+
+	d := &DryRunClient{index: make(map[string]int)}
+	for _, verb := range []string{"create", "update", "patch"} {
+		cs.PrependReactor(verb, "*", d.recorder(cs.Tracker()))
+		dC.PrependReactor(verb, "*", d.recorder(dC.Tracker()))
+	}
+
+	kc, err := NewClientCustom(ctx, cs, cs.Discovery(), dC, namespace, logger)
+	if err != nil {
+		return nil, err
+	}
+	d.Client = kc
+	return d, nil
+}
+
+// recorder applies the action to the in-memory objects and records the resulting object
+func (d *DryRunClient) recorder(tracker k8stesting.ObjectTracker) k8stesting.ReactionFunc {
+	react := k8stesting.ObjectReaction(tracker)
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "" {
+			return false, nil, nil
+		}
+		handled, obj, err := react(action)
+		if err != nil || obj == nil {
+			return handled, obj, err
+		}
+		if err := d.record(obj.DeepCopyObject()); err != nil {
+			return true, nil, ErrRecordingObject.Wrap(err)
+		}
+		return handled, obj, nil
+	}
+}
+
+func (d *DryRunClient) record(obj runtime.Object) error {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if gvk.Empty() {
+		kinds, _, err := scheme.Scheme.ObjectKinds(obj)
+		if err != nil {
+			return err
+		}
+		gvk = kinds[0]
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	content["apiVersion"], content["kind"] = gvk.ToAPIVersionAndKind()
+	// the status and the fields set by the server are not part of what knuu applies
+	delete(content, "status")
+	if metadata, ok := content["metadata"].(map[string]interface{}); ok {
+		for _, field := range []string{"creationTimestamp", "resourceVersion", "uid", "generation", "managedFields"} {
+			delete(metadata, field)
+		}
+	}
+	if gvk.Kind == "Secret" {
+		redactSecret(content)
+	}
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	key := objectKey(gvk, accessor.GetNamespace(), accessor.GetName())
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if n, ok := d.index[key]; ok {
+		d.objects[n] = content
+		return nil
+	}
+	d.index[key] = len(d.objects)
+	d.objects = append(d.objects, content)
+	return nil
+}
+
+// redactSecret replaces the values of the secret with redactedSecretValue, so the manifests do not leak them.
+// The keys are kept in the string data, so the manifest stays valid and shows what the secret holds.
+func redactSecret(content map[string]interface{}) {
+	redacted := make(map[string]interface{})
+	for _, field := range []string{"data", "stringData"} {
+		values, _ := content[field].(map[string]interface{})
+		for key := range values {
+			redacted[key] = redactedSecretValue
+		}
+		delete(content, field)
+	}
+	if len(redacted) != 0 {
+		content["stringData"] = redacted
+	}
+}
+
+func objectKey(gvk schema.GroupVersionKind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", gvk.GroupKind(), namespace, name)
+}
+
+// Objects returns the recorded objects, in the order they were first created.
+// An object that has been updated is returned as last updated, and deleted objects are kept.
+func (d *DryRunClient) Objects() []map[string]interface{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	objects := make([]map[string]interface{}, 0, len(d.objects))
+	for _, o := range d.objects {
+		objects = append(objects, runtime.DeepCopyJSON(o))
+	}
+	return objects
+}
+
+// WriteManifests writes the recorded objects as a multi-document YAML, in the order they were first created.
+func (d *DryRunClient) WriteManifests(w io.Writer) error {
+	for _, obj := range d.Objects() {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return ErrWritingManifests.Wrap(err)
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", data); err != nil {
+			return ErrWritingManifests.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// CustomResourceDefinitionExists always returns true, as any custom resource can be rendered
+func (d *DryRunClient) CustomResourceDefinitionExists(ctx context.Context, gvr *schema.GroupVersionResource) (bool, error) {
+	return true, nil
+}
+
+// GetFirstPodFromReplicaSet returns a pod built from the template of the ReplicaSet,
+// as no pod is created for it in dry-run mode.
+func (d *DryRunClient) GetFirstPodFromReplicaSet(ctx context.Context, name string) (*v1.Pod, error) {
+	rs, err := d.getReplicaSet(ctx, name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return templatePod(&rs.Spec.Template, rs.Namespace, rs.Name), nil
+}
+
+// ListPodsFromReplicaSet returns a pod built from the template of the ReplicaSet for each of its replicas
+func (d *DryRunClient) ListPodsFromReplicaSet(ctx context.Context, name string) ([]v1.Pod, error) {
+	rs, err := d.getReplicaSet(ctx, name)
+	if err != nil {
+		return nil, ErrGettingReplicaSet.WithParams(name).Wrap(err)
+	}
+	replicas := int32(1)
+	if rs.Spec.Replicas != nil {
+		replicas = *rs.Spec.Replicas
+	}
+	pods := make([]v1.Pod, 0, replicas)
+	for n := int32(0); n < replicas; n++ {
+		pods = append(pods, *templatePod(&rs.Spec.Template, rs.Namespace, fmt.Sprintf("%s-%d", rs.Name, n)))
+	}
+	return pods, nil
+}
+
+// ListPodsFromStatefulSet returns a pod built from the template of the StatefulSet for each of its replicas
+func (d *DryRunClient) ListPodsFromStatefulSet(ctx context.Context, name string) ([]v1.Pod, error) {
+	ss, err := d.getStatefulSet(ctx, name)
+	if err != nil {
+		return nil, ErrGettingStatefulSet.WithParams(name).Wrap(err)
+	}
+	replicas := int32(1)
+	if ss.Spec.Replicas != nil {
+		replicas = *ss.Spec.Replicas
+	}
+	pods := make([]v1.Pod, 0, replicas)
+	for n := int32(0); n < replicas; n++ {
+		pods = append(pods, *templatePod(&ss.Spec.Template, ss.Namespace, fmt.Sprintf("%s-%d", ss.Name, n)))
+	}
+	return pods, nil
+}
+
+// ListPodsFromDaemonSet returns a single pod built from the template of the DaemonSet,
+// as if the cluster had a single node
+func (d *DryRunClient) ListPodsFromDaemonSet(ctx context.Context, name string) ([]v1.Pod, error) {
+	ds, err := d.GetDaemonSet(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return []v1.Pod{*templatePod(&ds.Spec.Template, ds.Namespace, name+"-0")}, nil
+}
+
+func templatePod(template *v1.PodTemplateSpec, namespace, name string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    template.Labels,
+		},
+		Spec:   template.Spec,
+		Status: v1.PodStatus{Phase: v1.PodRunning},
+	}
+}
+
+// IsPodRunning returns true if the pod has been created
+func (d *DryRunClient) IsPodRunning(ctx context.Context, name string) (bool, error) {
+	_, err := d.getPod(ctx, name)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// IsReplicaSetRunning returns true if the ReplicaSet has been created
+func (d *DryRunClient) IsReplicaSetRunning(ctx context.Context, name string) (bool, error) {
+	return d.ReplicaSetExists(ctx, name)
+}
+
+// IsStatefulSetRunning returns true if the StatefulSet has been created
+func (d *DryRunClient) IsStatefulSetRunning(ctx context.Context, name string) (bool, error) {
+	return d.StatefulSetExists(ctx, name)
+}
+
+// IsDaemonSetRunning returns true if the DaemonSet has been created
+func (d *DryRunClient) IsDaemonSetRunning(ctx context.Context, name string) (bool, error) {
+	return d.DaemonSetExists(ctx, name)
+}
+
+func (d *DryRunClient) PortForwardPod(ctx context.Context, podName string, localPort, remotePort int) error {
+	return nil
+}
+
+// RunCommandInPod does not run anything and returns an empty output
+func (d *DryRunClient) RunCommandInPod(ctx context.Context, podName, containerName string, cmd []string) (string, error) {
+	d.logger.WithFields(logrus.Fields{
+		"pod":       podName,
+		"container": containerName,
+		"command":   cmd,
+	}).Debug("dry run: command not run")
+	return "", nil
+}
+
+// StreamCommandInPod does not run anything and writes no output
+func (d *DryRunClient) StreamCommandInPod(ctx context.Context, podName, containerName string, cmd []string, stdin io.Reader, stdout io.Writer) error {
+	d.logger.WithFields(logrus.Fields{
+		"pod":       podName,
+		"container": containerName,
+		"command":   cmd,
+	}).Debug("dry run: command not run")
+	return nil
+}
+
+// WaitForJob returns the Job as completed, as it is not run
+func (d *DryRunClient) WaitForJob(ctx context.Context, name string) (*batchv1.Job, error) {
+	job, err := d.GetJob(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	job.Status.Succeeded = 1
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+	return job, nil
+}
+
+// ListPodsFromJob returns a pod built from the template of the Job, that has completed
+func (d *DryRunClient) ListPodsFromJob(ctx context.Context, name string) ([]v1.Pod, error) {
+	job, err := d.GetJob(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	pod := templatePod(&job.Spec.Template, job.Namespace, job.Name)
+	pod.Status.Phase = v1.PodSucceeded
+	for _, container := range pod.Spec.Containers {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, v1.ContainerStatus{
+			Name:  container.Name,
+			State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Completed"}},
+		})
+	}
+	return []v1.Pod{*pod}, nil
+}
+
+// GetPodLogStream returns empty logs, as nothing is run
+func (d *DryRunClient) GetPodLogStream(ctx context.Context, podName string, logOptions v1.PodLogOptions) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func (d *DryRunClient) WaitForDeployment(ctx context.Context, name string) error {
+	return nil
+}
+
+func (d *DryRunClient) WaitForService(ctx context.Context, name string) error {
+	return nil
+}
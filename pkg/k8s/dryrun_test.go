@@ -0,0 +1,61 @@
+package k8s_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+)
+
+func TestDryRunClient(t *testing.T) {
+	ctx := context.Background()
+	client, err := k8s.NewDryRunClient(ctx, "dry-run", logrus.New())
+	require.NoError(t, err)
+
+	labels := map[string]string{"app": "test"}
+	_, err = client.CreateConfigMap(ctx, "test-config", labels, map[string]string{"key": "old"})
+	require.NoError(t, err)
+	_, err = client.UpdateConfigMap(ctx, "test-config", labels, map[string]string{"key": "new"})
+	require.NoError(t, err)
+
+	gvr := &schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "examples"}
+	exists, err := client.CustomResourceDefinitionExists(ctx, gvr)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	require.NoError(t, client.CreateCustomResource(ctx, "test-example", gvr, &map[string]interface{}{
+		"spec": map[string]interface{}{"key": "value"},
+	}))
+
+	_, err = client.CreateOrUpdateSecret(ctx, "test-secret", labels, map[string][]byte{"password": []byte("hunter2")})
+	require.NoError(t, err)
+
+	// deleted objects are still rendered, as they have been created
+	require.NoError(t, client.DeleteConfigMap(ctx, "test-config"))
+
+	objects := client.Objects()
+	require.Len(t, objects, 4)
+	assert.Equal(t, "Namespace", objects[0]["kind"])
+	assert.Equal(t, "ConfigMap", objects[1]["kind"])
+	assert.Equal(t, map[string]interface{}{"key": "new"}, objects[1]["data"])
+	assert.Equal(t, "example.com/v1", objects[2]["apiVersion"])
+	// the values of the secrets are redacted, only their keys are rendered
+	assert.Equal(t, "Secret", objects[3]["kind"])
+	assert.Nil(t, objects[3]["data"])
+	assert.Equal(t, map[string]interface{}{"password": "REDACTED"}, objects[3]["stringData"])
+
+	var buf bytes.Buffer
+	require.NoError(t, client.WriteManifests(&buf))
+	assert.Equal(t, 4, strings.Count(buf.String(), "---\n"))
+	assert.Contains(t, buf.String(), "key: new")
+	assert.NotContains(t, buf.String(), "key: old")
+	assert.NotContains(t, buf.String(), "hunter2")
+	assert.NotContains(t, buf.String(), base64.StdEncoding.EncodeToString([]byte("hunter2")))
+}
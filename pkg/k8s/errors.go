@@ -80,6 +80,8 @@ var (
 	ErrResourceDoesNotExist            = errors.New("ResourceDoesNotExist", "resource %s does not exist in group version %s")
 	ErrCreatingCustomResource          = errors.New("CreatingCustomResource", "creating custom resource %s")
 	ErrCreatingRole                    = errors.New("CreatingRole", "creating role %s")
+	ErrGettingRole                     = errors.New("GettingRole", "error getting role %s")
+	ErrRoleDoesNotExist                = errors.New("RoleDoesNotExist", "role %s does not exist")
 	ErrCreatingRoleBinding             = errors.New("CreatingRoleBinding", "creating role binding %s")
 	ErrCreatingRoleBindingFailed       = errors.New("CreatingRoleBindingFailed", "creating role binding %s failed")
 	ErrNodePortNotSet                  = errors.New("NodePortNotSet", "node port not set")
@@ -93,6 +95,7 @@ var (
 	ErrWaitingForDeployment            = errors.New("WaitingForDeployment", "waiting for deployment %s to be ready")
 	ErrClusterRoleAlreadyExists        = errors.New("ClusterRoleAlreadyExists", "cluster role %s already exists")
 	ErrClusterRoleBindingAlreadyExists = errors.New("ClusterRoleBindingAlreadyExists", "cluster role binding %s already exists")
+	ErrGettingClusterRole              = errors.New("GettingClusterRole", "error getting cluster role %s")
 	ErrCreateEndpoint                  = errors.New("CreateEndpoint", "failed to create endpoint for service %s")
 	ErrGetEndpoint                     = errors.New("GetEndpoint", "failed to get endpoint for service %s")
 	ErrUpdateEndpoint                  = errors.New("UpdateEndpoint", "failed to update endpoint for service %s")
@@ -137,4 +140,53 @@ var (
 	ErrListingPods                     = errors.New("ListingPods", "failed to list pods")
 	ErrGetPodStatus                    = errors.New("GetPodStatus", "failed to get pod status for pod %s")
 	ErrUpdatingConfigmap               = errors.New("UpdatingConfigmap", "failed to update configmap %s")
+	ErrListingReplicaSets              = errors.New("ListingReplicaSets", "failed to list replicaSets")
+	ErrListingServices                 = errors.New("ListingServices", "failed to list services")
+	ErrListingConfigmaps               = errors.New("ListingConfigmaps", "failed to list configmaps")
+	ErrListingPersistentVolumeClaims   = errors.New("ListingPersistentVolumeClaims", "failed to list persistent volume claims")
+	ErrInvalidLeaseName                = errors.New("InvalidLeaseName", "invalid lease name %s: %v")
+	ErrCreatingLease                   = errors.New("CreatingLease", "failed to create lease %s")
+	ErrGettingLease                    = errors.New("GettingLease", "failed to get lease %s")
+	ErrUpdatingLease                   = errors.New("UpdatingLease", "failed to update lease %s")
+	ErrListingNetworkPolicies          = errors.New("ListingNetworkPolicies", "failed to list network policies")
+	ErrListingEvents                   = errors.New("ListingEvents", "failed to list events")
+	ErrWatchingEvents                  = errors.New("WatchingEvents", "failed to watch events")
+	ErrRecordingObject                 = errors.New("RecordingObject", "failed to record object in dry-run mode")
+	ErrWritingManifests                = errors.New("WritingManifests", "failed to write manifests")
+	ErrScalingReplicaSet               = errors.New("ScalingReplicaSet", "failed to scale ReplicaSet %s")
+	ErrInvalidStatefulSetName          = errors.New("InvalidStatefulSetName", "invalid StatefulSet name %s: %v")
+	ErrStatefulSetReplicasNegative     = errors.New("StatefulSetReplicasNegative", "number of replicas cannot be negative: %d")
+	ErrStatefulSetServiceNameRequired  = errors.New("StatefulSetServiceNameRequired", "service name is required for StatefulSet %s")
+	ErrCreatingStatefulSet             = errors.New("CreatingStatefulSet", "failed to create StatefulSet %s")
+	ErrGettingStatefulSet              = errors.New("GettingStatefulSet", "failed to get StatefulSet %s")
+	ErrDeletingStatefulSet             = errors.New("DeletingStatefulSet", "failed to delete StatefulSet %s")
+	ErrListingStatefulSets             = errors.New("ListingStatefulSets", "failed to list statefulSets")
+	ErrListingPodsForStatefulSet       = errors.New("ListingPodsForStatefulSet", "failed to list pods for StatefulSet %s")
+	ErrScalingStatefulSet              = errors.New("ScalingStatefulSet", "failed to scale StatefulSet %s")
+	ErrCreatingHeadlessService         = errors.New("CreatingHeadlessService", "failed to create headless service %s")
+	ErrInvalidJobName                  = errors.New("InvalidJobName", "invalid Job name %s: %v")
+	ErrJobBackoffLimitNegative         = errors.New("JobBackoffLimitNegative", "backoff limit cannot be negative: %d")
+	ErrCreatingJob                     = errors.New("CreatingJob", "failed to create Job %s")
+	ErrGettingJob                      = errors.New("GettingJob", "failed to get Job %s")
+	ErrWaitingForJob                   = errors.New("WaitingForJob", "error waiting for Job %s to finish")
+	ErrListingPodsForJob               = errors.New("ListingPodsForJob", "failed to list pods for Job %s")
+	ErrDeletingJob                     = errors.New("DeletingJob", "failed to delete Job %s")
+	ErrListingDaemonSets               = errors.New("ListingDaemonSets", "failed to list daemonSets")
+	ErrListingJobs                     = errors.New("ListingJobs", "failed to list jobs")
+	ErrListingPodsForDaemonSet         = errors.New("ListingPodsForDaemonSet", "failed to list pods for DaemonSet %s")
+	ErrInvalidVolumeName               = errors.New("InvalidVolumeName", "invalid volume name %s: %v")
+	ErrInvalidVolumeAccessMode         = errors.New("InvalidVolumeAccessMode", "invalid volume access mode '%s'")
+	ErrVolumeMountNotFound             = errors.New("VolumeMountNotFound", "volume %s mounted in sidecar %s is not a volume of container %s")
+	ErrStreamingCommand                = errors.New("StreamingCommand", "failed to stream command '%s' in pod %s, stderr: `%s`")
+	ErrGettingSecret                   = errors.New("GettingSecret", "failed to get secret %s")
+	ErrCreatingSecret                  = errors.New("CreatingSecret", "failed to create secret %s")
+	ErrUpdatingSecret                  = errors.New("UpdatingSecret", "failed to update secret %s")
+	ErrDeletingSecret                  = errors.New("DeletingSecret", "failed to delete secret %s")
+	ErrInvalidSecretName               = errors.New("InvalidSecretName", "invalid secret name %s: %v")
+	ErrInvalidSecretKey                = errors.New("InvalidSecretKey", "invalid secret key %s: %v")
+	ErrInvalidLiveFile                 = errors.New("InvalidLiveFile", "invalid live file with key '%s' at path '%s', the key must be set and the path absolute")
+	ErrLiveFilesMixedInDirectory       = errors.New("LiveFilesMixedInDirectory", "the live files of directory %s must all be stored in the ConfigMap or all in the Secret")
+	ErrFilesArchiveURLEmpty            = errors.New("FilesArchiveURLEmpty", "file '%s' is remote but the URL of the files archive is empty")
+	ErrSymlinkNotInVolume              = errors.New("SymlinkNotInVolume", "symlink '%s' is not in a volume, the symlinks can only be created in the volumes")
+	ErrInvalidSecretFile               = errors.New("InvalidSecretFile", "invalid secret file with key '%s' at path '%s', the key must be set and the path absolute")
 )
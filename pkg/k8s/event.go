@@ -0,0 +1,355 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const (
+	eventsBufferSize       = 100
+	eventWatchRetry        = 5 * time.Second
+	reasonOOMKilled        = "OOMKilled"
+	reasonBackOff          = "BackOff"
+	reasonFailedScheduling = "FailedScheduling"
+)
+
+// EventType classifies the events streamed by WatchEvents
+type EventType string
+
+const (
+	EventImagePullBackOff EventType = "ImagePullBackOff"
+	EventOOMKilled        EventType = "OOMKilled"
+	EventFailedScheduling EventType = "FailedScheduling"
+	// EventBackOff is the back-off of a container that keeps crashing
+	EventBackOff EventType = "BackOff"
+	// EventOther is any other event, its reason tells what happened
+	EventOther EventType = "Other"
+)
+
+// Event is an event of an object in the namespace
+type Event struct {
+	Type    EventType
+	Reason  string
+	Message string
+	Warning bool
+	// Kind and Name identify the object the event is about
+	Kind string
+	Name string
+	// Container is set when the event is about a container of a pod
+	Container string
+	// Labels are the labels of the pod the event is about, if any
+	Labels map[string]string
+	Time   time.Time
+}
+
+// ListEvents returns all the events in the namespace.
+func (c *Client) ListEvents(ctx context.Context) ([]v1.Event, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+	eventList, err := c.clientset.CoreV1().Events(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, ErrListingEvents.Wrap(err)
+	}
+	return eventList.Items, nil
+}
+
+// WatchEvents streams the events that happen in the namespace from now on, until the context is done.
+// Besides the events reported by Kubernetes, an event is sent when a container is OOM killed,
+// as this is only reported in the status of the pod.
+// The channel is closed when the context is done.
+func (c *Client) WatchEvents(ctx context.Context) (<-chan Event, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+
+	w := &eventWatcher{
+		client:    c,
+		events:    make(chan Event, eventsBufferSize),
+		podLabels: make(map[string]map[string]string),
+		oomKilled: make(map[string]metav1.Time),
+	}
+	if err := w.start(ctx); err != nil {
+		return nil, ErrWatchingEvents.Wrap(err)
+	}
+	go w.run(ctx)
+	return w.events, nil
+}
+
+// eventWatcher merges the watch of the events and the watch of the pods of the namespace
+type eventWatcher struct {
+	client *Client
+	events chan Event
+
+	eventWatch watch.Interface
+	eventsRV   string
+	podWatch   watch.Interface
+	podsRV     string
+
+	podLabels map[string]map[string]string
+	// oomKilled holds the last OOM kill reported for each container, so it is reported only once
+	oomKilled map[string]metav1.Time
+}
+
+func (w *eventWatcher) start(ctx context.Context) error {
+	if err := w.listEvents(ctx); err != nil {
+		return err
+	}
+	if err := w.listPods(ctx); err != nil {
+		return err
+	}
+
+	var err error
+	if w.eventWatch, err = w.watchEvents(ctx); err != nil {
+		return err
+	}
+	if w.podWatch, err = w.watchPods(ctx); err != nil {
+		w.eventWatch.Stop()
+		return err
+	}
+	return nil
+}
+
+// listEvents sets the resource version from which the events are watched, so that past events are not sent
+func (w *eventWatcher) listEvents(ctx context.Context) error {
+	list, err := w.client.clientset.CoreV1().Events(w.client.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	w.eventsRV = list.ResourceVersion
+	return nil
+}
+
+// listPods records the labels of the pods and the OOM kills that already happened
+func (w *eventWatcher) listPods(ctx context.Context) error {
+	list, err := w.client.clientset.CoreV1().Pods(w.client.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	w.podsRV = list.ResourceVersion
+	// the pods deleted while the watch was down are not listed anymore
+	w.podLabels = make(map[string]map[string]string, len(list.Items))
+	for n := range list.Items {
+		w.podLabels[list.Items[n].Name] = list.Items[n].Labels
+		w.oomKilledEvents(&list.Items[n])
+	}
+	return nil
+}
+
+func (w *eventWatcher) watchEvents(ctx context.Context) (watch.Interface, error) {
+	return w.client.clientset.CoreV1().Events(w.client.namespace).Watch(ctx, metav1.ListOptions{ResourceVersion: w.eventsRV})
+}
+
+func (w *eventWatcher) watchPods(ctx context.Context) (watch.Interface, error) {
+	return w.client.clientset.CoreV1().Pods(w.client.namespace).Watch(ctx, metav1.ListOptions{ResourceVersion: w.podsRV})
+}
+
+func (w *eventWatcher) run(ctx context.Context) {
+	defer close(w.events)
+	defer func() {
+		// a watch is nil if the context was done while restarting it
+		if w.eventWatch != nil {
+			w.eventWatch.Stop()
+		}
+		if w.podWatch != nil {
+			w.podWatch.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case we, ok := <-w.eventWatch.ResultChan():
+			if !ok || we.Type == watch.Error {
+				w.eventWatch.Stop()
+				if w.eventWatch = w.restart(ctx, we, w.listEvents, w.watchEvents); w.eventWatch == nil {
+					return
+				}
+				continue
+			}
+			e, ok := we.Object.(*v1.Event)
+			if !ok || we.Type != watch.Added && we.Type != watch.Modified {
+				continue
+			}
+			w.eventsRV = e.ResourceVersion
+			if !w.send(ctx, w.convertEvent(ctx, e)) {
+				return
+			}
+		case we, ok := <-w.podWatch.ResultChan():
+			if !ok || we.Type == watch.Error {
+				w.podWatch.Stop()
+				if w.podWatch = w.restart(ctx, we, w.listPods, w.watchPods); w.podWatch == nil {
+					return
+				}
+				continue
+			}
+			pod, ok := we.Object.(*v1.Pod)
+			if !ok {
+				continue
+			}
+			w.podsRV = pod.ResourceVersion
+			if we.Type == watch.Deleted {
+				w.forgetPod(pod)
+				continue
+			}
+			w.podLabels[pod.Name] = pod.Labels
+			for _, e := range w.oomKilledEvents(pod) {
+				if !w.send(ctx, e) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// restart starts a watch again once it has been closed by the server.
+// When the resource version is too old, the resources are listed again to get a new one.
+// It returns nil if the context is done before the watch could be restarted.
+func (w *eventWatcher) restart(
+	ctx context.Context,
+	last watch.Event,
+	list func(context.Context) error,
+	start func(context.Context) (watch.Interface, error),
+) watch.Interface {
+	relist := last.Type == watch.Error
+	for {
+		if relist {
+			if err := list(ctx); err != nil {
+				w.client.logger.WithError(err).Warn("listing resources to restart the event watch")
+			}
+		}
+		wi, err := start(ctx)
+		if err == nil {
+			return wi
+		}
+		w.client.logger.WithError(err).Warn("restarting the event watch")
+		relist = true
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(eventWatchRetry):
+		}
+	}
+}
+
+func (w *eventWatcher) send(ctx context.Context, e Event) bool {
+	select {
+	case w.events <- e:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (w *eventWatcher) convertEvent(ctx context.Context, e *v1.Event) Event {
+	event := Event{
+		Type:      classifyEvent(e.Reason, e.Message),
+		Reason:    e.Reason,
+		Message:   e.Message,
+		Warning:   e.Type == v1.EventTypeWarning,
+		Kind:      e.InvolvedObject.Kind,
+		Name:      e.InvolvedObject.Name,
+		Container: containerFromFieldPath(e.InvolvedObject.FieldPath),
+		Time:      eventTime(e),
+	}
+	if event.Kind != "Pod" {
+		return event
+	}
+
+	labels, ok := w.podLabels[event.Name]
+	if !ok {
+		// the event may arrive before the pod is seen by the pod watch
+		if pod, err := w.client.getPod(ctx, event.Name); err == nil {
+			labels = pod.Labels
+			w.podLabels[event.Name] = labels
+		}
+	}
+	event.Labels = labels
+	return event
+}
+
+// forgetPod drops what is recorded about a deleted pod, so that the watcher does not grow with the pods of the namespace
+func (w *eventWatcher) forgetPod(pod *v1.Pod) {
+	delete(w.podLabels, pod.Name)
+	for _, statuses := range [][]v1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, s := range statuses {
+			delete(w.oomKilled, pod.Name+"/"+s.Name)
+		}
+	}
+}
+
+// oomKilledEvents returns the OOM kills of the containers of the pod that have not been reported yet
+func (w *eventWatcher) oomKilledEvents(pod *v1.Pod) []Event {
+	var events []Event
+	for _, statuses := range [][]v1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, s := range statuses {
+			terminated := s.LastTerminationState.Terminated
+			if s.State.Terminated != nil {
+				terminated = s.State.Terminated
+			}
+			if terminated == nil || terminated.Reason != reasonOOMKilled {
+				continue
+			}
+
+			key := pod.Name + "/" + s.Name
+			if last, ok := w.oomKilled[key]; ok && last.Equal(&terminated.FinishedAt) {
+				continue
+			}
+			w.oomKilled[key] = terminated.FinishedAt
+
+			events = append(events, Event{
+				Type:      EventOOMKilled,
+				Reason:    reasonOOMKilled,
+				Message:   terminated.Message,
+				Warning:   true,
+				Kind:      "Pod",
+				Name:      pod.Name,
+				Container: s.Name,
+				Labels:    pod.Labels,
+				Time:      terminated.FinishedAt.Time,
+			})
+		}
+	}
+	return events
+}
+
+func classifyEvent(reason, message string) EventType {
+	switch {
+	case reason == reasonFailedScheduling:
+		return EventFailedScheduling
+	case strings.Contains(message, "ImagePullBackOff"),
+		strings.Contains(message, "ErrImagePull"),
+		reason == reasonBackOff && strings.Contains(message, "pulling image"):
+		return EventImagePullBackOff
+	case reason == reasonBackOff:
+		return EventBackOff
+	}
+	return EventOther
+}
+
+// containerFromFieldPath returns the name of the container referenced by a field path like 'spec.containers{name}'
+func containerFromFieldPath(fieldPath string) string {
+	start := strings.Index(fieldPath, "{")
+	end := strings.LastIndex(fieldPath, "}")
+	if start < 0 || end <= start {
+		return ""
+	}
+	return fieldPath[start+1 : end]
+}
+
+func eventTime(e *v1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	}
+	return e.CreationTimestamp.Time
+}
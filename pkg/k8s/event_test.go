@@ -0,0 +1,122 @@
+package k8s_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+)
+
+const eventTimeout = 5 * time.Second
+
+func (s *TestSuite) TestListEvents() {
+	_, err := s.client.Clientset().CoreV1().Events(s.namespace).Create(context.Background(), &v1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-event", Namespace: s.namespace},
+		Reason:     "Scheduled",
+	}, metav1.CreateOptions{})
+	s.Require().NoError(err)
+
+	events, err := s.client.ListEvents(context.Background())
+	s.Require().NoError(err)
+	s.Require().Len(events, 1)
+	s.Assert().Equal("Scheduled", events[0].Reason)
+
+	s.client.Clientset().(*fake.Clientset).
+		PrependReactor("list", "events",
+			func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, nil, errInternalServerError
+			})
+	_, err = s.client.ListEvents(context.Background())
+	s.Assert().ErrorIs(err, k8s.ErrListingEvents)
+}
+
+func (s *TestSuite) TestWatchEvents() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	labels := map[string]string{"knuu.sh/name": "test-instance"}
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: s.namespace, Labels: labels},
+		Status: v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{{Name: "test-instance"}},
+		},
+	}
+	_, err := s.client.Clientset().CoreV1().Pods(s.namespace).Create(ctx, pod, metav1.CreateOptions{})
+	s.Require().NoError(err)
+
+	events, err := s.client.WatchEvents(ctx)
+	s.Require().NoError(err)
+
+	_, err = s.client.Clientset().CoreV1().Events(s.namespace).Create(ctx, &v1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pod.1", Namespace: s.namespace},
+		InvolvedObject: v1.ObjectReference{
+			Kind:      "Pod",
+			Name:      "test-pod",
+			FieldPath: "spec.containers{test-instance}",
+		},
+		Type:    v1.EventTypeWarning,
+		Reason:  "BackOff",
+		Message: `Back-off pulling image "does-not-exist"`,
+	}, metav1.CreateOptions{})
+	s.Require().NoError(err)
+
+	event := s.nextEvent(events)
+	s.Assert().Equal(k8s.EventImagePullBackOff, event.Type)
+	s.Assert().Equal("test-instance", event.Container)
+	s.Assert().Equal(labels, event.Labels)
+	s.Assert().True(event.Warning)
+
+	pod.Status.ContainerStatuses[0].RestartCount = 1
+	pod.Status.ContainerStatuses[0].LastTerminationState.Terminated = &v1.ContainerStateTerminated{
+		Reason:     "OOMKilled",
+		FinishedAt: metav1.Now(),
+	}
+	_, err = s.client.Clientset().CoreV1().Pods(s.namespace).UpdateStatus(ctx, pod, metav1.UpdateOptions{})
+	s.Require().NoError(err)
+
+	event = s.nextEvent(events)
+	s.Assert().Equal(k8s.EventOOMKilled, event.Type)
+	s.Assert().Equal("test-pod", event.Name)
+	s.Assert().Equal("test-instance", event.Container)
+
+	// the labels of a deleted pod are forgotten, so later events of the pod have none
+	s.Require().NoError(s.client.Clientset().CoreV1().Pods(s.namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}))
+	var deletedLabels map[string]string
+	for n := 2; n < 100; n++ {
+		_, err = s.client.Clientset().CoreV1().Events(s.namespace).Create(ctx, &v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: fmt.Sprintf("test-pod.%d", n), Namespace: s.namespace},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "test-pod"},
+			Reason:         "Killing",
+		}, metav1.CreateOptions{})
+		s.Require().NoError(err)
+		// the deletion may be seen after the first events, as pods and events are watched apart
+		if deletedLabels = s.nextEvent(events).Labels; deletedLabels == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	s.Assert().Nil(deletedLabels)
+
+	cancel()
+	for range events {
+		// the channel is closed once the context is done
+	}
+}
+
+func (s *TestSuite) nextEvent(events <-chan k8s.Event) k8s.Event {
+	select {
+	case event, ok := <-events:
+		s.Require().True(ok, "events channel closed")
+		return event
+	case <-time.After(eventTimeout):
+		s.FailNow("timed out waiting for event")
+	}
+	return k8s.Event{}
+}
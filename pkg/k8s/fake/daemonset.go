@@ -0,0 +1,78 @@
+package fake
+
+import (
+	"fmt"
+
+	appv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/rand"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// defaultNodeName is the node the DaemonSets run on when no node has been added to the fake clientset
+const defaultNodeName = "fake-node"
+
+var nodesGVR = v1.SchemeGroupVersion.WithResource("nodes")
+
+// readyDaemonSet marks the DaemonSet as ready and creates a pod on each node that does not have one yet.
+// The nodes are the ones added to the fake clientset, or a single node named 'fake-node' if there are none.
+func (c *Client) readyDaemonSet(obj runtime.Object, tracker k8stesting.ObjectTracker) error {
+	ds, ok := obj.(*appv1.DaemonSet)
+	if !ok {
+		return nil
+	}
+	nodes, err := nodeNames(tracker)
+	if err != nil {
+		return err
+	}
+	count := int32(len(nodes))
+	ds.Status = appv1.DaemonSetStatus{
+		CurrentNumberScheduled: count,
+		DesiredNumberScheduled: count,
+		NumberReady:            count,
+		NumberAvailable:        count,
+		UpdatedNumberScheduled: count,
+		ObservedGeneration:     ds.Generation,
+	}
+
+	pods, err := ownedPods(tracker, ds.Namespace, "DaemonSet", ds.Name)
+	if err != nil {
+		return err
+	}
+	scheduled := make(map[string]bool, len(pods))
+	for _, pod := range pods {
+		scheduled[pod.Spec.NodeName] = true
+	}
+	for _, node := range nodes {
+		if scheduled[node] {
+			continue
+		}
+		template := ds.Spec.Template.DeepCopy()
+		template.Spec.NodeName = node
+		name := fmt.Sprintf("%s-%s", ds.Name, rand.String(podSuffixLength))
+		if err := c.addPod(tracker, name, ds.Namespace, "DaemonSet", ds.Name, template); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nodeNames returns the names of the nodes of the fake clientset, or the default node if there are none
+func nodeNames(tracker k8stesting.ObjectTracker) ([]string, error) {
+	list, err := tracker.List(nodesGVR, v1.SchemeGroupVersion.WithKind("Node"), "")
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	var names []string
+	if list != nil {
+		for _, node := range list.(*v1.NodeList).Items {
+			names = append(names, node.Name)
+		}
+	}
+	if len(names) == 0 {
+		names = []string{defaultNodeName}
+	}
+	return names, nil
+}
@@ -0,0 +1,102 @@
+package fake
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+)
+
+// Exec is a command run in a container of a pod
+type Exec struct {
+	Pod       string
+	Container string
+	Command   []string
+	// Stdin is the input given to the command by StreamCommandInPod
+	Stdin []byte
+}
+
+// Script returns the command as a single string.
+// For the commands run through a shell, like the ones of instance.ExecuteCommand, this is the script given to the shell.
+func (e Exec) Script() string {
+	if len(e.Command) == 3 && e.Command[1] == "-c" {
+		return e.Command[2]
+	}
+	return strings.Join(e.Command, " ")
+}
+
+// ExecHandler returns the output of a command run in a container
+type ExecHandler func(Exec) (string, error)
+
+// SetExecOutput sets the output returned when the given command is run in any container.
+// The command is compared with the script of the exec, e.g. "echo hello" for instance.ExecuteCommand(ctx, "echo", "hello").
+func (c *Client) SetExecOutput(command, output string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.execOutputs[command] = output
+}
+
+// HandleExec sets the handler called for the commands that have no output set with SetExecOutput.
+// Without a handler, these commands return an empty output.
+func (c *Client) HandleExec(handler ExecHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.execHandler = handler
+}
+
+// Execs returns the commands run so far, in the order they were run
+func (c *Client) Execs() []Exec {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	execs := make([]Exec, len(c.execs))
+	copy(execs, c.execs)
+	return execs
+}
+
+// RunCommandInPod records the command and returns its scripted output
+func (c *Client) RunCommandInPod(ctx context.Context, podName, containerName string, cmd []string) (string, error) {
+	if _, err := c.clientset.CoreV1().Pods(c.Namespace()).Get(ctx, podName, metav1.GetOptions{}); err != nil {
+		return "", k8s.ErrGettingPod.WithParams(podName).Wrap(err)
+	}
+
+	return c.runCommand(ctx, Exec{Pod: podName, Container: containerName, Command: cmd})
+}
+
+// StreamCommandInPod records the command with its input and writes its scripted output
+func (c *Client) StreamCommandInPod(ctx context.Context, podName, containerName string, cmd []string, stdin io.Reader, stdout io.Writer) error {
+	if _, err := c.clientset.CoreV1().Pods(c.Namespace()).Get(ctx, podName, metav1.GetOptions{}); err != nil {
+		return k8s.ErrGettingPod.WithParams(podName).Wrap(err)
+	}
+
+	exec := Exec{Pod: podName, Container: containerName, Command: cmd}
+	if stdin != nil {
+		input, err := io.ReadAll(stdin)
+		if err != nil {
+			return err
+		}
+		exec.Stdin = input
+	}
+	output, err := c.runCommand(ctx, exec)
+	if err != nil {
+		return k8s.ErrStreamingCommand.WithParams(strings.Join(cmd, " "), podName, err.Error()).Wrap(err)
+	}
+	_, err = io.WriteString(stdout, output)
+	return err
+}
+
+// runCommand records the exec and returns its scripted output
+func (c *Client) runCommand(_ context.Context, exec Exec) (string, error) {
+	c.mu.Lock()
+	c.execs = append(c.execs, exec)
+	output, ok := c.execOutputs[exec.Script()]
+	handler := c.execHandler
+	c.mu.Unlock()
+
+	if ok || handler == nil {
+		return output, nil
+	}
+	return handler(exec)
+}
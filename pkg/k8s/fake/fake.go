@@ -0,0 +1,414 @@
+// Package fake provides an in-memory k8s.KubeManager to unit test code built on knuu without a cluster.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	appv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/rand"
+	dynfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+)
+
+const podSuffixLength = 5
+
+var podsGVR = v1.SchemeGroupVersion.WithResource("pods")
+
+// Client is a k8s.KubeManager backed by client-go's fake clientset.
+// On top of storing the objects, it simulates what the cluster would do with them:
+// the ReplicaSets, StatefulSets, DaemonSets and Deployments become ready right away,
+// the pods of the ReplicaSets and StatefulSets are created, along with the claims of the StatefulSets,
+// the DaemonSets run a pod on each node added to the clientset, or on a single 'fake-node',
+// the Jobs are run to completion with the output scripted for their command,
+// the Services get a ClusterIP (and an ingress IP for the LoadBalancers),
+// the commands run in the pods return the output scripted with SetExecOutput or HandleExec,
+// and the port-forwards succeed.
+type Client struct {
+	*k8s.Client
+	clientset *fake.Clientset
+
+	mu          sync.Mutex
+	nextIP      int
+	execs       []Exec
+	execOutputs map[string]string
+	execHandler ExecHandler
+	// logs are the outputs of the commands of the pods of the Jobs
+	logs map[string]string
+}
+
+var _ k8s.KubeManager = &Client{}
+
+// NewClient creates a fake client for the given namespace, which is created right away
+func NewClient(ctx context.Context, namespace string, logger *logrus.Logger) (*Client, error) {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	cs := fake.NewSimpleClientset()
+	c := &Client{
+		clientset:   cs,
+		execOutputs: make(map[string]string),
+		logs:        make(map[string]string),
+	}
+	tracker := cs.Tracker()
+	for _, verb := range []string{"create", "update"} {
+		cs.PrependReactor(verb, "replicasets", c.reactor(tracker, c.readyReplicaSet))
+		cs.PrependReactor(verb, "statefulsets", c.reactor(tracker, c.readyStatefulSet))
+		cs.PrependReactor(verb, "daemonsets", c.reactor(tracker, c.readyDaemonSet))
+		cs.PrependReactor(verb, "deployments", c.reactor(tracker, readyDeployment))
+		cs.PrependReactor(verb, "services", c.reactor(tracker, c.assignServiceIPs))
+	}
+	cs.PrependReactor("create", "pods", c.reactor(tracker, runningPod))
+	cs.PrependReactor("create", "jobs", c.reactor(tracker, c.completeJob))
+	cs.PrependReactor("delete", "replicasets", c.deleteOwnedPods("ReplicaSet"))
+	cs.PrependReactor("delete", "statefulsets", c.deleteOwnedPods("StatefulSet"))
+	cs.PrependReactor("delete", "daemonsets", c.deleteOwnedPods("DaemonSet"))
+	cs.PrependReactor("delete", "jobs", c.deleteOwnedPods("Job"))
+
+	dC := dynfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), dynamicListKinds())
+	kc, err := k8s.NewClientCustom(ctx, cs, cs.Discovery(), dC, namespace, logger)
+	if err != nil {
+		return nil, err
+	}
+	c.Client = kc
+	return c, nil
+}
+
+// dynamicListKinds returns the list kinds of the resources that can be listed with the dynamic client,
+// as the fake dynamic client can only list the kinds it knows:
+// the built-in resources, and the custom resources of the proxy
+func dynamicListKinds() map[schema.GroupVersionResource]string {
+	listKinds := map[schema.GroupVersionResource]string{
+		{Group: "traefik.io", Version: "v1alpha1", Resource: "ingressroutes"}: "IngressRouteList",
+		{Group: "traefik.io", Version: "v1alpha1", Resource: "middlewares"}:   "MiddlewareList",
+	}
+	for gvk := range scheme.Scheme.AllKnownTypes() {
+		if !strings.HasSuffix(gvk.Kind, "List") || gvk.Kind == "List" ||
+			gvk.Version == "" || gvk.Version == runtime.APIVersionInternal {
+			continue
+		}
+		gvr, _ := meta.UnsafeGuessKindToResource(gvk.GroupVersion().WithKind(strings.TrimSuffix(gvk.Kind, "List")))
+		listKinds[gvr] = gvk.Kind
+	}
+	return listKinds
+}
+
+// FakeClientset returns the underlying fake clientset, e.g. to add reactors that inject errors
+func (c *Client) FakeClientset() *fake.Clientset {
+	return c.clientset
+}
+
+// reactor lets the given function change the object before it is stored.
+// The object is copied, so the one given by the caller is left untouched.
+func (c *Client) reactor(tracker k8stesting.ObjectTracker, simulate func(runtime.Object, k8stesting.ObjectTracker) error) k8stesting.ReactionFunc {
+	react := k8stesting.ObjectReaction(tracker)
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "" {
+			return false, nil, nil
+		}
+
+		// create and update actions have the same methods, so they are told apart by their verb
+		a, ok := action.(k8stesting.CreateAction)
+		if !ok {
+			return false, nil, nil
+		}
+		obj := a.GetObject().DeepCopyObject()
+		switch action.GetVerb() {
+		case "create":
+			action = k8stesting.NewCreateAction(a.GetResource(), a.GetNamespace(), obj)
+		case "update":
+			action = k8stesting.NewUpdateAction(a.GetResource(), a.GetNamespace(), obj)
+		default:
+			return false, nil, nil
+		}
+		if accessor, err := meta.Accessor(obj); err == nil && accessor.GetNamespace() == "" {
+			accessor.SetNamespace(action.GetNamespace())
+		}
+		if err := simulate(obj, tracker); err != nil {
+			return true, nil, err
+		}
+		return react(action)
+	}
+}
+
+// readyReplicaSet marks the ReplicaSet as ready and creates or deletes pods to match its number of replicas
+func (c *Client) readyReplicaSet(obj runtime.Object, tracker k8stesting.ObjectTracker) error {
+	rs, ok := obj.(*appv1.ReplicaSet)
+	if !ok {
+		return nil
+	}
+	replicas := int32(1)
+	if rs.Spec.Replicas != nil {
+		replicas = *rs.Spec.Replicas
+	}
+	rs.Status = appv1.ReplicaSetStatus{
+		Replicas:             replicas,
+		FullyLabeledReplicas: replicas,
+		ReadyReplicas:        replicas,
+		AvailableReplicas:    replicas,
+		ObservedGeneration:   rs.Generation,
+	}
+
+	pods, err := ownedPods(tracker, rs.Namespace, "ReplicaSet", rs.Name)
+	if err != nil {
+		return err
+	}
+	// the newest pods are removed first when scaling down, like the ReplicaSet controller does
+	for n := len(pods) - 1; n >= int(replicas); n-- {
+		if err := tracker.Delete(podsGVR, rs.Namespace, pods[n].Name); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	for n := len(pods); n < int(replicas); n++ {
+		name := fmt.Sprintf("%s-%s", rs.Name, rand.String(podSuffixLength))
+		if err := c.addPod(tracker, name, rs.Namespace, "ReplicaSet", rs.Name, &rs.Spec.Template); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readyStatefulSet marks the StatefulSet as ready and creates or deletes pods to match its number of replicas.
+// The pods are named after their ordinal, and the missing claims of their volumes are created;
+// the claims are never deleted, like with the retention policy set by knuu.
+func (c *Client) readyStatefulSet(obj runtime.Object, tracker k8stesting.ObjectTracker) error {
+	ss, ok := obj.(*appv1.StatefulSet)
+	if !ok {
+		return nil
+	}
+	replicas := int32(1)
+	if ss.Spec.Replicas != nil {
+		replicas = *ss.Spec.Replicas
+	}
+	ss.Status = appv1.StatefulSetStatus{
+		Replicas:           replicas,
+		ReadyReplicas:      replicas,
+		CurrentReplicas:    replicas,
+		UpdatedReplicas:    replicas,
+		AvailableReplicas:  replicas,
+		ObservedGeneration: ss.Generation,
+	}
+
+	pods, err := ownedPods(tracker, ss.Namespace, "StatefulSet", ss.Name)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool, len(pods))
+	for _, pod := range pods {
+		existing[pod.Name] = true
+	}
+	for n := 0; n < int(replicas) || n < len(pods); n++ {
+		name := fmt.Sprintf("%s-%d", ss.Name, n)
+		if n >= int(replicas) {
+			if err := tracker.Delete(podsGVR, ss.Namespace, name); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			continue
+		}
+		for _, template := range ss.Spec.VolumeClaimTemplates {
+			pvc := template.DeepCopy()
+			pvc.Name = k8s.StatefulSetClaimName(template.Name, ss.Name, n)
+			pvc.Namespace = ss.Namespace
+			pvc.Status.Phase = v1.ClaimBound
+			if err := tracker.Add(pvc); err != nil && !errors.IsAlreadyExists(err) {
+				return err
+			}
+		}
+		if existing[name] {
+			continue
+		}
+		if err := c.addPod(tracker, name, ss.Namespace, "StatefulSet", ss.Name, &ss.Spec.Template); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addPod adds a running pod built from the template, owned by the workload of the given kind
+func (c *Client) addPod(tracker k8stesting.ObjectTracker, name, namespace, kind, owner string, template *v1.PodTemplateSpec) error {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			Labels:            template.Labels,
+			Annotations:       template.Annotations,
+			CreationTimestamp: metav1.Now(),
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       kind,
+				Name:       owner,
+			}},
+		},
+		Spec: *template.Spec.DeepCopy(),
+	}
+	if err := runningPod(pod, tracker); err != nil {
+		return err
+	}
+	pod.Status.PodIP = c.nextAddress()
+	return tracker.Add(pod)
+}
+
+func readyDeployment(obj runtime.Object, _ k8stesting.ObjectTracker) error {
+	d, ok := obj.(*appv1.Deployment)
+	if !ok {
+		return nil
+	}
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	d.Status = appv1.DeploymentStatus{
+		Replicas:           replicas,
+		UpdatedReplicas:    replicas,
+		ReadyReplicas:      replicas,
+		AvailableReplicas:  replicas,
+		ObservedGeneration: d.Generation,
+	}
+	return nil
+}
+
+// runningPod marks the pod and its containers as running, unless its status has been set already
+func runningPod(obj runtime.Object, _ k8stesting.ObjectTracker) error {
+	pod, ok := obj.(*v1.Pod)
+	if !ok || pod.Status.Phase != "" {
+		return nil
+	}
+	now := metav1.NewTime(time.Now())
+	pod.Status.Phase = v1.PodRunning
+	pod.Status.StartTime = &now
+	pod.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}
+	pod.Status.InitContainerStatuses = nil
+	for _, ic := range pod.Spec.InitContainers {
+		pod.Status.InitContainerStatuses = append(pod.Status.InitContainerStatuses, v1.ContainerStatus{
+			Name:  ic.Name,
+			Image: ic.Image,
+			State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Completed"}},
+			Ready: true,
+		})
+	}
+	pod.Status.ContainerStatuses = nil
+	for _, container := range pod.Spec.Containers {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, v1.ContainerStatus{
+			Name:    container.Name,
+			Image:   container.Image,
+			State:   v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: now}},
+			Ready:   true,
+			Started: ptr.To(true),
+		})
+	}
+	return nil
+}
+
+// assignServiceIPs gives a ClusterIP to the service, keeping the one it already has when it is updated
+func (c *Client) assignServiceIPs(obj runtime.Object, tracker k8stesting.ObjectTracker) error {
+	svc, ok := obj.(*v1.Service)
+	if !ok {
+		return nil
+	}
+
+	existing, err := tracker.Get(v1.SchemeGroupVersion.WithResource("services"), svc.Namespace, svc.Name)
+	switch {
+	case err == nil:
+		old := existing.(*v1.Service)
+		if svc.Spec.ClusterIP == "" {
+			svc.Spec.ClusterIP = old.Spec.ClusterIP
+			svc.Spec.ClusterIPs = old.Spec.ClusterIPs
+		}
+		if len(svc.Status.LoadBalancer.Ingress) == 0 {
+			svc.Status.LoadBalancer = old.Status.LoadBalancer
+		}
+	case !errors.IsNotFound(err):
+		return err
+	}
+
+	if svc.Spec.ClusterIP == "" {
+		svc.Spec.ClusterIP = c.nextAddress()
+		svc.Spec.ClusterIPs = []string{svc.Spec.ClusterIP}
+	}
+	if svc.Spec.Type == v1.ServiceTypeLoadBalancer && len(svc.Status.LoadBalancer.Ingress) == 0 {
+		svc.Status.LoadBalancer.Ingress = []v1.LoadBalancerIngress{{IP: c.nextAddress()}}
+	}
+	return nil
+}
+
+// deleteOwnedPods removes the pods of the workload of the given kind once it is deleted
+func (c *Client) deleteOwnedPods(kind string) k8stesting.ReactionFunc {
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		del, ok := action.(k8stesting.DeleteAction)
+		if !ok {
+			return false, nil, nil
+		}
+		tracker := c.clientset.Tracker()
+		pods, err := ownedPods(tracker, del.GetNamespace(), kind, del.GetName())
+		if err != nil {
+			return true, nil, err
+		}
+		for _, pod := range pods {
+			if err := tracker.Delete(podsGVR, del.GetNamespace(), pod.Name); err != nil && !errors.IsNotFound(err) {
+				return true, nil, err
+			}
+		}
+		return false, nil, nil
+	}
+}
+
+// ownedPods returns the pods of the workload of the given kind, the oldest first
+func ownedPods(tracker k8stesting.ObjectTracker, namespace, kind, owner string) ([]v1.Pod, error) {
+	list, err := tracker.List(podsGVR, v1.SchemeGroupVersion.WithKind("Pod"), namespace)
+	if err != nil {
+		return nil, err
+	}
+	var pods []v1.Pod
+	for _, pod := range list.(*v1.PodList).Items {
+		for _, ref := range pod.OwnerReferences {
+			if ref.Kind == kind && ref.Name == owner {
+				pods = append(pods, pod)
+			}
+		}
+	}
+	sort.Slice(pods, func(a, b int) bool {
+		return pods[a].CreationTimestamp.Before(&pods[b].CreationTimestamp)
+	})
+	return pods, nil
+}
+
+// nextAddress returns a new address in 10.0.0.0/8, for the pods and the services
+func (c *Client) nextAddress() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextIP++
+	return fmt.Sprintf("10.%d.%d.%d", c.nextIP>>16&0xff, c.nextIP>>8&0xff, c.nextIP&0xff)
+}
+
+// WaitForService returns once the service exists, as there is nothing to connect to
+func (c *Client) WaitForService(ctx context.Context, name string) error {
+	if _, err := c.GetService(ctx, name); err != nil {
+		return k8s.ErrCheckingServiceReady.WithParams(name).Wrap(err)
+	}
+	return nil
+}
+
+// PortForwardPod succeeds as long as the pod exists, nothing is listening on the local port
+func (c *Client) PortForwardPod(ctx context.Context, podName string, localPort, remotePort int) error {
+	if _, err := c.clientset.CoreV1().Pods(c.Namespace()).Get(ctx, podName, metav1.GetOptions{}); err != nil {
+		return k8s.ErrGettingPod.WithParams(podName).Wrap(err)
+	}
+	return nil
+}
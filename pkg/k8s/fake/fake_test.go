@@ -0,0 +1,110 @@
+package fake_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/celestiaorg/knuu/pkg/instance"
+	"github.com/celestiaorg/knuu/pkg/k8s"
+	"github.com/celestiaorg/knuu/pkg/k8s/fake"
+	"github.com/celestiaorg/knuu/pkg/knuu"
+	"github.com/celestiaorg/knuu/pkg/system"
+)
+
+const testNamespace = "fake-test"
+
+func TestInstanceLifecycle(t *testing.T) {
+	ctx := context.Background()
+	client, err := fake.NewClient(ctx, testNamespace, logrus.New())
+	require.NoError(t, err)
+	client.SetExecOutput("cat /etc/hostname", "validator\n")
+
+	ins, err := instance.New("validator", &system.SystemDependencies{
+		K8sClient: client,
+		Logger:    logrus.New(),
+		Scope:     testNamespace,
+	})
+	require.NoError(t, err)
+	require.NoError(t, ins.Build().SetImage(ctx, "alpine:latest"))
+	require.NoError(t, ins.Network().AddPortTCP(26656))
+	require.NoError(t, ins.Build().Commit(ctx))
+	require.NoError(t, ins.Execution().Start(ctx))
+
+	running, err := ins.Execution().IsRunning(ctx)
+	require.NoError(t, err)
+	assert.True(t, running)
+
+	ip, err := ins.Network().GetIP(ctx)
+	require.NoError(t, err)
+	assert.NotEmpty(t, ip)
+
+	_, err = ins.Network().PortForwardTCP(ctx, 26656)
+	require.NoError(t, err)
+
+	out, err := ins.Execution().ExecuteCommand(ctx, "cat", "/etc/hostname")
+	require.NoError(t, err)
+	assert.Equal(t, "validator\n", out)
+
+	out, err = ins.Execution().ExecuteCommand(ctx, "echo", "unscripted")
+	require.NoError(t, err)
+	assert.Empty(t, out)
+
+	execs := client.Execs()
+	require.Len(t, execs, 2)
+	assert.Equal(t, "validator", execs[0].Container)
+	assert.Equal(t, "echo unscripted", execs[1].Script())
+
+	require.NoError(t, ins.Execution().Destroy(ctx))
+	pods, err := client.Clientset().CoreV1().Pods(testNamespace).List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, pods.Items)
+}
+
+func TestKnuuWithFakeClient(t *testing.T) {
+	ctx := context.Background()
+	client, err := fake.NewClient(ctx, testNamespace, nil)
+	require.NoError(t, err)
+
+	kn, err := knuu.New(ctx, knuu.Options{K8sClient: client, Logger: logrus.New()})
+	require.NoError(t, err)
+	require.NoError(t, kn.CleanUp(ctx))
+}
+
+func TestHandleExec(t *testing.T) {
+	ctx := context.Background()
+	client, err := fake.NewClient(ctx, testNamespace, nil)
+	require.NoError(t, err)
+
+	_, err = client.RunCommandInPod(ctx, "missing", "container", []string{"true"})
+	assert.Error(t, err)
+
+	pod, err := client.DeployPod(ctx, podConfig("worker"), false)
+	require.NoError(t, err)
+	running, err := client.IsPodRunning(ctx, pod.Name)
+	require.NoError(t, err)
+	assert.True(t, running)
+
+	client.HandleExec(func(e fake.Exec) (string, error) {
+		return e.Container + ": " + e.Script(), nil
+	})
+	out, err := client.RunCommandInPod(ctx, pod.Name, "worker", []string{"/bin/sh", "-c", "uptime"})
+	require.NoError(t, err)
+	assert.Equal(t, "worker: uptime", out)
+}
+
+func podConfig(name string) k8s.PodConfig {
+	return k8s.PodConfig{
+		Namespace: testNamespace,
+		Name:      name,
+		Labels:    map[string]string{"app": name},
+		ContainerConfig: k8s.ContainerConfig{
+			Name:  name,
+			Image: "alpine:latest",
+		},
+	}
+}
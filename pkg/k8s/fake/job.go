@@ -0,0 +1,108 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/rand"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// completeJob runs the Job to completion when it is created: the command of its first container is run like
+// the commands of RunCommandInPod, and its output becomes the logs of the pod. A command that returns an error
+// exits with the code 1 and the error as termination message, and is retried up to the backoff limit of the Job.
+func (c *Client) completeJob(obj runtime.Object, tracker k8stesting.ObjectTracker) error {
+	job, ok := obj.(*batchv1.Job)
+	if !ok || len(job.Spec.Template.Spec.Containers) == 0 {
+		return nil
+	}
+	if job.Spec.Selector == nil {
+		job.Spec.Selector = &metav1.LabelSelector{MatchLabels: job.Spec.Template.Labels}
+	}
+	backoffLimit := int32(6)
+	if job.Spec.BackoffLimit != nil {
+		backoffLimit = *job.Spec.BackoffLimit
+	}
+
+	container := job.Spec.Template.Spec.Containers[0]
+	now := metav1.Now()
+	job.Status = batchv1.JobStatus{StartTime: &now}
+	for attempt := int32(0); attempt <= backoffLimit; attempt++ {
+		name := fmt.Sprintf("%s-%s", job.Name, rand.String(podSuffixLength))
+		output, err := c.runCommand(context.Background(), Exec{
+			Pod:       name,
+			Container: container.Name,
+			Command:   append(append([]string{}, container.Command...), container.Args...),
+		})
+		terminated := &v1.ContainerStateTerminated{Reason: "Completed", FinishedAt: now}
+		phase := v1.PodSucceeded
+		if err != nil {
+			terminated = &v1.ContainerStateTerminated{ExitCode: 1, Reason: "Error", Message: err.Error(), FinishedAt: now}
+			phase = v1.PodFailed
+		}
+
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         job.Namespace,
+				Labels:            job.Spec.Template.Labels,
+				Annotations:       job.Spec.Template.Annotations,
+				CreationTimestamp: metav1.NewTime(now.Add(time.Duration(attempt) * time.Second)),
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "batch/v1",
+					Kind:       "Job",
+					Name:       job.Name,
+				}},
+			},
+			Spec: *job.Spec.Template.Spec.DeepCopy(),
+			Status: v1.PodStatus{
+				Phase: phase,
+				ContainerStatuses: []v1.ContainerStatus{{
+					Name:  container.Name,
+					Image: container.Image,
+					State: v1.ContainerState{Terminated: terminated},
+				}},
+			},
+		}
+		if err := tracker.Add(pod); err != nil {
+			return err
+		}
+		c.mu.Lock()
+		c.logs[name] = output
+		c.mu.Unlock()
+
+		if err == nil {
+			job.Status.Succeeded = 1
+			job.Status.CompletionTime = &now
+			job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+			return nil
+		}
+		job.Status.Failed++
+	}
+	job.Status.Conditions = []batchv1.JobCondition{{
+		Type:    batchv1.JobFailed,
+		Status:  v1.ConditionTrue,
+		Reason:  "BackoffLimitExceeded",
+		Message: "Job has reached the specified backoff limit",
+	}}
+	return nil
+}
+
+// GetPodLogStream returns the output of the command of the pods of the Jobs,
+// and the logs of the fake clientset for the other pods
+func (c *Client) GetPodLogStream(ctx context.Context, podName string, logOptions v1.PodLogOptions) (io.ReadCloser, error) {
+	c.mu.Lock()
+	logs, ok := c.logs[podName]
+	c.mu.Unlock()
+	if ok {
+		return io.NopCloser(strings.NewReader(logs)), nil
+	}
+	return c.Client.GetPodLogStream(ctx, podName, logOptions)
+}
@@ -0,0 +1,193 @@
+package k8s
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+type JobConfig struct {
+	Name           string            // Name of the Job
+	Namespace      string            // Namespace of the Job
+	Labels         map[string]string // Labels to apply to the Job, key/value represents the name/value of the label
+	BackoffLimit   int32             // BackoffLimit is the number of retries before the Job is marked as failed
+	ActiveDeadline time.Duration     // ActiveDeadline is the time the Job can run for, including the retries, no limit if zero
+	PodConfig      PodConfig         // PodConfig represents the pod configuration
+}
+
+// CreateJob creates a Job that runs a single pod to completion, in the namespace that k8s is initialized with.
+// The failed pods are not restarted, a new pod is created for each retry.
+func (c *Client) CreateJob(ctx context.Context, jobConfig JobConfig, init bool) (*batchv1.Job, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+	jobConfig.Namespace = c.namespace
+	if err := validateJobConfig(jobConfig); err != nil {
+		return nil, err
+	}
+	job := c.prepareJob(jobConfig, init)
+
+	created, err := c.clientset.BatchV1().Jobs(c.namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return nil, ErrCreatingJob.WithParams(jobConfig.Name).Wrap(err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"name":      jobConfig.Name,
+		"namespace": c.namespace,
+	}).Debug("job created")
+	return created, nil
+}
+
+func (c *Client) GetJob(ctx context.Context, name string) (*batchv1.Job, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+	job, err := c.clientset.BatchV1().Jobs(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, ErrGettingJob.WithParams(name).Wrap(err)
+	}
+	return job, nil
+}
+
+// IsJobRunning returns true if the Job has a pod running
+func (c *Client) IsJobRunning(ctx context.Context, name string) (bool, error) {
+	job, err := c.GetJob(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	return job.Status.Active > 0, nil
+}
+
+// WaitForJob waits until the Job has completed or failed, and returns it
+func (c *Client) WaitForJob(ctx context.Context, name string) (*batchv1.Job, error) {
+	for {
+		job, err := c.GetJob(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if JobFinished(job) {
+			return job, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ErrWaitingForJob.WithParams(name).Wrap(ctx.Err())
+		case <-time.After(waitRetry):
+			// Retry after some seconds
+		}
+	}
+}
+
+// JobFinished returns true if the Job has completed or failed
+func JobFinished(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) &&
+			condition.Status == v1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// ListJobs returns the Jobs in the namespace that match the given labels.
+func (c *Client) ListJobs(ctx context.Context, labels map[string]string) ([]batchv1.Job, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+	selector := metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: labels})
+	jobList, err := c.clientset.BatchV1().Jobs(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, ErrListingJobs.Wrap(err)
+	}
+	return jobList.Items, nil
+}
+
+// ListPodsFromJob returns the pods of the Job, one per attempt, the oldest first
+func (c *Client) ListPodsFromJob(ctx context.Context, name string) ([]v1.Pod, error) {
+	job, err := c.GetJob(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	selector := metav1.FormatLabelSelector(job.Spec.Selector)
+	pods, err := c.clientset.CoreV1().Pods(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, ErrListingPodsForJob.WithParams(name).Wrap(err)
+	}
+
+	sort.Slice(pods.Items, func(a, b int) bool {
+		ta, tb := pods.Items[a].CreationTimestamp, pods.Items[b].CreationTimestamp
+		if !ta.Equal(&tb) {
+			return ta.Before(&tb)
+		}
+		return pods.Items[a].Name < pods.Items[b].Name
+	})
+	return pods.Items, nil
+}
+
+// DeleteJob deletes the Job and its pods
+func (c *Client) DeleteJob(ctx context.Context, name string) error {
+	if c.terminated {
+		return ErrClientTerminated
+	}
+	err := c.clientset.BatchV1().Jobs(c.namespace).Delete(ctx, name, metav1.DeleteOptions{
+		GracePeriodSeconds: ptr.To[int64](0),
+		PropagationPolicy:  ptr.To(metav1.DeletePropagationBackground),
+	})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return ErrDeletingJob.WithParams(name).Wrap(err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"name":      name,
+		"namespace": c.namespace,
+	}).Debug("job deleted")
+	return nil
+}
+
+func (c *Client) prepareJob(jobConf JobConfig, init bool) *batchv1.Job {
+	podSpec := c.preparePodSpec(jobConf.PodConfig, init)
+	podSpec.RestartPolicy = v1.RestartPolicyNever
+	for n := range podSpec.Containers {
+		// the end of the logs is used as the termination message when the container fails without writing one
+		podSpec.Containers[n].TerminationMessagePolicy = v1.TerminationMessageFallbackToLogsOnError
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: jobConf.Namespace,
+			Name:      jobConf.Name,
+			Labels:    jobConf.Labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To(jobConf.BackoffLimit),
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   jobConf.Namespace,
+					Labels:      jobConf.Labels,
+					Annotations: jobConf.PodConfig.Annotations,
+				},
+				Spec: podSpec,
+			},
+		},
+	}
+	if jobConf.ActiveDeadline > 0 {
+		job.Spec.ActiveDeadlineSeconds = ptr.To(int64(jobConf.ActiveDeadline.Seconds()))
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"name":      jobConf.Name,
+		"namespace": jobConf.Namespace,
+	}).Debug("prepared job")
+	return job
+}
@@ -0,0 +1,110 @@
+package k8s_test
+
+import (
+	"context"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+)
+
+func (s *TestSuite) TestCreateJob() {
+	jobConfig := k8s.JobConfig{
+		Name:           "genesis",
+		Labels:         map[string]string{"app": "genesis"},
+		BackoffLimit:   2,
+		ActiveDeadline: time.Minute,
+		PodConfig: k8s.PodConfig{
+			Namespace: s.namespace,
+			Name:      "genesis",
+			Labels:    map[string]string{"app": "genesis"},
+			ContainerConfig: k8s.ContainerConfig{
+				Name:    "genesis",
+				Image:   "alpine:latest",
+				Command: []string{"echo", "done"},
+			},
+		},
+	}
+
+	job, err := s.client.CreateJob(context.Background(), jobConfig, true)
+	s.Require().NoError(err)
+	s.Assert().Equal(int32(2), *job.Spec.BackoffLimit)
+	s.Assert().Equal(int64(60), *job.Spec.ActiveDeadlineSeconds)
+	s.Assert().Equal(v1.RestartPolicyNever, job.Spec.Template.Spec.RestartPolicy)
+	s.Assert().Equal(v1.TerminationMessageFallbackToLogsOnError, job.Spec.Template.Spec.Containers[0].TerminationMessagePolicy)
+
+	_, err = s.client.CreateJob(context.Background(), jobConfig, true)
+	s.Assert().ErrorIs(err, k8s.ErrCreatingJob)
+
+	jobConfig.BackoffLimit = -1
+	_, err = s.client.CreateJob(context.Background(), jobConfig, true)
+	s.Assert().ErrorIs(err, k8s.ErrJobBackoffLimitNegative)
+}
+
+func (s *TestSuite) TestWaitForJob() {
+	_, err := s.client.Clientset().BatchV1().Jobs(s.namespace).Create(context.Background(), &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "finished", Namespace: s.namespace},
+		Status: batchv1.JobStatus{
+			Succeeded:  1,
+			Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}},
+		},
+	}, metav1.CreateOptions{})
+	s.Require().NoError(err)
+	job, err := s.client.WaitForJob(context.Background(), "finished")
+	s.Require().NoError(err)
+	s.Assert().True(k8s.JobFinished(job))
+
+	_, err = s.client.Clientset().BatchV1().Jobs(s.namespace).Create(context.Background(), &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: s.namespace},
+		Status:     batchv1.JobStatus{Active: 1},
+	}, metav1.CreateOptions{})
+	s.Require().NoError(err)
+	running, err := s.client.IsJobRunning(context.Background(), "running")
+	s.Require().NoError(err)
+	s.Assert().True(running)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = s.client.WaitForJob(ctx, "running")
+	s.Assert().ErrorIs(err, k8s.ErrWaitingForJob)
+}
+
+func (s *TestSuite) TestListPodsFromJob() {
+	labels := map[string]string{"job-name": "attempts"}
+	_, err := s.client.Clientset().BatchV1().Jobs(s.namespace).Create(context.Background(), &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "attempts", Namespace: s.namespace},
+		Spec:       batchv1.JobSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+	}, metav1.CreateOptions{})
+	s.Require().NoError(err)
+
+	now := time.Now()
+	for name, created := range map[string]time.Time{
+		"attempts-second": now,
+		"attempts-first":  now.Add(-time.Minute),
+	} {
+		_, err := s.client.Clientset().CoreV1().Pods(s.namespace).Create(context.Background(), &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         s.namespace,
+				Labels:            labels,
+				CreationTimestamp: metav1.NewTime(created),
+			},
+		}, metav1.CreateOptions{})
+		s.Require().NoError(err)
+	}
+
+	pods, err := s.client.ListPodsFromJob(context.Background(), "attempts")
+	s.Require().NoError(err)
+	s.Require().Len(pods, 2)
+	s.Assert().Equal("attempts-first", pods[0].Name)
+	s.Assert().Equal("attempts-second", pods[1].Name)
+
+	s.Require().NoError(s.client.DeleteJob(context.Background(), "attempts"))
+	_, err = s.client.GetJob(context.Background(), "attempts")
+	s.Assert().ErrorIs(err, k8s.ErrGettingJob)
+	// deleting a missing Job is not an error
+	s.Assert().NoError(s.client.DeleteJob(context.Background(), "attempts"))
+}
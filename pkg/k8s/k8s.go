@@ -48,9 +48,36 @@ type Client struct {
 var _ KubeManager = &Client{}
 
 func NewClient(ctx context.Context, namespace string, logger *logrus.Logger) (*Client, error) {
+	cs, dc, dC, err := newClientsFromClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	return NewClientCustom(ctx, cs, dc, dC, namespace, logger)
+}
+
+// NewClientForExistingNamespace creates a client for a namespace that already exists.
+// Unlike NewClient, it does not try to create the namespace, which is usually not allowed
+// for the components of knuu that run inside the namespace.
+func NewClientForExistingNamespace(namespace string, logger *logrus.Logger) (*Client, error) {
+	cs, dc, dC, err := newClientsFromClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		clientset:          cs,
+		discoveryClient:    dc,
+		dynamicClient:      dC,
+		namespace:          SanitizeName(namespace),
+		logger:             logger,
+		terminated:         false,
+		maxPendingDuration: defaultMaxPendingDuration,
+	}, nil
+}
+
+func newClientsFromClusterConfig() (kubernetes.Interface, discovery.DiscoveryInterface, dynamic.Interface, error) {
 	config, err := getClusterConfig()
 	if err != nil {
-		return nil, ErrRetrievingKubernetesConfig.Wrap(err)
+		return nil, nil, nil, ErrRetrievingKubernetesConfig.Wrap(err)
 	}
 
 	// Set custom QPS and Burst to avoid client rate limit errors
@@ -59,19 +86,19 @@ func NewClient(ctx context.Context, namespace string, logger *logrus.Logger) (*C
 
 	cs, err := kubernetes.NewForConfig(config)
 	if err != nil {
-		return nil, ErrCreatingClientset.Wrap(err)
+		return nil, nil, nil, ErrCreatingClientset.Wrap(err)
 	}
 
 	dc, err := discovery.NewDiscoveryClientForConfig(config)
 	if err != nil {
-		return nil, ErrCreatingDiscoveryClient.Wrap(err)
+		return nil, nil, nil, ErrCreatingDiscoveryClient.Wrap(err)
 	}
 
 	dC, err := dynamic.NewForConfig(config)
 	if err != nil {
-		return nil, ErrCreatingDynamicClient.Wrap(err)
+		return nil, nil, nil, ErrCreatingDynamicClient.Wrap(err)
 	}
-	return NewClientCustom(ctx, cs, dc, dC, namespace, logger)
+	return cs, dc, dC, nil
 }
 
 func NewClientCustom(
@@ -0,0 +1,95 @@
+package k8s
+
+import (
+	"context"
+	"math"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// CreateLease creates a lease that expires after the given duration.
+// If the lease already exists, it is renewed with the given duration.
+func (c *Client) CreateLease(ctx context.Context, name string, labels map[string]string, duration time.Duration) (*coordinationv1.Lease, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+	if err := validateLeaseName(name); err != nil {
+		return nil, err
+	}
+	if err := validateLabels(labels); err != nil {
+		return nil, err
+	}
+
+	now := metav1.NewMicroTime(time.Now())
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.namespace,
+			Labels:    labels,
+		},
+		Spec: coordinationv1.LeaseSpec{
+			AcquireTime:          &now,
+			RenewTime:            &now,
+			LeaseDurationSeconds: ptr.To(leaseDurationSeconds(duration)),
+		},
+	}
+
+	created, err := c.clientset.CoordinationV1().Leases(c.namespace).Create(ctx, lease, metav1.CreateOptions{})
+	if err != nil {
+		if apierrs.IsAlreadyExists(err) {
+			return c.RenewLease(ctx, name, duration)
+		}
+		return nil, ErrCreatingLease.WithParams(name).Wrap(err)
+	}
+	return created, nil
+}
+
+// GetLease returns the lease with the given name.
+func (c *Client) GetLease(ctx context.Context, name string) (*coordinationv1.Lease, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+	return c.clientset.CoordinationV1().Leases(c.namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// RenewLease renews the lease so that it expires after the given duration from now.
+func (c *Client) RenewLease(ctx context.Context, name string, duration time.Duration) (*coordinationv1.Lease, error) {
+	lease, err := c.GetLease(ctx, name)
+	if err != nil {
+		return nil, ErrGettingLease.WithParams(name).Wrap(err)
+	}
+
+	now := metav1.NewMicroTime(time.Now())
+	lease.Spec.RenewTime = &now
+	lease.Spec.LeaseDurationSeconds = ptr.To(leaseDurationSeconds(duration))
+
+	updated, err := c.clientset.CoordinationV1().Leases(c.namespace).Update(ctx, lease, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, ErrUpdatingLease.WithParams(name).Wrap(err)
+	}
+	return updated, nil
+}
+
+// LeaseExpiry returns the time at which the given lease expires.
+// A lease that has never been renewed is considered expired.
+func LeaseExpiry(lease *coordinationv1.Lease) time.Time {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return time.Time{}
+	}
+	return lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+}
+
+func leaseDurationSeconds(duration time.Duration) int32 {
+	seconds := math.Ceil(duration.Seconds())
+	if seconds > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	if seconds < 0 {
+		return 0
+	}
+	return int32(seconds)
+}
@@ -0,0 +1,129 @@
+package k8s_test
+
+import (
+	"context"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+)
+
+func (s *TestSuite) TestCreateLease() {
+	tests := []struct {
+		name        string
+		leaseName   string
+		setupMock   func()
+		expectedErr error
+	}{
+		{
+			name:        "successful creation",
+			leaseName:   "test-lease",
+			setupMock:   func() {},
+			expectedErr: nil,
+		},
+		{
+			name:      "existing lease is renewed",
+			leaseName: "existing-lease",
+			setupMock: func() {
+				_, err := s.client.CreateLease(context.Background(), "existing-lease", nil, time.Second)
+				s.Require().NoError(err)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:        "invalid name",
+			leaseName:   "invalid_name",
+			setupMock:   func() {},
+			expectedErr: k8s.ErrInvalidLeaseName,
+		},
+		{
+			name:      "client error",
+			leaseName: "error-lease",
+			setupMock: func() {
+				s.client.Clientset().(*fake.Clientset).
+					PrependReactor("create", "leases",
+						func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+							return true, nil, errInternalServerError
+						})
+			},
+			expectedErr: k8s.ErrCreatingLease.WithParams("error-lease").Wrap(errInternalServerError),
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.setupMock()
+
+			lease, err := s.client.CreateLease(context.Background(), tt.leaseName, nil, time.Minute)
+			if tt.expectedErr != nil {
+				s.Require().Error(err)
+				s.Assert().ErrorIs(err, tt.expectedErr)
+				return
+			}
+
+			s.Require().NoError(err)
+			s.Assert().Equal(tt.leaseName, lease.Name)
+			s.Assert().Equal(int32(60), *lease.Spec.LeaseDurationSeconds)
+		})
+	}
+}
+
+func (s *TestSuite) TestRenewLease() {
+	tests := []struct {
+		name        string
+		leaseName   string
+		setupMock   func()
+		expectedErr error
+	}{
+		{
+			name:      "successful renewal",
+			leaseName: "test-lease",
+			setupMock: func() {
+				_, err := s.client.CreateLease(context.Background(), "test-lease", nil, time.Second)
+				s.Require().NoError(err)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:        "lease not found",
+			leaseName:   "missing-lease",
+			setupMock:   func() {},
+			expectedErr: k8s.ErrGettingLease,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.setupMock()
+
+			lease, err := s.client.RenewLease(context.Background(), tt.leaseName, time.Hour)
+			if tt.expectedErr != nil {
+				s.Require().Error(err)
+				s.Assert().ErrorIs(err, tt.expectedErr)
+				return
+			}
+
+			s.Require().NoError(err)
+			s.Assert().Equal(int32(3600), *lease.Spec.LeaseDurationSeconds)
+			s.Assert().WithinDuration(time.Now().Add(time.Hour), k8s.LeaseExpiry(lease), time.Minute)
+		})
+	}
+}
+
+func (s *TestSuite) TestLeaseExpiry() {
+	renewTime := metav1.NewMicroTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	lease := &coordinationv1.Lease{
+		Spec: coordinationv1.LeaseSpec{
+			RenewTime:            &renewTime,
+			LeaseDurationSeconds: ptr.To[int32](30),
+		},
+	}
+	s.Assert().Equal(renewTime.Add(30*time.Second), k8s.LeaseExpiry(lease))
+	s.Assert().True(k8s.LeaseExpiry(&coordinationv1.Lease{}).IsZero())
+}
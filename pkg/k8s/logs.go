@@ -8,7 +8,7 @@ import (
 )
 
 func (c *Client) GetLogStream(ctx context.Context, replicaSetName string, containerName string) (io.ReadCloser, error) {
-	logOptions := &v1.PodLogOptions{}
+	logOptions := v1.PodLogOptions{}
 	if containerName != "" {
 		logOptions.Container = containerName
 	}
@@ -18,6 +18,12 @@ func (c *Client) GetLogStream(ctx context.Context, replicaSetName string, contai
 		return nil, err
 	}
 
-	req := c.Clientset().CoreV1().Pods(c.Namespace()).GetLogs(pod.Name, logOptions)
+	return c.GetPodLogStream(ctx, pod.Name, logOptions)
+}
+
+// GetPodLogStream returns the logs of a pod with the given options,
+// e.g. the logs of the previous run of a container that has been restarted.
+func (c *Client) GetPodLogStream(ctx context.Context, podName string, logOptions v1.PodLogOptions) (io.ReadCloser, error) {
+	req := c.Clientset().CoreV1().Pods(c.Namespace()).GetLogs(podName, &logOptions)
 	return req.Stream(ctx)
 }
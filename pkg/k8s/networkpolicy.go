@@ -98,7 +98,9 @@ func (c *Client) GetNetworkPolicy(ctx context.Context, name string) (*v1.Network
 }
 
 func (c *Client) NetworkPolicyExists(ctx context.Context, name string) bool {
-	_, err := c.GetNetworkPolicy(ctx, name)
+	// the policy is not got with GetNetworkPolicy, as it would modify the shared ErrGettingNetworkPolicy
+	// for a missing policy, which is expected when instances of a group are destroyed in parallel
+	_, err := c.clientset.NetworkingV1().NetworkPolicies(c.namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		c.logger.WithField("name", name).WithError(err).Debug("getting networkPolicy")
 		return false
@@ -106,3 +108,15 @@ func (c *Client) NetworkPolicyExists(ctx context.Context, name string) bool {
 
 	return true
 }
+
+// ListNetworkPolicies returns all the network policies in the namespace.
+func (c *Client) ListNetworkPolicies(ctx context.Context) ([]v1.NetworkPolicy, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+	npList, err := c.clientset.NetworkingV1().NetworkPolicies(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, ErrListingNetworkPolicies.Wrap(err)
+	}
+	return npList.Items, nil
+}
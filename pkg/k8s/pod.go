@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -30,9 +33,38 @@ const (
 	defaultFileModeForVolume = 0777
 
 	podFilesConfigmapNameSuffix = "-config"
+	// remoteFilesNameSuffix is the suffix of the volume and of the init container of the files downloaded from their archive
+	remoteFilesNameSuffix = "-files"
+	// remoteFilesPath is the path where the files init container writes the staged files, see File.staged
+	remoteFilesPath = "/files"
+	// stagedFilesConfigPath is the path where the files init container mounts the ConfigMaps of the staged files
+	stagedFilesConfigPath = "/config"
+	// liveFilesNameSuffix is the suffix of the ConfigMap and of the Secret that hold the live files of a container
+	liveFilesNameSuffix = "-live-files"
+	// secretsNameSuffix is the suffix of the Secret, and of its volume, that holds the secret environment variables and files of a container
+	secretsNameSuffix = "-secrets"
 
 	initContainerNameSuffix = "-init"
 	defaultContainerUser    = 0
+
+	// seedContainerNameSuffix is the suffix of the init containers that seed the volumes
+	seedContainerNameSuffix = "-seed"
+	// downloadContainerImage is the image of the init containers that seed the volumes and stage the files,
+	// it has to provide curl, tar, chown and chmod.
+	// It is pinned, as the downloads are signed with curl's --aws-sigv4 and piped with set -o pipefail.
+	downloadContainerImage = "curlimages/curl:8.10.1"
+	// seedMarkerFile is created at the root of a volume once it is seeded, so it is only seeded once
+	seedMarkerFile = ".knuu-seeded"
+
+	// S3AccessKeyIDKey, S3SecretAccessKeyKey and S3RegionKey are the keys of the Secret holding the credentials
+	// the init containers sign their downloads with, see VolumeSeed.CredentialsSecret
+	S3AccessKeyIDKey     = "access-key-id"
+	S3SecretAccessKeyKey = "secret-access-key"
+	S3RegionKey          = "region"
+
+	s3AccessKeyIDEnv     = "KNUU_S3_ACCESS_KEY_ID"
+	s3SecretAccessKeyEnv = "KNUU_S3_SECRET_ACCESS_KEY"
+	s3RegionEnv          = "KNUU_S3_REGION"
 )
 
 type ContainerConfig struct {
@@ -43,6 +75,7 @@ type ContainerConfig struct {
 	Args            []string            // Arguments to pass to the command in the container
 	Env             map[string]string   // Environment variables to set in the container
 	Volumes         []*Volume           // Volumes to mount in the Pod
+	VolumeMounts    []*VolumeMount      // Volumes of the main container to mount in a sidecar
 	MemoryRequest   resource.Quantity   // Memory request for the container
 	MemoryLimit     resource.Quantity   // Memory limit for the container
 	CPURequest      resource.Quantity   // CPU request for the container
@@ -50,7 +83,13 @@ type ContainerConfig struct {
 	ReadinessProbe  *v1.Probe           // Readiness probe for the container
 	StartupProbe    *v1.Probe           // Startup probe for the container
 	Files           []*File             // Files to add to the Pod
+	FilesArchiveURL string              // URL of the tar archive of the remote files, see File.Remote
+	LiveFiles       []*LiveFile         // Files to add to the Pod that can be updated while it runs
+	SecretEnv       map[string]string   // SecretEnv maps environment variables to their key in the Secret of the container, see SecretsName
+	SecretFiles     []*SecretFile       // Files to add to the Pod from the Secret of the container
 	SecurityContext *v1.SecurityContext // Security context for the container
+	// FilesArchiveCredentialsSecret is the Secret with the credentials to download the files archive, see VolumeSeed.CredentialsSecret
+	FilesArchiveCredentialsSecret string
 }
 
 type PodConfig struct {
@@ -64,15 +103,121 @@ type PodConfig struct {
 	Annotations        map[string]string // Annotations to apply to the Pod
 }
 
+// Volume is a volume of a container, backed by its own PersistentVolumeClaim
 type Volume struct {
-	Path  string
-	Size  resource.Quantity
-	Owner int64
+	Name         string                        // Name of the volume, unique among the volumes of the container
+	Path         string                        // Path where the volume is mounted in the container
+	Size         resource.Quantity             // Size requested for the claim of the volume
+	Owner        int64                         // Owner of the content of the volume
+	StorageClass string                        // StorageClass of the claim, the default one of the cluster if empty
+	AccessMode   v1.PersistentVolumeAccessMode // AccessMode of the claim, ReadWriteOnce if empty
+	ClaimName    string                        // ClaimName is an existing claim to mount in a pod instead of the one of the volume
+	Seed         *VolumeSeed                   // Seed populates the volume the first time it is mounted, if set
+}
+
+// VolumeSeed is a tar archive extracted to a volume the first time it is mounted
+type VolumeSeed struct {
+	URL     string // URL the archive is downloaded from by the pod
+	Gzipped bool   // Gzipped is true if the archive is compressed with gzip
+	// CredentialsSecret is the Secret the download is signed with, if set, so the URL does not have to be presigned
+	// and does not expire. The Secret holds the keys S3AccessKeyIDKey, S3SecretAccessKeyKey and S3RegionKey.
+	CredentialsSecret string
+}
+
+// VolumeMount mounts a volume of the main container of the pod in a sidecar
+type VolumeMount struct {
+	Volume   string // Name of the volume of the main container
+	Path     string // Path where the volume is mounted in the sidecar
+	ReadOnly bool   // ReadOnly mounts the volume read-only
 }
 
 type File struct {
-	Source string
-	Dest   string
+	Source  string
+	Dest    string
+	Shard   int         // Shard is the index of the ConfigMap of the file, see FilesConfigMapName
+	Remote  bool        // Remote is true if the file is extracted from the files archive of its container instead of a ConfigMap
+	Mode    os.FileMode // Mode is the permissions of the file, 0777 if unset
+	Owner   int64       // Owner is the user ID of the file
+	Group   int64       // Group is the group ID of the file
+	Symlink string      // Symlink is the target of the file if it is a symbolic link, which can only be created in a volume
+}
+
+// staged reports whether the file is written to the files volume by the files init container instead of mounted from its ConfigMap.
+// The files of a ConfigMap belong to root, so the files of another user are staged to give them their owner.
+func (f *File) staged() bool {
+	return f.Remote || f.Owner != 0
+}
+
+// FilesConfigMapName returns the name of the ConfigMap of the given shard of the files of the container.
+// The first shard is named after the container.
+func FilesConfigMapName(containerName string, shard int) string {
+	if shard == 0 {
+		return containerName
+	}
+	return fmt.Sprintf("%s%s-%d", containerName, podFilesConfigmapNameSuffix, shard)
+}
+
+// filesVolumeName returns the name of the pod volume the file is mounted from
+func filesVolumeName(name string, file *File) string {
+	if file.staged() {
+		return name + remoteFilesNameSuffix
+	}
+	return shardVolumeName(name, file.Shard)
+}
+
+// shardVolumeName returns the name of the pod volume of the ConfigMap of the given shard of the files
+func shardVolumeName(name string, shard int) string {
+	if shard == 0 {
+		return name + podFilesConfigmapNameSuffix
+	}
+	return FilesConfigMapName(name, shard)
+}
+
+// fileMode returns the mode of a file of a ConfigMap volume, nil for the default mode of the volume
+func fileMode(file *File) *int32 {
+	if file.Mode == 0 {
+		return nil
+	}
+	return ptr.To(int32(file.Mode.Perm()))
+}
+
+// LiveFile is a file mounted from the ConfigMap or the Secret of the live files of its container, see LiveFilesName.
+// Unlike the other files, it is mounted with a mount of its directory instead of a subPath,
+// so the updates of its content appear in the container.
+type LiveFile struct {
+	Key    string // Key of the file in the ConfigMap or the Secret
+	Dest   string // Dest is the path of the file in the container
+	Secret bool   // Secret is true if the file is stored in the Secret instead of the ConfigMap
+}
+
+// SecretFile is a file mounted from the Secret of its container, see SecretsName
+type SecretFile struct {
+	Key  string      // Key of the file in the Secret
+	Dest string      // Dest is the path of the file in the container
+	Mode os.FileMode // Mode is the permissions of the file
+}
+
+// SecretsName returns the name of the Secret that holds the secret environment variables and files of the container
+func SecretsName(containerName string) string {
+	return containerName + secretsNameSuffix
+}
+
+// LiveFilesName returns the name of the ConfigMap and of the Secret that hold the live files of the container
+func LiveFilesName(containerName string) string {
+	return containerName + liveFilesNameSuffix
+}
+
+// ListPods returns the pods in the namespace that match the given labels.
+func (c *Client) ListPods(ctx context.Context, labels map[string]string) ([]v1.Pod, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+	selector := metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: labels})
+	podList, err := c.clientset.CoreV1().Pods(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, ErrListingPods.Wrap(err)
+	}
+	return podList.Items, nil
 }
 
 // DeployPod creates a new pod in the namespace that k8s client is initiate with if it doesn't already exist.
@@ -101,6 +246,28 @@ func (c *Client) NewVolume(path string, size resource.Quantity, owner int64) *Vo
 	}
 }
 
+// VolumeClaimName returns the name of the PersistentVolumeClaim of the volume of the container,
+// which is also the name of the volume in the pod
+func VolumeClaimName(containerName, volumeName string) string {
+	return containerName + "-" + volumeName
+}
+
+// accessModes returns the access modes of the claim of the volume
+func (v *Volume) accessModes() []v1.PersistentVolumeAccessMode {
+	if v.AccessMode == "" {
+		return []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}
+	}
+	return []v1.PersistentVolumeAccessMode{v.AccessMode}
+}
+
+// storageClassName returns the storage class of the claim of the volume, nil for the default one
+func (v *Volume) storageClassName() *string {
+	if v.StorageClass == "" {
+		return nil
+	}
+	return ptr.To(v.StorageClass)
+}
+
 func (c *Client) NewFile(source, dest string) *File {
 	return &File{
 		Source: source,
@@ -234,6 +401,67 @@ func (c *Client) RunCommandInPod(
 	return stdout.String(), nil
 }
 
+// StreamCommandInPod runs the command in the container of the pod, with the given stdin, which can be nil,
+// and streams its stdout to the given writer, so the output does not have to fit in memory.
+// Unlike RunCommandInPod, the command only fails on a non-zero exit code; its stderr is then part of the error.
+func (c *Client) StreamCommandInPod(
+	ctx context.Context,
+	podName,
+	containerName string,
+	cmd []string,
+	stdin io.Reader,
+	stdout io.Writer,
+) error {
+	if err := validatePodName(podName); err != nil {
+		return err
+	}
+	if err := validateContainerName(containerName); err != nil {
+		return err
+	}
+	if err := validateCommand(cmd); err != nil {
+		return err
+	}
+
+	if _, err := c.getPod(ctx, podName); err != nil {
+		return ErrGettingPod.WithParams(podName).Wrap(err)
+	}
+
+	req := c.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(podName).
+		Namespace(c.namespace).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Command:   cmd,
+			Container: containerName,
+			Stdin:     stdin != nil,
+			Stdout:    true,
+			Stderr:    true,
+			TTY:       false,
+		}, scheme.ParameterCodec)
+
+	k8sConfig, err := getClusterConfig()
+	if err != nil {
+		return ErrGettingK8sConfig.Wrap(err)
+	}
+	exec, err := remotecommand.NewSPDYExecutor(k8sConfig, http.MethodPost, req.URL())
+	if err != nil {
+		return ErrCreatingExecutor.Wrap(err)
+	}
+
+	var stderr bytes.Buffer
+	err = exec.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: &stderr,
+		Tty:    false,
+	})
+	if err != nil {
+		return ErrStreamingCommand.WithParams(strings.Join(cmd, " "), podName, stderr.String()).Wrap(err)
+	}
+	return nil
+}
+
 func (c *Client) DeletePodWithGracePeriod(ctx context.Context, name string, gracePeriodSeconds *int64) error {
 	if _, err := c.getPod(ctx, name); err != nil {
 		// If the pod does not exist, skip and return without error
@@ -363,38 +591,122 @@ func buildEnv(envMap map[string]string) []v1.EnvVar {
 	return envVars
 }
 
+// buildSecretEnv generates the environment variables of a container that are read from its Secret, ordered by name.
+func buildSecretEnv(name string, secretEnv map[string]string) []v1.EnvVar {
+	names := make([]string, 0, len(secretEnv))
+	for envName := range secretEnv {
+		names = append(names, envName)
+	}
+	sort.Strings(names)
+
+	envVars := make([]v1.EnvVar, 0, len(names))
+	for _, envName := range names {
+		envVars = append(envVars, v1.EnvVar{
+			Name: envName,
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: SecretsName(name)},
+					Key:                  secretEnv[envName],
+				},
+			},
+		})
+	}
+	return envVars
+}
+
+// buildSecretFileVolumes generates the volume and the mounts of the secret files of a container.
+// Each file is projected from the Secret with its own mode, and mounted with a subPath.
+func buildSecretFileVolumes(name string, secretFiles []*SecretFile) ([]v1.Volume, []v1.VolumeMount) {
+	if len(secretFiles) == 0 {
+		return nil, nil
+	}
+
+	var (
+		items  = make([]v1.KeyToPath, 0, len(secretFiles))
+		mounts = make([]v1.VolumeMount, 0, len(secretFiles))
+	)
+	for _, file := range secretFiles {
+		items = append(items, v1.KeyToPath{Key: file.Key, Path: file.Key, Mode: ptr.To(int32(file.Mode.Perm()))})
+		mounts = append(mounts, v1.VolumeMount{
+			Name:      SecretsName(name),
+			MountPath: file.Dest,
+			SubPath:   file.Key,
+			ReadOnly:  true,
+		})
+	}
+	volume := v1.Volume{
+		Name: SecretsName(name),
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{
+				SecretName: SecretsName(name),
+				Items:      items,
+			},
+		},
+	}
+	return []v1.Volume{volume}, mounts
+}
+
 // buildPodVolumes generates a volume configuration for a pod based on the given name.
-// If the volumes amount is zero, returns an empty slice.
-func buildPodVolumes(name string, volumesAmount, filesAmount int) []v1.Volume {
+// Each volume gets its own claim, each ConfigMap of the files its own volume, and the staged files share an emptyDir.
+// If there are no volumes and no files, returns an empty slice.
+func buildPodVolumes(name string, volumes []*Volume, files []*File) []v1.Volume {
 	var podVolumes []v1.Volume
 
-	if volumesAmount != 0 {
-		podVolume := v1.Volume{
-			Name: name,
+	for _, volume := range volumes {
+		claimName := VolumeClaimName(name, volume.Name)
+		if volume.ClaimName != "" {
+			claimName = volume.ClaimName
+		}
+		podVolumes = append(podVolumes, v1.Volume{
+			Name: VolumeClaimName(name, volume.Name),
 			VolumeSource: v1.VolumeSource{
 				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
-					ClaimName: name,
+					ClaimName: claimName,
 				},
 			},
-		}
-
-		podVolumes = append(podVolumes, podVolume)
+		})
 	}
 
-	if filesAmount != 0 {
-		podFiles := v1.Volume{
-			Name: name + podFilesConfigmapNameSuffix,
+	var (
+		// shards is the index of the volume of each ConfigMap in podVolumes
+		shards = make(map[int]int)
+		staged bool
+	)
+	for n, file := range files {
+		if file.Symlink != "" {
+			continue
+		}
+		staged = staged || file.staged()
+		if file.Remote {
+			continue
+		}
+
+		// each file is projected with its own mode, the staged files being copied from the ConfigMap
+		item := v1.KeyToPath{Key: fmt.Sprintf("%d", n), Path: fmt.Sprintf("%d", n), Mode: fileMode(file)}
+		if i, ok := shards[file.Shard]; ok {
+			podVolumes[i].ConfigMap.Items = append(podVolumes[i].ConfigMap.Items, item)
+			continue
+		}
+		shards[file.Shard] = len(podVolumes)
+		podVolumes = append(podVolumes, v1.Volume{
+			Name: shardVolumeName(name, file.Shard),
 			VolumeSource: v1.VolumeSource{
 				ConfigMap: &v1.ConfigMapVolumeSource{
 					LocalObjectReference: v1.LocalObjectReference{
-						Name: name,
+						Name: FilesConfigMapName(name, file.Shard),
 					},
+					Items:       []v1.KeyToPath{item},
 					DefaultMode: ptr.To[int32](defaultFileModeForVolume),
 				},
 			},
-		}
-
-		podVolumes = append(podVolumes, podFiles)
+		})
+	}
+	if staged {
+		// the staged files are written to the volume by the files init container
+		podVolumes = append(podVolumes, v1.Volume{
+			Name:         name + remoteFilesNameSuffix,
+			VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+		})
 	}
 
 	return podVolumes
@@ -407,9 +719,8 @@ func buildContainerVolumes(name string, volumes []*Volume, files []*File) []v1.V
 		containerVolumes = append(
 			containerVolumes,
 			v1.VolumeMount{
-				Name:      name,
+				Name:      VolumeClaimName(name, volume.Name),
 				MountPath: volume.Path,
-				SubPath:   strings.TrimLeft(volume.Path, "/"),
 			},
 		)
 	}
@@ -417,16 +728,10 @@ func buildContainerVolumes(name string, volumes []*Volume, files []*File) []v1.V
 	var containerFiles []v1.VolumeMount
 
 	for n, file := range files {
-		shouldAddFile := true
-		for _, volume := range volumes {
-			if strings.HasPrefix(file.Dest, volume.Path) {
-				shouldAddFile = false
-				break
-			}
-		}
-		if shouldAddFile {
+		// the files in the volumes are copied to them by the init container
+		if !inVolume(file.Dest, volumes) {
 			containerFiles = append(containerFiles, v1.VolumeMount{
-				Name:      name + podFilesConfigmapNameSuffix,
+				Name:      filesVolumeName(name, file),
 				MountPath: file.Dest,
 				SubPath:   fmt.Sprintf("%d", n),
 			})
@@ -436,23 +741,73 @@ func buildContainerVolumes(name string, volumes []*Volume, files []*File) []v1.V
 	return append(containerVolumes, containerFiles...)
 }
 
+// buildLiveFileVolumes generates the volumes and the mounts of the live files of a container.
+// The live files of a directory share a volume mounted at the directory, which only contains these files.
+func buildLiveFileVolumes(name string, liveFiles []*LiveFile) ([]v1.Volume, []v1.VolumeMount) {
+	var (
+		volumes []v1.Volume
+		mounts  []v1.VolumeMount
+		// volumeIndex is the index of the volume of each directory
+		volumeIndex = make(map[string]int)
+	)
+	for _, file := range liveFiles {
+		var (
+			dir  = filepath.Dir(file.Dest)
+			item = v1.KeyToPath{Key: file.Key, Path: filepath.Base(file.Dest)}
+		)
+		if n, ok := volumeIndex[dir]; ok {
+			if source := volumes[n].ConfigMap; source != nil {
+				source.Items = append(source.Items, item)
+			} else {
+				volumes[n].Secret.Items = append(volumes[n].Secret.Items, item)
+			}
+			continue
+		}
+
+		volume := v1.Volume{Name: fmt.Sprintf("%s-live-%d", name, len(volumes))}
+		if file.Secret {
+			volume.Secret = &v1.SecretVolumeSource{
+				SecretName:  LiveFilesName(name),
+				Items:       []v1.KeyToPath{item},
+				DefaultMode: ptr.To[int32](defaultFileModeForVolume),
+			}
+		} else {
+			volume.ConfigMap = &v1.ConfigMapVolumeSource{
+				LocalObjectReference: v1.LocalObjectReference{Name: LiveFilesName(name)},
+				Items:                []v1.KeyToPath{item},
+				DefaultMode:          ptr.To[int32](defaultFileModeForVolume),
+			}
+		}
+		volumeIndex[dir] = len(volumes)
+		volumes = append(volumes, volume)
+		mounts = append(mounts, v1.VolumeMount{Name: volume.Name, MountPath: dir})
+	}
+	return volumes, mounts
+}
+
 // buildInitContainerVolumes generates a volume mount configuration for an init container based on the given name and volumes.
 func buildInitContainerVolumes(name string, volumes []*Volume, files []*File) []v1.VolumeMount {
 	if len(volumes) == 0 && len(files) == 0 {
 		return []v1.VolumeMount{} // return empty slice if no volumes are specified
 	}
 
-	containerVolumes := []v1.VolumeMount{
-		{
-			Name:      name,
-			MountPath: knuuPath, // set the path to "/knuu" as per the requirements
-		},
+	// the volumes are mounted below "/knuu", where the init container copies their content
+	containerVolumes := make([]v1.VolumeMount, 0, len(volumes))
+	for _, volume := range volumes {
+		containerVolumes = append(containerVolumes, v1.VolumeMount{
+			Name:      VolumeClaimName(name, volume.Name),
+			MountPath: filepath.Join(knuuPath, volume.Path),
+		})
 	}
 
 	var containerFiles []v1.VolumeMount
 	for n, file := range files {
+		// the symlinks are created by the init container
+		if file.Symlink != "" {
+			continue
+		}
 		containerFiles = append(containerFiles, v1.VolumeMount{
-			Name:      name + podFilesConfigmapNameSuffix,
+			Name:      filesVolumeName(name, file),
 			MountPath: file.Dest,
 			SubPath:   fmt.Sprintf("%d", n),
 		})
@@ -473,6 +828,10 @@ func (c *Client) buildInitContainerCommand(volumes []*Volume, files []*File) []s
 
 	// for each file, get the directory and create the parent directory if it doesn't exist
 	for _, file := range files {
+		// the symlinks are created once the volumes are copied
+		if file.Symlink != "" {
+			continue
+		}
 		// get the directory of the file
 		folder := filepath.Dir(file.Dest)
 		if _, processed := dirsProcessed[folder]; !processed {
@@ -487,20 +846,23 @@ func (c *Client) buildInitContainerCommand(volumes []*Volume, files []*File) []s
 		cmds = append(cmds, copyFileToKnuu)
 	}
 
-	// for each volume, copy the contents of the volume to the knuu volume
+	// for each volume, copy the contents of the image to the volume, and give the volume to its owner
 	for i, volume := range volumes {
 		knuuVolumePath := fmt.Sprintf("%s%s", knuuPath, volume.Path)
-		cmd := fmt.Sprintf("if [ -d %s ] && [ \"$(ls -A %s)\" ]; then mkdir -p %s && cp -r %s/* %s && chown -R %d:%d %s",
-			volume.Path, volume.Path, knuuVolumePath, volume.Path,
-			knuuVolumePath, volume.Owner, volume.Owner, knuuVolumePath)
+		cmd := fmt.Sprintf("if [ -d %s ] && [ \"$(ls -A %s)\" ]; then cp -r %s/* %s ;fi && chown -R %d:%d %s",
+			volume.Path, volume.Path, volume.Path, knuuVolumePath,
+			volume.Owner, volume.Owner, knuuVolumePath)
 		if i < len(volumes)-1 {
-			cmd += " ;fi && "
-		} else {
-			cmd += " ;fi"
+			cmd += " && "
 		}
 		cmds = append(cmds, cmd)
 	}
 
+	// the files in the volumes are given their owner and mode after the volumes are given theirs
+	if fileCmds := buildVolumeFilesCommands(volumes, files); len(fileCmds) != 0 {
+		cmds = append(cmds, " && "+strings.Join(fileCmds, " && "))
+	}
+
 	fullCommand := strings.Join(cmds, "")
 	commands = append(commands, fullCommand)
 
@@ -508,6 +870,38 @@ func (c *Client) buildInitContainerCommand(volumes []*Volume, files []*File) []s
 	return commands
 }
 
+// buildVolumeFilesCommands generates the commands that create the symlinks in the volumes,
+// and give the files in the volumes their owner and their mode.
+func buildVolumeFilesCommands(volumes []*Volume, files []*File) []string {
+	var cmds []string
+	for _, file := range files {
+		if !inVolume(file.Dest, volumes) {
+			continue
+		}
+		knuuFile := filepath.Join(knuuPath, file.Dest)
+		if file.Symlink != "" {
+			cmds = append(cmds, fmt.Sprintf("mkdir -p %s && ln -sfn %s %s && chown -h %d:%d %s",
+				filepath.Dir(knuuFile), file.Symlink, knuuFile, file.Owner, file.Group, knuuFile))
+			continue
+		}
+		cmds = append(cmds, fmt.Sprintf("chown %d:%d %s", file.Owner, file.Group, knuuFile))
+		if file.Mode != 0 {
+			cmds = append(cmds, fmt.Sprintf("chmod %o %s", file.Mode.Perm(), knuuFile))
+		}
+	}
+	return cmds
+}
+
+// inVolume reports whether the path is in one of the volumes
+func inVolume(path string, volumes []*Volume) bool {
+	for _, volume := range volumes {
+		if strings.HasPrefix(path, volume.Path) {
+			return true
+		}
+	}
+	return false
+}
+
 // buildResources generates a resource configuration for a container based on the given CPU and memory requests and limits.
 func buildResources(memoryRequest, memoryLimit, cpuRequest resource.Quantity) v1.ResourceRequirements {
 	return v1.ResourceRequirements{
@@ -521,16 +915,32 @@ func buildResources(memoryRequest, memoryLimit, cpuRequest resource.Quantity) v1
 	}
 }
 
+// buildSharedVolumeMounts generates the mounts of the volumes of the main container of the pod, named owner, in a sidecar.
+func buildSharedVolumeMounts(owner string, mounts []*VolumeMount) []v1.VolumeMount {
+	volumeMounts := make([]v1.VolumeMount, 0, len(mounts))
+	for _, mount := range mounts {
+		volumeMounts = append(volumeMounts, v1.VolumeMount{
+			Name:      VolumeClaimName(owner, mount.Volume),
+			MountPath: mount.Path,
+			ReadOnly:  mount.ReadOnly,
+		})
+	}
+	return volumeMounts
+}
+
 // prepareContainer creates a v1.Container from a given ContainerConfig.
 func prepareContainer(config ContainerConfig) v1.Container {
+	_, liveFileMounts := buildLiveFileVolumes(config.Name, config.LiveFiles)
+	_, secretFileMounts := buildSecretFileVolumes(config.Name, config.SecretFiles)
+	volumeMounts := append(buildContainerVolumes(config.Name, config.Volumes, config.Files), liveFileMounts...)
 	return v1.Container{
 		Name:            config.Name,
 		Image:           config.Image,
 		ImagePullPolicy: config.ImagePullPolicy,
 		Command:         config.Command,
 		Args:            config.Args,
-		Env:             buildEnv(config.Env),
-		VolumeMounts:    buildContainerVolumes(config.Name, config.Volumes, config.Files),
+		Env:             append(buildEnv(config.Env), buildSecretEnv(config.Name, config.SecretEnv)...),
+		VolumeMounts:    append(volumeMounts, secretFileMounts...),
 		Resources:       buildResources(config.MemoryRequest, config.MemoryLimit, config.CPURequest),
 		LivenessProbe:   config.LivenessProbe,
 		ReadinessProbe:  config.ReadinessProbe,
@@ -541,12 +951,17 @@ func prepareContainer(config ContainerConfig) v1.Container {
 
 // prepareInitContainers creates a slice of v1.Container as init containers.
 func (c *Client) prepareInitContainers(config ContainerConfig, init bool) []v1.Container {
-	if !init || len(config.Volumes) == 0 {
+	if !init {
 		return nil
 	}
+	// the remote files are extracted first, so they can be copied to the volumes
+	initContainers := prepareFilesInitContainers(config)
+	if len(config.Volumes) == 0 {
+		return initContainers
+	}
 
-	return []v1.Container{
-		{
+	initContainers = append(initContainers,
+		v1.Container{
 			Name:  config.Name + initContainerNameSuffix,
 			Image: config.Image,
 			SecurityContext: &v1.SecurityContext{
@@ -555,12 +970,142 @@ func (c *Client) prepareInitContainers(config ContainerConfig, init bool) []v1.C
 			Command:      c.buildInitContainerCommand(config.Volumes, config.Files),
 			VolumeMounts: buildInitContainerVolumes(config.Name, config.Volumes, config.Files),
 		},
+	)
+	// the volumes are seeded after the content of the image is copied, so the seed takes precedence
+	for _, volume := range config.Volumes {
+		if volume.Seed == nil {
+			continue
+		}
+		initContainers = append(initContainers, v1.Container{
+			Name:  VolumeClaimName(config.Name, volume.Name) + seedContainerNameSuffix,
+			Image: downloadContainerImage,
+			SecurityContext: &v1.SecurityContext{
+				RunAsUser: ptr.To[int64](defaultContainerUser),
+			},
+			Command: buildSeedContainerCommand(volume),
+			Env:     buildS3CredentialsEnv(volume.Seed.CredentialsSecret),
+			VolumeMounts: []v1.VolumeMount{{
+				Name:      VolumeClaimName(config.Name, volume.Name),
+				MountPath: volume.Path,
+			}},
+		})
+	}
+	return initContainers
+}
+
+// prepareFilesInitContainers creates the init container that writes the staged files of the container to their volume,
+// if the container has staged files. It extracts the archive of the remote files, copies the other staged files
+// from their ConfigMap, and gives the files their owner and their mode.
+func prepareFilesInitContainers(config ContainerConfig) []v1.Container {
+	var (
+		cmds   = []string{"set -e -o pipefail"}
+		mounts = []v1.VolumeMount{{
+			Name:      config.Name + remoteFilesNameSuffix,
+			MountPath: remoteFilesPath,
+		}}
+		mountedShards = make(map[int]bool)
+		staged        bool
+		env           []v1.EnvVar
+	)
+	for _, file := range config.Files {
+		if file.Remote {
+			cmds = append(cmds, fmt.Sprintf("%s | tar -xf - -C %s",
+				buildDownloadCommand(config.FilesArchiveURL, config.FilesArchiveCredentialsSecret), remoteFilesPath))
+			env = buildS3CredentialsEnv(config.FilesArchiveCredentialsSecret)
+			break
+		}
+	}
+	for n, file := range config.Files {
+		if file.Symlink != "" || !file.staged() {
+			continue
+		}
+		staged = true
+		var (
+			key    = fmt.Sprintf("%d", n)
+			target = filepath.Join(remoteFilesPath, key)
+		)
+		if !file.Remote {
+			shardPath := filepath.Join(stagedFilesConfigPath, fmt.Sprintf("%d", file.Shard))
+			if !mountedShards[file.Shard] {
+				mountedShards[file.Shard] = true
+				mounts = append(mounts, v1.VolumeMount{
+					Name:      shardVolumeName(config.Name, file.Shard),
+					MountPath: shardPath,
+				})
+			}
+			cmds = append(cmds, fmt.Sprintf("cp %s %s", filepath.Join(shardPath, key), target))
+		}
+		cmds = append(cmds, fmt.Sprintf("chown %d:%d %s", file.Owner, file.Group, target))
+		if file.Mode != 0 {
+			cmds = append(cmds, fmt.Sprintf("chmod %o %s", file.Mode.Perm(), target))
+		}
+	}
+	if !staged {
+		return nil
+	}
+
+	return []v1.Container{{
+		Name:  config.Name + remoteFilesNameSuffix,
+		Image: downloadContainerImage,
+		SecurityContext: &v1.SecurityContext{
+			RunAsUser: ptr.To[int64](defaultContainerUser),
+		},
+		Command:      []string{"sh", "-c", strings.Join(cmds, " && ")},
+		Env:          env,
+		VolumeMounts: mounts,
+	}}
+}
+
+// buildSeedContainerCommand generates the command of the init container that seeds the volume.
+// The archive is only extracted if the volume has not been seeded yet, so the data is kept across restarts.
+func buildSeedContainerCommand(volume *Volume) []string {
+	tarFlags := "-xf"
+	if volume.Seed.Gzipped {
+		tarFlags = "-xzf"
+	}
+	marker := filepath.Join(volume.Path, seedMarkerFile)
+	cmd := fmt.Sprintf("set -e -o pipefail && if [ ! -f %s ]; then %s | tar %s - -C %s && chown -R %d:%d %s && touch %s ;fi",
+		marker, buildDownloadCommand(volume.Seed.URL, volume.Seed.CredentialsSecret), tarFlags, volume.Path, volume.Owner, volume.Owner, volume.Path, marker)
+	return []string{"sh", "-c", cmd}
+}
+
+// buildDownloadCommand generates the curl command that writes the content at the URL to its output.
+// If the credentials Secret is set, the request is signed with the credentials set by buildS3CredentialsEnv.
+func buildDownloadCommand(url, credentialsSecret string) string {
+	if credentialsSecret == "" {
+		return "curl -fsSL " + ShellQuote(url)
+	}
+	return fmt.Sprintf(`curl -fsSL --aws-sigv4 "aws:amz:${%s}:s3" --user "${%s}:${%s}" %s`,
+		s3RegionEnv, s3AccessKeyIDEnv, s3SecretAccessKeyEnv, ShellQuote(url))
+}
+
+// buildS3CredentialsEnv sets the credentials of the Secret in the environment of an init container, see buildDownloadCommand
+func buildS3CredentialsEnv(credentialsSecret string) []v1.EnvVar {
+	if credentialsSecret == "" {
+		return nil
+	}
+	fromSecret := func(name, key string) v1.EnvVar {
+		return v1.EnvVar{
+			Name: name,
+			ValueFrom: &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: credentialsSecret},
+				Key:                  key,
+			}},
+		}
+	}
+	return []v1.EnvVar{
+		fromSecret(s3AccessKeyIDEnv, S3AccessKeyIDKey),
+		fromSecret(s3SecretAccessKeyEnv, S3SecretAccessKeyKey),
+		fromSecret(s3RegionEnv, S3RegionKey),
 	}
 }
 
 // preparePodVolumes prepares pod volumes
 func preparePodVolumes(config ContainerConfig) []v1.Volume {
-	return buildPodVolumes(config.Name, len(config.Volumes), len(config.Files))
+	liveFileVolumes, _ := buildLiveFileVolumes(config.Name, config.LiveFiles)
+	secretFileVolumes, _ := buildSecretFileVolumes(config.Name, config.SecretFiles)
+	podVolumes := append(buildPodVolumes(config.Name, config.Volumes, config.Files), liveFileVolumes...)
+	return append(podVolumes, secretFileVolumes...)
 }
 
 func (c *Client) preparePodSpec(spec PodConfig, init bool) v1.PodSpec {
@@ -575,6 +1120,11 @@ func (c *Client) preparePodSpec(spec PodConfig, init bool) v1.PodSpec {
 	// Prepare sidecar containers and append to the pod spec
 	for _, sidecarConfig := range spec.SidecarConfigs {
 		sidecarContainer := prepareContainer(sidecarConfig)
+		if init {
+			podSpec.InitContainers = append(podSpec.InitContainers, prepareFilesInitContainers(sidecarConfig)...)
+		}
+		sidecarContainer.VolumeMounts = append(sidecarContainer.VolumeMounts,
+			buildSharedVolumeMounts(spec.ContainerConfig.Name, sidecarConfig.VolumeMounts)...)
 		sidecarVolumes := preparePodVolumes(sidecarConfig)
 
 		podSpec.Containers = append(podSpec.Containers, sidecarContainer)
@@ -2,12 +2,15 @@ package k8s_test
 
 import (
 	"context"
+	"strings"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
 	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
 
 	"github.com/celestiaorg/knuu/pkg/k8s"
 )
@@ -69,6 +72,263 @@ func (s *TestSuite) TestDeployPod() {
 	}
 }
 
+func (s *TestSuite) TestDeployPodWithSeededVolume() {
+	containerConfig := testContainerConfig
+	containerConfig.Volumes = []*k8s.Volume{
+		{
+			Name:  "data",
+			Path:  "/data",
+			Size:  resource.MustParse("1Gi"),
+			Owner: 1000,
+			Seed: &k8s.VolumeSeed{
+				URL:               "http://minio/snapshot.tar.gz?a=1&b=2",
+				Gzipped:           true,
+				CredentialsSecret: "minio-credentials",
+			},
+		},
+		{Name: "keys", Path: "/keys", Size: resource.MustParse("1Mi")},
+	}
+
+	pod, err := s.client.DeployPod(context.Background(), k8s.PodConfig{
+		Namespace:       s.namespace,
+		Name:            "seeded-pod",
+		ContainerConfig: containerConfig,
+	}, true)
+	s.Require().NoError(err)
+
+	// the image content is copied first, then only the seeded volume gets a seed container
+	initContainers := pod.Spec.InitContainers
+	s.Require().Len(initContainers, 2)
+	s.Assert().Equal(containerConfig.Name+"-init", initContainers[0].Name)
+	seed := initContainers[1]
+	s.Assert().Equal(containerConfig.Name+"-data-seed", seed.Name)
+	s.Assert().Equal("curlimages/curl:8.10.1", seed.Image)
+	s.Assert().Equal([]v1.VolumeMount{{Name: containerConfig.Name + "-data", MountPath: "/data"}}, seed.VolumeMounts)
+	s.Require().Len(seed.Command, 3)
+	s.Assert().Equal("set -e -o pipefail && if [ ! -f /data/.knuu-seeded ]; then "+
+		`curl -fsSL --aws-sigv4 "aws:amz:${KNUU_S3_REGION}:s3" --user "${KNUU_S3_ACCESS_KEY_ID}:${KNUU_S3_SECRET_ACCESS_KEY}" `+
+		"'http://minio/snapshot.tar.gz?a=1&b=2' | tar -xzf - -C /data && chown -R 1000:1000 /data && touch /data/.knuu-seeded ;fi",
+		seed.Command[2])
+	// the download is signed with the credentials of the secret, so the URL does not expire
+	env := make(map[string]v1.SecretKeySelector)
+	for _, e := range seed.Env {
+		s.Require().NotNil(e.ValueFrom)
+		s.Require().NotNil(e.ValueFrom.SecretKeyRef)
+		env[e.Name] = *e.ValueFrom.SecretKeyRef
+	}
+	credentials := v1.LocalObjectReference{Name: "minio-credentials"}
+	s.Assert().Equal(map[string]v1.SecretKeySelector{
+		"KNUU_S3_ACCESS_KEY_ID":     {LocalObjectReference: credentials, Key: k8s.S3AccessKeyIDKey},
+		"KNUU_S3_SECRET_ACCESS_KEY": {LocalObjectReference: credentials, Key: k8s.S3SecretAccessKeyKey},
+		"KNUU_S3_REGION":            {LocalObjectReference: credentials, Key: k8s.S3RegionKey},
+	}, env)
+}
+
+func (s *TestSuite) TestDeployPodWithShardedFiles() {
+	containerConfig := testContainerConfig
+	containerConfig.Files = []*k8s.File{
+		{Source: "/tmp/genesis.json", Dest: "/etc/app/genesis.json"},
+		{Source: "/tmp/config.toml", Dest: "/etc/app/config.toml", Shard: 1},
+		{Source: "/tmp/snapshot.db", Dest: "/data/snapshot.db", Remote: true},
+	}
+	containerConfig.Volumes = []*k8s.Volume{{Name: "data", Path: "/data", Size: resource.MustParse("1Gi")}}
+
+	_, err := s.client.DeployPod(context.Background(), k8s.PodConfig{
+		Namespace:       s.namespace,
+		Name:            "files-pod",
+		ContainerConfig: containerConfig,
+	}, true)
+	s.Assert().ErrorIs(err, k8s.ErrFilesArchiveURLEmpty)
+
+	containerConfig.FilesArchiveURL = "http://minio/files.tar"
+	pod, err := s.client.DeployPod(context.Background(), k8s.PodConfig{
+		Namespace:       s.namespace,
+		Name:            "files-pod",
+		ContainerConfig: containerConfig,
+	}, true)
+	s.Require().NoError(err)
+
+	// each shard is mounted from its own configmap, and the remote files from the volume of the files init container
+	volumes := make(map[string]v1.VolumeSource)
+	for _, volume := range pod.Spec.Volumes {
+		volumes[volume.Name] = volume.VolumeSource
+	}
+	s.Require().Len(volumes, 4)
+	s.Assert().Equal(containerConfig.Name, volumes[containerConfig.Name+"-config"].ConfigMap.Name)
+	s.Assert().Equal(k8s.FilesConfigMapName(containerConfig.Name, 1), volumes[containerConfig.Name+"-config-1"].ConfigMap.Name)
+	s.Assert().NotNil(volumes[containerConfig.Name+"-files"].EmptyDir)
+
+	s.Require().Len(pod.Spec.InitContainers, 2)
+	files := pod.Spec.InitContainers[0]
+	s.Assert().Equal(containerConfig.Name+"-files", files.Name)
+	s.Assert().Equal("curlimages/curl:8.10.1", files.Image)
+	s.Assert().Equal([]v1.VolumeMount{{Name: containerConfig.Name + "-files", MountPath: "/files"}}, files.VolumeMounts)
+	s.Require().Len(files.Command, 3)
+	s.Assert().Equal("set -e -o pipefail && curl -fsSL 'http://minio/files.tar' | tar -xf - -C /files && chown 0:0 /files/2", files.Command[2])
+	s.Assert().Empty(files.Env, "the archive is downloaded without credentials")
+	// the remote file below the volume is copied to it by the init container
+	s.Assert().Contains(pod.Spec.InitContainers[1].VolumeMounts,
+		v1.VolumeMount{Name: containerConfig.Name + "-files", MountPath: "/data/snapshot.db", SubPath: "2"})
+	s.Assert().Equal([]v1.VolumeMount{
+		{Name: containerConfig.Name + "-data", MountPath: "/data"},
+		{Name: containerConfig.Name + "-config", MountPath: "/etc/app/genesis.json", SubPath: "0"},
+		{Name: containerConfig.Name + "-config-1", MountPath: "/etc/app/config.toml", SubPath: "1"},
+	}, pod.Spec.Containers[0].VolumeMounts)
+
+	// with the credentials secret, the download is signed and the URL does not have to be presigned
+	containerConfig.FilesArchiveCredentialsSecret = "minio-credentials"
+	pod, err = s.client.DeployPod(context.Background(), k8s.PodConfig{
+		Namespace:       s.namespace,
+		Name:            "signed-files-pod",
+		ContainerConfig: containerConfig,
+	}, true)
+	s.Require().NoError(err)
+	files = pod.Spec.InitContainers[0]
+	s.Assert().Contains(files.Command[2], `curl -fsSL --aws-sigv4 "aws:amz:${KNUU_S3_REGION}:s3" `)
+	s.Assert().Len(files.Env, 3)
+}
+
+func (s *TestSuite) TestDeployPodWithFileOwnersAndSymlinks() {
+	containerConfig := testContainerConfig
+	containerConfig.Files = []*k8s.File{
+		{Source: "/tmp/config.toml", Dest: "/etc/app/config.toml", Mode: 0o644},
+		{Source: "/tmp/node_key.json", Dest: "/etc/app/node_key.json", Mode: 0o600, Owner: 10001, Group: 10001},
+		{Source: "/tmp/celestia", Dest: "/data/bin/celestia", Mode: 0o755, Owner: 10001, Group: 10001},
+		{Source: "/tmp/latest", Dest: "/data/bin/latest", Symlink: "celestia", Owner: 10001, Group: 10001},
+	}
+	containerConfig.Volumes = []*k8s.Volume{{Name: "data", Path: "/data", Size: resource.MustParse("1Gi"), Owner: 10001}}
+
+	// the symlinks can only be created in the volumes
+	configWithSymlink := containerConfig
+	configWithSymlink.Files = append(containerConfig.Files, &k8s.File{Source: "/tmp/link", Dest: "/etc/app/link", Symlink: "config.toml"})
+	_, err := s.client.DeployPod(context.Background(), k8s.PodConfig{
+		Namespace:       s.namespace,
+		Name:            "owners-pod",
+		ContainerConfig: configWithSymlink,
+	}, true)
+	s.Assert().ErrorIs(err, k8s.ErrSymlinkNotInVolume)
+
+	pod, err := s.client.DeployPod(context.Background(), k8s.PodConfig{
+		Namespace:       s.namespace,
+		Name:            "owners-pod",
+		ContainerConfig: containerConfig,
+	}, true)
+	s.Require().NoError(err)
+
+	// the files keep their mode, and the files of another user are staged by the files init container
+	var configMap *v1.ConfigMapVolumeSource
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name == containerConfig.Name+"-config" {
+			configMap = volume.ConfigMap
+		}
+	}
+	s.Require().NotNil(configMap)
+	s.Assert().Equal([]v1.KeyToPath{
+		{Key: "0", Path: "0", Mode: ptr.To[int32](0o644)},
+		{Key: "1", Path: "1", Mode: ptr.To[int32](0o600)},
+		{Key: "2", Path: "2", Mode: ptr.To[int32](0o755)},
+	}, configMap.Items)
+	s.Assert().Equal([]v1.VolumeMount{
+		{Name: containerConfig.Name + "-data", MountPath: "/data"},
+		{Name: containerConfig.Name + "-config", MountPath: "/etc/app/config.toml", SubPath: "0"},
+		{Name: containerConfig.Name + "-files", MountPath: "/etc/app/node_key.json", SubPath: "1"},
+	}, pod.Spec.Containers[0].VolumeMounts)
+
+	s.Require().Len(pod.Spec.InitContainers, 2)
+	files := pod.Spec.InitContainers[0]
+	s.Assert().Equal("curlimages/curl:8.10.1", files.Image)
+	s.Assert().Equal([]v1.VolumeMount{
+		{Name: containerConfig.Name + "-files", MountPath: "/files"},
+		{Name: containerConfig.Name + "-config", MountPath: "/config/0"},
+	}, files.VolumeMounts)
+	s.Assert().Equal("set -e -o pipefail"+
+		" && cp /config/0/1 /files/1 && chown 10001:10001 /files/1 && chmod 600 /files/1"+
+		" && cp /config/0/2 /files/2 && chown 10001:10001 /files/2 && chmod 755 /files/2", files.Command[2])
+
+	// the files in the volume are given their owner after the volume, and the symlink is created
+	s.Assert().True(strings.HasSuffix(pod.Spec.InitContainers[1].Command[2],
+		"chown -R 10001:10001 /knuu/data"+
+			" && chown 10001:10001 /knuu/data/bin/celestia && chmod 755 /knuu/data/bin/celestia"+
+			" && mkdir -p /knuu/data/bin && ln -sfn celestia /knuu/data/bin/latest && chown -h 10001:10001 /knuu/data/bin/latest"),
+		pod.Spec.InitContainers[1].Command[2])
+}
+
+func (s *TestSuite) TestDeployPodWithSecrets() {
+	containerConfig := testContainerConfig
+	containerConfig.SecretEnv = map[string]string{"MNEMONIC": "env-MNEMONIC", "API_TOKEN": "env-API_TOKEN"}
+	containerConfig.SecretFiles = []*k8s.SecretFile{{Key: "file-0", Dest: "/keys/node_key.json", Mode: 0o400}}
+
+	pod, err := s.client.DeployPod(context.Background(), k8s.PodConfig{
+		Namespace:       s.namespace,
+		Name:            "secrets-pod",
+		ContainerConfig: containerConfig,
+	}, false)
+	s.Require().NoError(err)
+
+	// the values are only referenced, they are never in the pod spec
+	secrets := k8s.SecretsName(containerConfig.Name)
+	container := pod.Spec.Containers[0]
+	secretEnv := func(name string) v1.EnvVar {
+		return v1.EnvVar{Name: name, ValueFrom: &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: secrets},
+			Key:                  "env-" + name,
+		}}}
+	}
+	s.Assert().Equal([]v1.EnvVar{secretEnv("API_TOKEN"), secretEnv("MNEMONIC")}, container.Env)
+	s.Assert().Equal([]v1.VolumeMount{{Name: secrets, MountPath: "/keys/node_key.json", SubPath: "file-0", ReadOnly: true}}, container.VolumeMounts)
+	s.Assert().Equal([]v1.Volume{{
+		Name: secrets,
+		VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{
+			SecretName: secrets,
+			Items:      []v1.KeyToPath{{Key: "file-0", Path: "file-0", Mode: ptr.To[int32](0o400)}},
+		}},
+	}}, pod.Spec.Volumes)
+
+	containerConfig.SecretFiles = []*k8s.SecretFile{{Key: "file-0", Dest: "keys/node_key.json"}}
+	_, err = s.client.DeployPod(context.Background(), k8s.PodConfig{
+		Namespace:       s.namespace,
+		Name:            "invalid-secrets-pod",
+		ContainerConfig: containerConfig,
+	}, false)
+	s.Assert().ErrorIs(err, k8s.ErrInvalidSecretFile)
+}
+
+func (s *TestSuite) TestDeployPodWithLiveFiles() {
+	containerConfig := testContainerConfig
+	containerConfig.LiveFiles = []*k8s.LiveFile{
+		{Key: "0", Dest: "/etc/app/config.toml"},
+		{Key: "1", Dest: "/etc/app/peers.txt"},
+		{Key: "2", Dest: "/etc/keys/node_key.json", Secret: true},
+	}
+
+	pod, err := s.client.DeployPod(context.Background(), k8s.PodConfig{
+		Namespace:       s.namespace,
+		Name:            "live-files-pod",
+		ContainerConfig: containerConfig,
+	}, false)
+	s.Require().NoError(err)
+
+	// the live files of a directory share a volume mounted at the directory
+	liveFiles := k8s.LiveFilesName(containerConfig.Name)
+	s.Require().Len(pod.Spec.Volumes, 2)
+	s.Assert().Equal(liveFiles, pod.Spec.Volumes[0].ConfigMap.Name)
+	s.Assert().Equal([]v1.KeyToPath{{Key: "0", Path: "config.toml"}, {Key: "1", Path: "peers.txt"}}, pod.Spec.Volumes[0].ConfigMap.Items)
+	s.Assert().Equal(liveFiles, pod.Spec.Volumes[1].Secret.SecretName)
+	s.Assert().Equal([]v1.KeyToPath{{Key: "2", Path: "node_key.json"}}, pod.Spec.Volumes[1].Secret.Items)
+	s.Assert().Equal([]v1.VolumeMount{
+		{Name: pod.Spec.Volumes[0].Name, MountPath: "/etc/app"},
+		{Name: pod.Spec.Volumes[1].Name, MountPath: "/etc/keys"},
+	}, pod.Spec.Containers[0].VolumeMounts)
+
+	containerConfig.LiveFiles = append(containerConfig.LiveFiles, &k8s.LiveFile{Key: "3", Dest: "/etc/app/key.json", Secret: true})
+	_, err = s.client.DeployPod(context.Background(), k8s.PodConfig{
+		Namespace:       s.namespace,
+		Name:            "mixed-live-files-pod",
+		ContainerConfig: containerConfig,
+	}, false)
+	s.Assert().ErrorIs(err, k8s.ErrLiveFilesMixedInDirectory)
+}
+
 func (s *TestSuite) TestReplacePod() {
 	tests := []struct {
 		name        string
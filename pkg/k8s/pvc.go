@@ -10,11 +10,14 @@ import (
 )
 
 // CreatePersistentVolumeClaim deploys a PersistentVolumeClaim if it does not exist.
+// An empty storage class uses the default one of the cluster, and an empty access mode is ReadWriteOnce.
 func (c *Client) CreatePersistentVolumeClaim(
 	ctx context.Context,
 	name string,
 	labels map[string]string,
 	size resource.Quantity,
+	storageClass string,
+	accessMode v1.PersistentVolumeAccessMode,
 ) error {
 	if c.terminated {
 		return ErrClientTerminated
@@ -28,7 +31,11 @@ func (c *Client) CreatePersistentVolumeClaim(
 	if err := validateLabels(labels); err != nil {
 		return err
 	}
+	if err := validateAccessMode(accessMode); err != nil {
+		return err
+	}
 
+	volume := &Volume{Size: size, StorageClass: storageClass, AccessMode: accessMode}
 	pvc := &v1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: c.namespace,
@@ -36,9 +43,8 @@ func (c *Client) CreatePersistentVolumeClaim(
 			Labels:    labels,
 		},
 		Spec: v1.PersistentVolumeClaimSpec{
-			AccessModes: []v1.PersistentVolumeAccessMode{
-				v1.ReadWriteOnce,
-			},
+			AccessModes:      volume.accessModes(),
+			StorageClassName: volume.storageClassName(),
 			Resources: v1.VolumeResourceRequirements{
 				Requests: v1.ResourceList{
 					v1.ResourceStorage: size,
@@ -47,7 +53,12 @@ func (c *Client) CreatePersistentVolumeClaim(
 		},
 	}
 
-	if _, err := c.clientset.CoreV1().PersistentVolumeClaims(c.namespace).Create(ctx, pvc, metav1.CreateOptions{}); err != nil {
+	_, err := c.clientset.CoreV1().PersistentVolumeClaims(c.namespace).Create(ctx, pvc, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		c.logger.WithField("name", name).Debug("PersistentVolumeClaim already exists")
+		return nil
+	}
+	if err != nil {
 		return ErrCreatingPersistentVolumeClaim.WithParams(name).Wrap(err)
 	}
 
@@ -73,6 +84,19 @@ func (c *Client) DeletePersistentVolumeClaim(ctx context.Context, name string) e
 	return nil
 }
 
+// ListPersistentVolumeClaims returns the PersistentVolumeClaims in the namespace that match the given labels.
+func (c *Client) ListPersistentVolumeClaims(ctx context.Context, labels map[string]string) ([]v1.PersistentVolumeClaim, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+	selector := metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: labels})
+	pvcList, err := c.clientset.CoreV1().PersistentVolumeClaims(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, ErrListingPersistentVolumeClaims.Wrap(err)
+	}
+	return pvcList.Items, nil
+}
+
 func (c *Client) getPersistentVolumeClaim(ctx context.Context, name string) (*v1.PersistentVolumeClaim, error) {
 	return c.clientset.CoreV1().PersistentVolumeClaims(c.namespace).Get(ctx, name, metav1.GetOptions{})
 }
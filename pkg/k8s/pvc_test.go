@@ -15,12 +15,14 @@ import (
 
 func (s *TestSuite) TestCreatePersistentVolumeClaim() {
 	tests := []struct {
-		name        string
-		pvcName     string
-		labels      map[string]string
-		size        resource.Quantity
-		setupMock   func()
-		expectedErr error
+		name         string
+		pvcName      string
+		labels       map[string]string
+		size         resource.Quantity
+		storageClass string
+		accessMode   v1.PersistentVolumeAccessMode
+		setupMock    func()
+		expectedErr  error
 	}{
 		{
 			name:        "successful creation",
@@ -30,6 +32,33 @@ func (s *TestSuite) TestCreatePersistentVolumeClaim() {
 			setupMock:   func() {},
 			expectedErr: nil,
 		},
+		{
+			name:         "with storage class and access mode",
+			pvcName:      "shared-pvc",
+			labels:       map[string]string{"app": "test"},
+			size:         resource.MustParse("1Gi"),
+			storageClass: "nfs",
+			accessMode:   v1.ReadWriteMany,
+			setupMock:    func() {},
+			expectedErr:  nil,
+		},
+		{
+			name:        "already exists",
+			pvcName:     "test-pvc",
+			labels:      map[string]string{"app": "test"},
+			size:        resource.MustParse("1Gi"),
+			setupMock:   func() {},
+			expectedErr: nil,
+		},
+		{
+			name:        "invalid access mode",
+			pvcName:     "invalid-pvc",
+			labels:      map[string]string{"app": "test"},
+			size:        resource.MustParse("1Gi"),
+			accessMode:  "ReadSometimes",
+			setupMock:   func() {},
+			expectedErr: k8s.ErrInvalidVolumeAccessMode,
+		},
 		{
 			name:    "client error",
 			pvcName: "error-pvc",
@@ -50,7 +79,7 @@ func (s *TestSuite) TestCreatePersistentVolumeClaim() {
 		s.Run(tt.name, func() {
 			tt.setupMock()
 
-			err := s.client.CreatePersistentVolumeClaim(context.Background(), tt.pvcName, tt.labels, tt.size)
+			err := s.client.CreatePersistentVolumeClaim(context.Background(), tt.pvcName, tt.labels, tt.size, tt.storageClass, tt.accessMode)
 			if tt.expectedErr != nil {
 				s.Require().Error(err)
 				s.Assert().ErrorIs(err, tt.expectedErr)
@@ -58,6 +87,15 @@ func (s *TestSuite) TestCreatePersistentVolumeClaim() {
 			}
 
 			s.Require().NoError(err)
+			pvc, err := s.client.Clientset().CoreV1().PersistentVolumeClaims(s.namespace).Get(context.Background(), tt.pvcName, metav1.GetOptions{})
+			s.Require().NoError(err)
+			if tt.accessMode != "" {
+				s.Assert().Equal([]v1.PersistentVolumeAccessMode{tt.accessMode}, pvc.Spec.AccessModes)
+			}
+			if tt.storageClass != "" {
+				s.Require().NotNil(pvc.Spec.StorageClassName)
+				s.Assert().Equal(tt.storageClass, *pvc.Spec.StorageClassName)
+			}
 		})
 	}
 }
@@ -2,6 +2,7 @@ package k8s
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -98,6 +99,19 @@ func (c *Client) DeleteReplicaSet(ctx context.Context, name string) error {
 	return c.DeleteReplicaSetWithGracePeriod(ctx, name, nil)
 }
 
+// ListReplicaSets returns the replicaSets in the namespace that match the given labels.
+func (c *Client) ListReplicaSets(ctx context.Context, labels map[string]string) ([]appv1.ReplicaSet, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+	selector := metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: labels})
+	rsList, err := c.clientset.AppsV1().ReplicaSets(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, ErrListingReplicaSets.Wrap(err)
+	}
+	return rsList.Items, nil
+}
+
 func (c *Client) GetFirstPodFromReplicaSet(ctx context.Context, name string) (*v1.Pod, error) {
 	rsName, err := c.getReplicaSet(ctx, name)
 	if err != nil {
@@ -120,6 +134,53 @@ func (c *Client) GetFirstPodFromReplicaSet(ctx context.Context, name string) (*v
 	return c.getPod(ctx, pods.Items[0].Name)
 }
 
+// ListPodsFromReplicaSet returns the pods of the ReplicaSet, the oldest first.
+// Pods created at the same time are sorted by name, so the order is stable as long as the pods are not replaced.
+func (c *Client) ListPodsFromReplicaSet(ctx context.Context, name string) ([]v1.Pod, error) {
+	rs, err := c.getReplicaSet(ctx, name)
+	if err != nil {
+		return nil, ErrGettingReplicaSet.WithParams(name).Wrap(err)
+	}
+	selector := metav1.FormatLabelSelector(rs.Spec.Selector)
+	pods, err := c.clientset.CoreV1().Pods(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, ErrListingPodsForReplicaSet.WithParams(name).Wrap(err)
+	}
+
+	sort.Slice(pods.Items, func(a, b int) bool {
+		ta, tb := pods.Items[a].CreationTimestamp, pods.Items[b].CreationTimestamp
+		if !ta.Equal(&tb) {
+			return ta.Before(&tb)
+		}
+		return pods.Items[a].Name < pods.Items[b].Name
+	})
+	return pods.Items, nil
+}
+
+// ScaleReplicaSet sets the number of replicas of the ReplicaSet
+func (c *Client) ScaleReplicaSet(ctx context.Context, name string, replicas int32) (*appv1.ReplicaSet, error) {
+	if replicas < 0 {
+		return nil, ErrReplicaSetReplicasNegative.WithParams(replicas)
+	}
+	rs, err := c.getReplicaSet(ctx, name)
+	if err != nil {
+		return nil, ErrGettingReplicaSet.WithParams(name).Wrap(err)
+	}
+
+	rs.Spec.Replicas = &replicas
+	rs, err = c.clientset.AppsV1().ReplicaSets(c.namespace).Update(ctx, rs, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, ErrScalingReplicaSet.WithParams(name).Wrap(err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"name":      name,
+		"namespace": c.namespace,
+		"replicas":  replicas,
+	}).Debug("scaled replicaSet")
+	return rs, nil
+}
+
 func (c *Client) getReplicaSet(ctx context.Context, name string) (*appv1.ReplicaSet, error) {
 	if c.terminated {
 		return nil, ErrClientTerminated
@@ -2,8 +2,10 @@ package k8s_test
 
 import (
 	"context"
+	"time"
 
 	appv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
@@ -477,6 +479,67 @@ func (s *TestSuite) TestDeleteReplicaSet() {
 	}
 }
 
+func (s *TestSuite) TestListReplicaSets() {
+	labels := map[string]string{"knuu.sh/scope": "test"}
+	tests := []struct {
+		name          string
+		setupMock     func()
+		expectedNames []string
+		expectedErr   error
+	}{
+		{
+			name: "only matching replica sets are listed",
+			setupMock: func() {
+				for name, l := range map[string]map[string]string{
+					"match-rs":    labels,
+					"no-match-rs": {"knuu.sh/scope": "other"},
+				} {
+					_, err := s.client.Clientset().AppsV1().ReplicaSets(s.namespace).Create(context.Background(), &appv1.ReplicaSet{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      name,
+							Namespace: s.namespace,
+							Labels:    l,
+						},
+					}, metav1.CreateOptions{})
+					s.Require().NoError(err)
+				}
+			},
+			expectedNames: []string{"match-rs"},
+		},
+		{
+			name: "client error",
+			setupMock: func() {
+				s.client.Clientset().(*fake.Clientset).
+					PrependReactor("list", "replicasets",
+						func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+							return true, nil, errInternalServerError
+						})
+			},
+			expectedErr: k8s.ErrListingReplicaSets.Wrap(errInternalServerError),
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.setupMock()
+
+			rsList, err := s.client.ListReplicaSets(context.Background(), labels)
+			if tt.expectedErr != nil {
+				s.Require().Error(err)
+				s.Assert().ErrorIs(err, tt.expectedErr)
+				return
+			}
+
+			s.Require().NoError(err)
+			names := make([]string, 0, len(rsList))
+			for _, rs := range rsList {
+				names = append(names, rs.Name)
+			}
+			s.Assert().Equal(tt.expectedNames, names)
+		})
+	}
+}
+
 func (s *TestSuite) createReplicaSet(name string) error {
 	_, err := s.client.Clientset().AppsV1().ReplicaSets(s.namespace).Create(context.Background(), &appv1.ReplicaSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -486,3 +549,56 @@ func (s *TestSuite) createReplicaSet(name string) error {
 	}, metav1.CreateOptions{})
 	return err
 }
+
+func (s *TestSuite) TestScaleReplicaSet() {
+	s.Require().NoError(s.createReplicaSet("scaled-rs"))
+
+	rs, err := s.client.ScaleReplicaSet(context.Background(), "scaled-rs", 3)
+	s.Require().NoError(err)
+	s.Assert().Equal(int32(3), *rs.Spec.Replicas)
+
+	_, err = s.client.ScaleReplicaSet(context.Background(), "scaled-rs", -1)
+	s.Assert().ErrorIs(err, k8s.ErrReplicaSetReplicasNegative)
+
+	_, err = s.client.ScaleReplicaSet(context.Background(), "missing-rs", 1)
+	s.Assert().ErrorIs(err, k8s.ErrGettingReplicaSet)
+}
+
+func (s *TestSuite) TestListPodsFromReplicaSet() {
+	labels := map[string]string{"app": "replicas"}
+	_, err := s.client.Clientset().AppsV1().ReplicaSets(s.namespace).Create(context.Background(), &appv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "replicas", Namespace: s.namespace, Labels: labels},
+		Spec:       appv1.ReplicaSetSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+	}, metav1.CreateOptions{})
+	s.Require().NoError(err)
+
+	now := time.Now()
+	for name, created := range map[string]time.Time{
+		"replicas-b": now,
+		"replicas-c": now.Add(-time.Minute),
+		"replicas-a": now,
+		"other":      now,
+	} {
+		podLabels := labels
+		if name == "other" {
+			podLabels = map[string]string{"app": "other"}
+		}
+		_, err := s.client.Clientset().CoreV1().Pods(s.namespace).Create(context.Background(), &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         s.namespace,
+				Labels:            podLabels,
+				CreationTimestamp: metav1.NewTime(created),
+			},
+		}, metav1.CreateOptions{})
+		s.Require().NoError(err)
+	}
+
+	pods, err := s.client.ListPodsFromReplicaSet(context.Background(), "replicas")
+	s.Require().NoError(err)
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	s.Assert().Equal([]string{"replicas-c", "replicas-a", "replicas-b"}, names)
+}
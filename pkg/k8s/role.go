@@ -44,6 +44,22 @@ func (c *Client) DeleteRole(ctx context.Context, name string) error {
 	return c.clientset.RbacV1().Roles(c.namespace).Delete(ctx, name, metav1.DeleteOptions{})
 }
 
+// GetRole returns the role with the given name in the namespace.
+// It returns ErrRoleDoesNotExist if there is no such role.
+func (c *Client) GetRole(ctx context.Context, name string) (*rbacv1.Role, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+	role, err := c.clientset.RbacV1().Roles(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, ErrRoleDoesNotExist.WithParams(name).Wrap(err)
+	}
+	if err != nil {
+		return nil, ErrGettingRole.WithParams(name).Wrap(err)
+	}
+	return role, nil
+}
+
 func (c *Client) CreateClusterRole(
 	ctx context.Context,
 	name string,
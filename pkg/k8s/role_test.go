@@ -121,6 +121,62 @@ func (s *TestSuite) TestDeleteRole() {
 	}
 }
 
+func (s *TestSuite) TestGetRole() {
+	tests := []struct {
+		name        string
+		roleName    string
+		setupMock   func()
+		expectedErr error
+	}{
+		{
+			name:        "role does not exist",
+			roleName:    "missing-role",
+			setupMock:   func() {},
+			expectedErr: k8s.ErrRoleDoesNotExist,
+		},
+		{
+			name:     "successful retrieval",
+			roleName: "test-role",
+			setupMock: func() {
+				s.client.Clientset().(*fake.Clientset).
+					PrependReactor("get", "roles",
+						func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+							return true, &rbacv1.Role{}, nil
+						})
+			},
+			expectedErr: nil,
+		},
+		{
+			name:     "client error",
+			roleName: "error-role",
+			setupMock: func() {
+				s.client.Clientset().(*fake.Clientset).
+					PrependReactor("get", "roles",
+						func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+							return true, nil, errInternalServerError
+						})
+			},
+			expectedErr: k8s.ErrGettingRole,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.setupMock()
+
+			role, err := s.client.GetRole(context.Background(), tt.roleName)
+			if tt.expectedErr != nil {
+				s.Require().Error(err)
+				s.Assert().ErrorIs(err, tt.expectedErr)
+				return
+			}
+
+			s.Require().NoError(err)
+			s.Assert().NotNil(role)
+		})
+	}
+}
+
 func (s *TestSuite) TestCreateClusterRole() {
 	tests := []struct {
 		name        string
@@ -62,6 +62,41 @@ func (c *Client) CreateClusterRoleBinding(
 	name string,
 	labels map[string]string,
 	clusterRole, serviceAccount string,
+) error {
+	return c.createClusterRoleBinding(ctx, name, labels, clusterRole, serviceAccount, nil)
+}
+
+// CreateClusterRoleBindingOwnedByRole creates a cluster role binding that is owned by the cluster role it binds,
+// so it is removed by the garbage collector once the cluster role is deleted.
+// This lets a service account remove both with the permission to delete the cluster role only.
+func (c *Client) CreateClusterRoleBindingOwnedByRole(
+	ctx context.Context,
+	name string,
+	labels map[string]string,
+	clusterRole, serviceAccount string,
+) error {
+	if c.terminated {
+		return ErrClientTerminated
+	}
+	role, err := c.clientset.RbacV1().ClusterRoles().Get(ctx, clusterRole, metav1.GetOptions{})
+	if err != nil {
+		return ErrGettingClusterRole.WithParams(clusterRole).Wrap(err)
+	}
+	owner := metav1.OwnerReference{
+		APIVersion: rbacv1.SchemeGroupVersion.String(),
+		Kind:       "ClusterRole",
+		Name:       role.Name,
+		UID:        role.UID,
+	}
+	return c.createClusterRoleBinding(ctx, name, labels, clusterRole, serviceAccount, []metav1.OwnerReference{owner})
+}
+
+func (c *Client) createClusterRoleBinding(
+	ctx context.Context,
+	name string,
+	labels map[string]string,
+	clusterRole, serviceAccount string,
+	owners []metav1.OwnerReference,
 ) error {
 	if c.terminated {
 		return ErrClientTerminated
@@ -86,8 +121,9 @@ func (c *Client) CreateClusterRoleBinding(
 
 	role := &rbacv1.ClusterRoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:   name,
-			Labels: labels,
+			Name:            name,
+			Labels:          labels,
+			OwnerReferences: owners,
 		},
 		RoleRef: rbacv1.RoleRef{
 			Kind:     "ClusterRole",
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
 	k8stesting "k8s.io/client-go/testing"
@@ -179,6 +180,28 @@ func (s *TestSuite) TestCreateClusterRoleBinding() {
 	}
 }
 
+func (s *TestSuite) TestCreateClusterRoleBindingOwnedByRole() {
+	ctx := context.Background()
+
+	err := s.client.CreateClusterRoleBindingOwnedByRole(ctx, "missing-role-binding", nil, "missing-role", "test-sa")
+	s.Require().ErrorIs(err, k8s.ErrGettingClusterRole)
+
+	s.Require().NoError(s.client.CreateClusterRole(ctx, "owner-role", nil, []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}},
+	}))
+	role, err := s.client.Clientset().RbacV1().ClusterRoles().Get(ctx, "owner-role", metav1.GetOptions{})
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.client.CreateClusterRoleBindingOwnedByRole(ctx, "owned-binding", nil, "owner-role", "test-sa"))
+	binding, err := s.client.Clientset().RbacV1().ClusterRoleBindings().Get(ctx, "owned-binding", metav1.GetOptions{})
+	s.Require().NoError(err)
+	s.Require().Len(binding.OwnerReferences, 1)
+	s.Assert().Equal("ClusterRole", binding.OwnerReferences[0].Kind)
+	s.Assert().Equal("owner-role", binding.OwnerReferences[0].Name)
+	s.Assert().Equal(role.UID, binding.OwnerReferences[0].UID)
+	s.Assert().Equal("owner-role", binding.RoleRef.Name)
+}
+
 func (s *TestSuite) TestDeleteClusterRoleBinding() {
 	tests := []struct {
 		name        string
@@ -0,0 +1,71 @@
+package k8s
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetSecret returns the secret with the given name
+func (c *Client) GetSecret(ctx context.Context, name string) (*v1.Secret, error) {
+	secret, err := c.clientset.CoreV1().Secrets(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, ErrGettingSecret.WithParams(name).Wrap(err)
+	}
+	return secret, nil
+}
+
+// CreateOrUpdateSecret creates the secret with the given data, or replaces the labels and the data of the existing one
+func (c *Client) CreateOrUpdateSecret(
+	ctx context.Context, name string,
+	labels map[string]string, data map[string][]byte,
+) (*v1.Secret, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+
+	if err := validateSecret(name, labels, data); err != nil {
+		return nil, err
+	}
+
+	secret := prepareSecret(c.namespace, name, labels, data)
+	updated, err := c.clientset.CoreV1().Secrets(c.namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	if err == nil {
+		return updated, nil
+	}
+	if !apierrs.IsNotFound(err) {
+		return nil, ErrUpdatingSecret.WithParams(name).Wrap(err)
+	}
+
+	created, err := c.clientset.CoreV1().Secrets(c.namespace).Create(ctx, secret, metav1.CreateOptions{})
+	if err != nil {
+		return nil, ErrCreatingSecret.WithParams(name).Wrap(err)
+	}
+	return created, nil
+}
+
+// DeleteSecret deletes the secret, it does not fail if the secret does not exist
+func (c *Client) DeleteSecret(ctx context.Context, name string) error {
+	err := c.clientset.CoreV1().Secrets(c.namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrs.IsNotFound(err) {
+		return ErrDeletingSecret.WithParams(name).Wrap(err)
+	}
+	return nil
+}
+
+func prepareSecret(
+	namespace, name string,
+	labels map[string]string, data map[string][]byte,
+) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Type: v1.SecretTypeOpaque,
+		Data: data,
+	}
+}
@@ -0,0 +1,89 @@
+package k8s_test
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+)
+
+func (s *TestSuite) TestCreateOrUpdateSecret() {
+	tests := []struct {
+		name        string
+		secretName  string
+		data        map[string][]byte
+		setupMock   func()
+		expectedErr error
+	}{
+		{
+			name:       "successful creation",
+			secretName: "new-secret",
+			data:       map[string][]byte{"key": {0, 255}},
+			setupMock:  func() {},
+		},
+		{
+			name:       "successful update",
+			secretName: "existing-secret",
+			data:       map[string][]byte{"key": []byte("updated")},
+			setupMock: func() {
+				_, err := s.client.CreateOrUpdateSecret(context.Background(), "existing-secret", nil, map[string][]byte{"key": []byte("old")})
+				s.Require().NoError(err)
+			},
+		},
+		{
+			name:        "invalid key",
+			secretName:  "invalid-secret",
+			data:        map[string][]byte{"invalid/key": []byte("value")},
+			setupMock:   func() {},
+			expectedErr: k8s.ErrInvalidSecretKey,
+		},
+		{
+			name:       "client error",
+			secretName: "error-secret",
+			data:       map[string][]byte{"key": []byte("value")},
+			setupMock: func() {
+				s.client.Clientset().(*fake.Clientset).
+					PrependReactor("update", "secrets",
+						func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+							return true, nil, errInternalServerError
+						})
+			},
+			expectedErr: k8s.ErrUpdatingSecret.WithParams("error-secret").Wrap(errInternalServerError),
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.setupMock()
+
+			_, err := s.client.CreateOrUpdateSecret(context.Background(), tt.secretName, map[string]string{"app": "test"}, tt.data)
+			if tt.expectedErr != nil {
+				s.Require().Error(err)
+				s.Assert().ErrorIs(err, tt.expectedErr)
+				return
+			}
+
+			s.Require().NoError(err)
+			secret, err := s.client.GetSecret(context.Background(), tt.secretName)
+			s.Require().NoError(err)
+			s.Assert().Equal(tt.data, secret.Data)
+			s.Assert().Equal(map[string]string{"app": "test"}, secret.Labels)
+		})
+	}
+}
+
+func (s *TestSuite) TestDeleteSecret() {
+	ctx := context.Background()
+	_, err := s.client.CreateOrUpdateSecret(ctx, "deleted-secret", nil, map[string][]byte{"key": []byte("value")})
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.client.DeleteSecret(ctx, "deleted-secret"))
+	_, err = s.client.Clientset().CoreV1().Secrets(s.namespace).Get(ctx, "deleted-secret", metav1.GetOptions{})
+	s.Assert().Error(err)
+	// a missing secret is not an error
+	s.Assert().NoError(s.client.DeleteSecret(ctx, "deleted-secret"))
+}
@@ -20,6 +20,19 @@ func (c *Client) GetService(ctx context.Context, name string) (*v1.Service, erro
 	return c.clientset.CoreV1().Services(c.namespace).Get(ctx, name, metav1.GetOptions{})
 }
 
+// ListServices returns the services in the namespace that match the given labels.
+func (c *Client) ListServices(ctx context.Context, labels map[string]string) ([]v1.Service, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+	selector := metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: labels})
+	svcList, err := c.clientset.CoreV1().Services(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, ErrListingServices.Wrap(err)
+	}
+	return svcList.Items, nil
+}
+
 func (c *Client) CreateService(
 	ctx context.Context,
 	name string,
@@ -59,6 +72,45 @@ func (c *Client) CreateService(
 	return serv, nil
 }
 
+// CreateHeadlessService creates a service without a cluster IP, that gives a DNS name to each pod it selects.
+// The pods can be resolved before they are ready, so the replicas of a StatefulSet can find each other while starting.
+func (c *Client) CreateHeadlessService(ctx context.Context, name string, labels, selectorMap map[string]string) (*v1.Service, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+	if err := validateServiceName(name); err != nil {
+		return nil, err
+	}
+	if err := validateLabels(labels); err != nil {
+		return nil, err
+	}
+	if err := validateSelectorMap(selectorMap); err != nil {
+		return nil, err
+	}
+
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: c.namespace,
+			Name:      name,
+			Labels:    labels,
+		},
+		Spec: v1.ServiceSpec{
+			ClusterIP:                v1.ClusterIPNone,
+			Selector:                 selectorMap,
+			PublishNotReadyAddresses: true,
+		},
+	}
+	serv, err := c.clientset.CoreV1().Services(c.namespace).Create(ctx, svc, metav1.CreateOptions{})
+	if err != nil {
+		return nil, ErrCreatingHeadlessService.WithParams(name).Wrap(err)
+	}
+	c.logger.WithFields(logrus.Fields{
+		"name":      name,
+		"namespace": c.namespace,
+	}).Debug("headless service created")
+	return serv, nil
+}
+
 func (c *Client) PatchService(
 	ctx context.Context,
 	name string,
@@ -550,6 +550,67 @@ func (s *TestSuite) TestGetServiceEndpoint() {
 	}
 }
 
+func (s *TestSuite) TestListServices() {
+	labels := map[string]string{"knuu.sh/scope": "test"}
+	tests := []struct {
+		name          string
+		setupMock     func()
+		expectedNames []string
+		expectedErr   error
+	}{
+		{
+			name: "only matching services are listed",
+			setupMock: func() {
+				for name, l := range map[string]map[string]string{
+					"match-svc":    labels,
+					"no-match-svc": {"knuu.sh/scope": "other"},
+				} {
+					_, err := s.client.Clientset().CoreV1().Services(s.namespace).Create(context.Background(), &v1.Service{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      name,
+							Namespace: s.namespace,
+							Labels:    l,
+						},
+					}, metav1.CreateOptions{})
+					s.Require().NoError(err)
+				}
+			},
+			expectedNames: []string{"match-svc"},
+		},
+		{
+			name: "client error",
+			setupMock: func() {
+				s.client.Clientset().(*fake.Clientset).
+					PrependReactor("list", "services",
+						func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+							return true, nil, errInternalServerError
+						})
+			},
+			expectedErr: k8s.ErrListingServices.Wrap(errInternalServerError),
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.setupMock()
+
+			svcList, err := s.client.ListServices(context.Background(), labels)
+			if tt.expectedErr != nil {
+				s.Require().Error(err)
+				s.Assert().ErrorIs(err, tt.expectedErr)
+				return
+			}
+
+			s.Require().NoError(err)
+			names := make([]string, 0, len(svcList))
+			for _, svc := range svcList {
+				names = append(names, svc.Name)
+			}
+			s.Assert().Equal(tt.expectedNames, names)
+		})
+	}
+}
+
 func (s *TestSuite) createService(name string) error {
 	_, err := s.client.Clientset().CoreV1().Services(s.namespace).Create(context.Background(), &v1.Service{
 		ObjectMeta: metav1.ObjectMeta{
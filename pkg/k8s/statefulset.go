@@ -0,0 +1,254 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	appv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+type StatefulSetConfig struct {
+	Name        string            // Name of the StatefulSet
+	Namespace   string            // Namespace of the StatefulSet
+	Labels      map[string]string // Labels to apply to the StatefulSet, key/value represents the name/value of the label
+	Replicas    int32             // Replicas is the number of replicas
+	ServiceName string            // ServiceName is the headless service that gives the pods their DNS names
+	PodConfig   PodConfig         // PodConfig represents the pod configuration
+}
+
+// CreateStatefulSet creates a new StatefulSet in the namespace that k8s is initialized with.
+// The pods are named after the StatefulSet with their ordinal, e.g. name-0, name-1.
+// Each replica gets its own PersistentVolumeClaim for each volume of each container,
+// they are kept when the StatefulSet is deleted or scaled down, so a new StatefulSet with the same name reuses them.
+func (c *Client) CreateStatefulSet(ctx context.Context, ssConfig StatefulSetConfig, init bool) (*appv1.StatefulSet, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+	ssConfig.Namespace = c.namespace
+	if err := validateStatefulSetConfig(ssConfig); err != nil {
+		return nil, err
+	}
+	ss := c.prepareStatefulSet(ssConfig, init)
+
+	created, err := c.clientset.AppsV1().StatefulSets(c.namespace).Create(ctx, ss, metav1.CreateOptions{})
+	if err != nil {
+		return nil, ErrCreatingStatefulSet.WithParams(ssConfig.Name).Wrap(err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"name":      ssConfig.Name,
+		"namespace": c.namespace,
+	}).Debug("statefulSet created")
+	return created, nil
+}
+
+func (c *Client) IsStatefulSetRunning(ctx context.Context, name string) (bool, error) {
+	ss, err := c.getStatefulSet(ctx, name)
+	if err != nil {
+		return false, ErrGettingStatefulSet.WithParams(name).Wrap(err)
+	}
+	return ss.Status.ReadyReplicas == *ss.Spec.Replicas, nil
+}
+
+// StatefulSetExists checks if a StatefulSet exists in the namespace that k8s is initialized with.
+func (c *Client) StatefulSetExists(ctx context.Context, name string) (bool, error) {
+	_, err := c.getStatefulSet(ctx, name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, ErrGettingStatefulSet.WithParams(name).Wrap(err)
+	}
+	return true, nil
+}
+
+// DeleteStatefulSetWithGracePeriod deletes the StatefulSet, its PersistentVolumeClaims are kept.
+func (c *Client) DeleteStatefulSetWithGracePeriod(ctx context.Context, name string, gracePeriodSeconds *int64) error {
+	exists, err := c.StatefulSetExists(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	if gracePeriodSeconds == nil {
+		gracePeriodSeconds = ptr.To[int64](0)
+	}
+
+	delOpts := metav1.DeleteOptions{
+		GracePeriodSeconds: gracePeriodSeconds,
+	}
+	if err := c.clientset.AppsV1().StatefulSets(c.namespace).Delete(ctx, name, delOpts); err != nil {
+		return ErrDeletingStatefulSet.WithParams(name).Wrap(err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"name":      name,
+		"namespace": c.namespace,
+	}).Debug("statefulSet deleted")
+	return nil
+}
+
+func (c *Client) DeleteStatefulSet(ctx context.Context, name string) error {
+	return c.DeleteStatefulSetWithGracePeriod(ctx, name, nil)
+}
+
+// ListStatefulSets returns the StatefulSets in the namespace that match the given labels.
+func (c *Client) ListStatefulSets(ctx context.Context, labels map[string]string) ([]appv1.StatefulSet, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+	selector := metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: labels})
+	ssList, err := c.clientset.AppsV1().StatefulSets(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, ErrListingStatefulSets.Wrap(err)
+	}
+	return ssList.Items, nil
+}
+
+// ListPodsFromStatefulSet returns the pods of the StatefulSet, sorted by their ordinal.
+func (c *Client) ListPodsFromStatefulSet(ctx context.Context, name string) ([]v1.Pod, error) {
+	ss, err := c.getStatefulSet(ctx, name)
+	if err != nil {
+		return nil, ErrGettingStatefulSet.WithParams(name).Wrap(err)
+	}
+	selector := metav1.FormatLabelSelector(ss.Spec.Selector)
+	pods, err := c.clientset.CoreV1().Pods(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, ErrListingPodsForStatefulSet.WithParams(name).Wrap(err)
+	}
+
+	sort.Slice(pods.Items, func(a, b int) bool {
+		return podOrdinal(name, pods.Items[a].Name) < podOrdinal(name, pods.Items[b].Name)
+	})
+	return pods.Items, nil
+}
+
+// podOrdinal returns the ordinal of a pod of the StatefulSet with the given name, -1 if it has none
+func podOrdinal(statefulSetName, podName string) int {
+	n, err := strconv.Atoi(strings.TrimPrefix(podName, statefulSetName+"-"))
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// ScaleStatefulSet sets the number of replicas of the StatefulSet.
+// The replicas with the highest ordinals are removed first, their PersistentVolumeClaims are kept.
+func (c *Client) ScaleStatefulSet(ctx context.Context, name string, replicas int32) (*appv1.StatefulSet, error) {
+	if replicas < 0 {
+		return nil, ErrStatefulSetReplicasNegative.WithParams(replicas)
+	}
+	ss, err := c.getStatefulSet(ctx, name)
+	if err != nil {
+		return nil, ErrGettingStatefulSet.WithParams(name).Wrap(err)
+	}
+
+	ss.Spec.Replicas = &replicas
+	ss, err = c.clientset.AppsV1().StatefulSets(c.namespace).Update(ctx, ss, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, ErrScalingStatefulSet.WithParams(name).Wrap(err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"name":      name,
+		"namespace": c.namespace,
+		"replicas":  replicas,
+	}).Debug("scaled statefulSet")
+	return ss, nil
+}
+
+func (c *Client) getStatefulSet(ctx context.Context, name string) (*appv1.StatefulSet, error) {
+	if c.terminated {
+		return nil, ErrClientTerminated
+	}
+	return c.clientset.AppsV1().StatefulSets(c.namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c *Client) prepareStatefulSet(ssConf StatefulSetConfig, init bool) *appv1.StatefulSet {
+	podSpec := c.preparePodSpec(ssConf.PodConfig, init)
+	// the claims are created per replica from the templates, which have the same names as the pod volumes they replace
+	volumes := make([]v1.Volume, 0, len(podSpec.Volumes))
+	for _, volume := range podSpec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			volumes = append(volumes, volume)
+		}
+	}
+	podSpec.Volumes = volumes
+
+	ss := &appv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ssConf.Namespace,
+			Name:      ssConf.Name,
+			Labels:    ssConf.Labels,
+		},
+		Spec: appv1.StatefulSetSpec{
+			Replicas:    &ssConf.Replicas,
+			ServiceName: ssConf.ServiceName,
+			Selector:    &metav1.LabelSelector{MatchLabels: ssConf.Labels},
+			// the replicas are started together, as they usually need each other to become ready
+			PodManagementPolicy: appv1.ParallelPodManagement,
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   ssConf.Namespace,
+					Name:        ssConf.Name,
+					Labels:      ssConf.Labels,
+					Annotations: ssConf.PodConfig.Annotations,
+				},
+				Spec: podSpec,
+			},
+			VolumeClaimTemplates: prepareVolumeClaimTemplates(ssConf),
+			PersistentVolumeClaimRetentionPolicy: &appv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+				WhenDeleted: appv1.RetainPersistentVolumeClaimRetentionPolicyType,
+				WhenScaled:  appv1.RetainPersistentVolumeClaimRetentionPolicyType,
+			},
+		},
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"name":      ssConf.Name,
+		"namespace": ssConf.Namespace,
+	}).Debug("prepared statefulSet")
+	return ss
+}
+
+// prepareVolumeClaimTemplates returns a claim template for each volume of each container.
+// The claims are labeled like the StatefulSet, so they can be found once it is gone.
+func prepareVolumeClaimTemplates(ssConf StatefulSetConfig) []v1.PersistentVolumeClaim {
+	configs := append([]ContainerConfig{ssConf.PodConfig.ContainerConfig}, ssConf.PodConfig.SidecarConfigs...)
+	var templates []v1.PersistentVolumeClaim
+	for _, config := range configs {
+		for _, volume := range config.Volumes {
+			templates = append(templates, v1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   VolumeClaimName(config.Name, volume.Name),
+					Labels: ssConf.Labels,
+				},
+				Spec: v1.PersistentVolumeClaimSpec{
+					AccessModes:      volume.accessModes(),
+					StorageClassName: volume.storageClassName(),
+					Resources: v1.VolumeResourceRequirements{
+						Requests: v1.ResourceList{
+							v1.ResourceStorage: volume.Size,
+						},
+					},
+				},
+			})
+		}
+	}
+	return templates
+}
+
+// StatefulSetClaimName returns the name of the PersistentVolumeClaim created for the given replica
+// of the StatefulSet from the template with the given name.
+func StatefulSetClaimName(template, statefulSetName string, replica int) string {
+	return fmt.Sprintf("%s-%s-%d", template, statefulSetName, replica)
+}
@@ -0,0 +1,128 @@
+package k8s_test
+
+import (
+	"context"
+
+	appv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+)
+
+func (s *TestSuite) TestCreateStatefulSet() {
+	ssConfig := k8s.StatefulSetConfig{
+		Name:        "validator",
+		Labels:      map[string]string{"app": "validator"},
+		Replicas:    2,
+		ServiceName: "validator-headless",
+		PodConfig: k8s.PodConfig{
+			Namespace: s.namespace,
+			Name:      "validator",
+			Labels:    map[string]string{"app": "validator"},
+			ContainerConfig: k8s.ContainerConfig{
+				Name:  "validator",
+				Image: "alpine:latest",
+				Volumes: []*k8s.Volume{
+					{Name: "data", Path: "/data", Size: resource.MustParse("1Gi")},
+					{Name: "keys", Path: "/keys", Size: resource.MustParse("1Mi"), StorageClass: "fast"},
+				},
+			},
+			SidecarConfigs: []k8s.ContainerConfig{{Name: "sidecar", Image: "alpine:latest"}},
+		},
+	}
+
+	ss, err := s.client.CreateStatefulSet(context.Background(), ssConfig, true)
+	s.Require().NoError(err)
+	s.Assert().Equal(s.namespace, ss.Namespace)
+	s.Assert().Equal("validator-headless", ss.Spec.ServiceName)
+	s.Assert().Equal(int32(2), *ss.Spec.Replicas)
+	s.Assert().Equal(appv1.RetainPersistentVolumeClaimRetentionPolicyType, ss.Spec.PersistentVolumeClaimRetentionPolicy.WhenDeleted)
+
+	// a claim is made for each volume of the container, and replaces the claim of the pod
+	s.Require().Len(ss.Spec.VolumeClaimTemplates, 2)
+	data, keys := ss.Spec.VolumeClaimTemplates[0], ss.Spec.VolumeClaimTemplates[1]
+	s.Assert().Equal("validator-data", data.Name)
+	s.Assert().Equal("validator-keys", keys.Name)
+	expectedSize := resource.MustParse("1Mi")
+	s.Assert().True(expectedSize.Equal(keys.Spec.Resources.Requests[v1.ResourceStorage]))
+	s.Assert().Nil(data.Spec.StorageClassName)
+	s.Require().NotNil(keys.Spec.StorageClassName)
+	s.Assert().Equal("fast", *keys.Spec.StorageClassName)
+	for _, volume := range ss.Spec.Template.Spec.Volumes {
+		s.Assert().Nil(volume.PersistentVolumeClaim)
+	}
+
+	_, err = s.client.CreateStatefulSet(context.Background(), ssConfig, true)
+	s.Assert().ErrorIs(err, k8s.ErrCreatingStatefulSet)
+
+	ssConfig.ServiceName = ""
+	_, err = s.client.CreateStatefulSet(context.Background(), ssConfig, true)
+	s.Assert().ErrorIs(err, k8s.ErrStatefulSetServiceNameRequired)
+
+	s.Assert().Equal("validator-validator-1", k8s.StatefulSetClaimName("validator", "validator", 1))
+}
+
+func (s *TestSuite) TestDeleteStatefulSet() {
+	s.Require().NoError(s.createStatefulSet("deleted-ss", nil))
+
+	s.Require().NoError(s.client.DeleteStatefulSet(context.Background(), "deleted-ss"))
+	exists, err := s.client.StatefulSetExists(context.Background(), "deleted-ss")
+	s.Require().NoError(err)
+	s.Assert().False(exists)
+
+	// deleting a missing StatefulSet is not an error
+	s.Assert().NoError(s.client.DeleteStatefulSet(context.Background(), "deleted-ss"))
+}
+
+func (s *TestSuite) TestScaleStatefulSet() {
+	s.Require().NoError(s.createStatefulSet("scaled-ss", nil))
+
+	ss, err := s.client.ScaleStatefulSet(context.Background(), "scaled-ss", 3)
+	s.Require().NoError(err)
+	s.Assert().Equal(int32(3), *ss.Spec.Replicas)
+
+	_, err = s.client.ScaleStatefulSet(context.Background(), "scaled-ss", -1)
+	s.Assert().ErrorIs(err, k8s.ErrStatefulSetReplicasNegative)
+
+	_, err = s.client.ScaleStatefulSet(context.Background(), "missing-ss", 1)
+	s.Assert().ErrorIs(err, k8s.ErrGettingStatefulSet)
+}
+
+func (s *TestSuite) TestListPodsFromStatefulSet() {
+	labels := map[string]string{"app": "ordinals"}
+	s.Require().NoError(s.createStatefulSet("ordinals", labels))
+
+	for _, name := range []string{"ordinals-10", "ordinals-2", "ordinals-0"} {
+		_, err := s.client.Clientset().CoreV1().Pods(s.namespace).Create(context.Background(), &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: s.namespace, Labels: labels},
+		}, metav1.CreateOptions{})
+		s.Require().NoError(err)
+	}
+
+	pods, err := s.client.ListPodsFromStatefulSet(context.Background(), "ordinals")
+	s.Require().NoError(err)
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	s.Assert().Equal([]string{"ordinals-0", "ordinals-2", "ordinals-10"}, names)
+}
+
+func (s *TestSuite) TestCreateHeadlessService() {
+	labels := map[string]string{"app": "validator"}
+	svc, err := s.client.CreateHeadlessService(context.Background(), "validator-headless", labels, labels)
+	s.Require().NoError(err)
+	s.Assert().Equal(v1.ClusterIPNone, svc.Spec.ClusterIP)
+	s.Assert().True(svc.Spec.PublishNotReadyAddresses)
+	s.Assert().Equal(labels, svc.Spec.Selector)
+}
+
+func (s *TestSuite) createStatefulSet(name string, labels map[string]string) error {
+	_, err := s.client.Clientset().AppsV1().StatefulSets(s.namespace).Create(context.Background(), &appv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: s.namespace, Labels: labels},
+		Spec:       appv1.StatefulSetSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+	}, metav1.CreateOptions{})
+	return err
+}
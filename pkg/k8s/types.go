@@ -3,8 +3,11 @@ package k8s
 import (
 	"context"
 	"io"
+	"time"
 
 	appv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -19,22 +22,31 @@ type KubeManager interface {
 	Clientset() kubernetes.Interface
 	CreateClusterRole(ctx context.Context, name string, labels map[string]string, policyRules []rbacv1.PolicyRule) error
 	CreateClusterRoleBinding(ctx context.Context, name string, labels map[string]string, clusterRole, serviceAccount string) error
+	CreateClusterRoleBindingOwnedByRole(ctx context.Context, name string, labels map[string]string, clusterRole, serviceAccount string) error
 	CreateConfigMap(ctx context.Context, name string, labels, data map[string]string) (*corev1.ConfigMap, error)
 	CreateOrUpdateConfigMap(ctx context.Context, name string, labels, data map[string]string) (*corev1.ConfigMap, error)
+	CreateOrUpdateConfigMapWithBinaryData(ctx context.Context, name string, labels, data map[string]string, binaryData map[string][]byte) (*corev1.ConfigMap, error)
+	CreateOrUpdateSecret(ctx context.Context, name string, labels map[string]string, data map[string][]byte) (*corev1.Secret, error)
 	CreateCustomResource(ctx context.Context, name string, gvr *schema.GroupVersionResource, obj *map[string]interface{}) error
+	CreateHeadlessService(ctx context.Context, name string, labels, selectorMap map[string]string) (*corev1.Service, error)
+	CreateJob(ctx context.Context, jobConfig JobConfig, init bool) (*batchv1.Job, error)
 	CreateDaemonSet(ctx context.Context, name string, labels map[string]string, initContainers []corev1.Container, containers []corev1.Container) (*appv1.DaemonSet, error)
+	CreateLease(ctx context.Context, name string, labels map[string]string, duration time.Duration) (*coordinationv1.Lease, error)
 	CreateNamespace(ctx context.Context, name string) error
 	CreateNetworkPolicy(ctx context.Context, name string, selectorMap, ingressSelectorMap, egressSelectorMap map[string]string) error
-	CreatePersistentVolumeClaim(ctx context.Context, name string, labels map[string]string, size resource.Quantity) error
+	CreatePersistentVolumeClaim(ctx context.Context, name string, labels map[string]string, size resource.Quantity, storageClass string, accessMode corev1.PersistentVolumeAccessMode) error
 	CreateReplicaSet(ctx context.Context, rsConfig ReplicaSetConfig, init bool) (*appv1.ReplicaSet, error)
 	CreateRole(ctx context.Context, name string, labels map[string]string, policyRules []rbacv1.PolicyRule) error
 	CreateRoleBinding(ctx context.Context, name string, labels map[string]string, role, serviceAccount string) error
 	CreateService(ctx context.Context, name string, labels, selectorMap map[string]string, portsTCP, portsUDP []int) (*corev1.Service, error)
 	CreateServiceAccount(ctx context.Context, name string, labels map[string]string) error
+	CreateStatefulSet(ctx context.Context, ssConfig StatefulSetConfig, init bool) (*appv1.StatefulSet, error)
 	CustomResourceDefinitionExists(ctx context.Context, gvr *schema.GroupVersionResource) (bool, error)
 	DaemonSetExists(ctx context.Context, name string) (bool, error)
+	DeleteClusterRole(ctx context.Context, name string) error
 	DeleteConfigMap(ctx context.Context, name string) error
 	DeleteDaemonSet(ctx context.Context, name string) error
+	DeleteJob(ctx context.Context, name string) error
 	DeleteNamespace(ctx context.Context, name string) error
 	DeleteNetworkPolicy(ctx context.Context, name string) error
 	DeletePersistentVolumeClaim(ctx context.Context, name string) error
@@ -44,22 +56,48 @@ type KubeManager interface {
 	DeleteReplicaSetWithGracePeriod(ctx context.Context, name string, gracePeriodSeconds *int64) error
 	DeleteRole(ctx context.Context, name string) error
 	DeleteRoleBinding(ctx context.Context, name string) error
+	DeleteSecret(ctx context.Context, name string) error
 	DeleteService(ctx context.Context, name string) error
 	DeleteServiceAccount(ctx context.Context, name string) error
+	DeleteStatefulSet(ctx context.Context, name string) error
+	DeleteStatefulSetWithGracePeriod(ctx context.Context, name string, gracePeriodSeconds *int64) error
+	DeployDaemonSet(ctx context.Context, dsConfig DaemonSetConfig, init bool) (*appv1.DaemonSet, error)
 	DeployPod(ctx context.Context, podConfig PodConfig, init bool) (*corev1.Pod, error)
 	DiscoveryClient() discovery.DiscoveryInterface
 	DynamicClient() dynamic.Interface
 	GetConfigMap(ctx context.Context, name string) (*corev1.ConfigMap, error)
 	GetDaemonSet(ctx context.Context, name string) (*appv1.DaemonSet, error)
 	GetFirstPodFromReplicaSet(ctx context.Context, name string) (*corev1.Pod, error)
+	GetJob(ctx context.Context, name string) (*batchv1.Job, error)
+	GetLease(ctx context.Context, name string) (*coordinationv1.Lease, error)
 	GetLogStream(ctx context.Context, podName string, containerName string) (io.ReadCloser, error)
+	GetPodLogStream(ctx context.Context, podName string, logOptions corev1.PodLogOptions) (io.ReadCloser, error)
 	GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error)
 	GetNetworkPolicy(ctx context.Context, name string) (*netv1.NetworkPolicy, error)
+	GetRole(ctx context.Context, name string) (*rbacv1.Role, error)
+	GetSecret(ctx context.Context, name string) (*corev1.Secret, error)
 	GetService(ctx context.Context, name string) (*corev1.Service, error)
 	GetServiceEndpoint(ctx context.Context, name string) (string, error)
 	GetServiceIP(ctx context.Context, name string) (string, error)
+	IsDaemonSetRunning(ctx context.Context, name string) (bool, error)
 	IsPodRunning(ctx context.Context, name string) (bool, error)
+	IsJobRunning(ctx context.Context, name string) (bool, error)
 	IsReplicaSetRunning(ctx context.Context, name string) (bool, error)
+	IsStatefulSetRunning(ctx context.Context, name string) (bool, error)
+	ListConfigMaps(ctx context.Context, labels map[string]string) ([]corev1.ConfigMap, error)
+	ListDaemonSets(ctx context.Context, labels map[string]string) ([]appv1.DaemonSet, error)
+	ListJobs(ctx context.Context, labels map[string]string) ([]batchv1.Job, error)
+	ListEvents(ctx context.Context) ([]corev1.Event, error)
+	ListNetworkPolicies(ctx context.Context) ([]netv1.NetworkPolicy, error)
+	ListPersistentVolumeClaims(ctx context.Context, labels map[string]string) ([]corev1.PersistentVolumeClaim, error)
+	ListPods(ctx context.Context, labels map[string]string) ([]corev1.Pod, error)
+	ListPodsFromDaemonSet(ctx context.Context, name string) ([]corev1.Pod, error)
+	ListPodsFromJob(ctx context.Context, name string) ([]corev1.Pod, error)
+	ListPodsFromReplicaSet(ctx context.Context, name string) ([]corev1.Pod, error)
+	ListPodsFromStatefulSet(ctx context.Context, name string) ([]corev1.Pod, error)
+	ListReplicaSets(ctx context.Context, labels map[string]string) ([]appv1.ReplicaSet, error)
+	ListServices(ctx context.Context, labels map[string]string) ([]corev1.Service, error)
+	ListStatefulSets(ctx context.Context, labels map[string]string) ([]appv1.StatefulSet, error)
 	Namespace() string
 	NamespaceExists(ctx context.Context, name string) (bool, error)
 	NetworkPolicyExists(ctx context.Context, name string) bool
@@ -67,15 +105,22 @@ type KubeManager interface {
 	NewVolume(path string, size resource.Quantity, owner int64) *Volume
 	PatchService(ctx context.Context, name string, labels, selectorMap map[string]string, portsTCP, portsUDP []int) (*corev1.Service, error)
 	PortForwardPod(ctx context.Context, podName string, localPort, remotePort int) error
+	RenewLease(ctx context.Context, name string, duration time.Duration) (*coordinationv1.Lease, error)
 	ReplicaSetExists(ctx context.Context, name string) (bool, error)
 	ReplacePod(ctx context.Context, podConfig PodConfig) (*corev1.Pod, error)
 	ReplacePodWithGracePeriod(ctx context.Context, podConfig PodConfig, gracePeriod *int64) (*corev1.Pod, error)
 	ReplaceReplicaSet(ctx context.Context, ReplicaSetConfig ReplicaSetConfig) (*appv1.ReplicaSet, error)
 	ReplaceReplicaSetWithGracePeriod(ctx context.Context, ReplicaSetConfig ReplicaSetConfig, gracePeriod *int64) (*appv1.ReplicaSet, error)
 	RunCommandInPod(ctx context.Context, podName, containerName string, cmd []string) (string, error)
+	ScaleReplicaSet(ctx context.Context, name string, replicas int32) (*appv1.ReplicaSet, error)
+	ScaleStatefulSet(ctx context.Context, name string, replicas int32) (*appv1.StatefulSet, error)
+	StatefulSetExists(ctx context.Context, name string) (bool, error)
+	StreamCommandInPod(ctx context.Context, podName, containerName string, cmd []string, stdin io.Reader, stdout io.Writer) error
 	ConfigMapExists(ctx context.Context, name string) (bool, error)
 	UpdateDaemonSet(ctx context.Context, name string, labels map[string]string, initContainers []corev1.Container, containers []corev1.Container) (*appv1.DaemonSet, error)
 	UpdateConfigMap(ctx context.Context, name string, labels, data map[string]string) (*corev1.ConfigMap, error)
+	WatchEvents(ctx context.Context) (<-chan Event, error)
+	WaitForJob(ctx context.Context, name string) (*batchv1.Job, error)
 	WaitForDeployment(ctx context.Context, name string) error
 	WaitForService(ctx context.Context, name string) error
 	Terminate()
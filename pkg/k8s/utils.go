@@ -60,3 +60,8 @@ func SanitizeName(name string) string {
 	}
 	return sanitized
 }
+
+// ShellQuote quotes the string so that it is read as a single word by the shell the commands are run with
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
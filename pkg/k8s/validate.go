@@ -1,6 +1,8 @@
 package k8s
 
 import (
+	"path/filepath"
+
 	v1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -53,6 +55,19 @@ func validateConfigMapKeys(data map[string]string) error {
 	return nil
 }
 
+// validateConfigMapBinaryKeys checks the keys of the binary data, which must not be used by the data too
+func validateConfigMapBinaryKeys(data map[string]string, binaryData map[string][]byte) error {
+	for key := range binaryData {
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			return ErrInvalidConfigMapKey.WithParams(key, errs)
+		}
+		if _, ok := data[key]; ok {
+			return ErrInvalidConfigMapKey.WithParams(key, "the key is used by the data and the binary data")
+		}
+	}
+	return nil
+}
+
 func validateCustomResourceName(name string) error {
 	return validateDNS1123Subdomain(name, ErrInvalidCustomResourceName)
 }
@@ -142,11 +157,45 @@ func validateContainerConfig(config ContainerConfig) error {
 		if err := validateFile(file); err != nil {
 			return err
 		}
+		if file.Remote && config.FilesArchiveURL == "" {
+			return ErrFilesArchiveURLEmpty.WithParams(file.Dest)
+		}
+		if file.Symlink != "" && !inVolume(file.Dest, config.Volumes) {
+			return ErrSymlinkNotInVolume.WithParams(file.Dest)
+		}
+	}
+	if err := validateLiveFiles(config.LiveFiles); err != nil {
+		return err
+	}
+	for _, file := range config.SecretFiles {
+		if file.Key == "" || !filepath.IsAbs(file.Dest) {
+			return ErrInvalidSecretFile.WithParams(file.Key, file.Dest)
+		}
 	}
 	return validateContainerName(config.Name)
 }
 
+// validateLiveFiles checks that the live files have a key and an absolute path,
+// and that the live files of a directory are all stored in the ConfigMap or all in the Secret, as they share a volume
+func validateLiveFiles(liveFiles []*LiveFile) error {
+	secretDirs := make(map[string]bool, len(liveFiles))
+	for _, file := range liveFiles {
+		if file.Key == "" || !filepath.IsAbs(file.Dest) {
+			return ErrInvalidLiveFile.WithParams(file.Key, file.Dest)
+		}
+		dir := filepath.Dir(file.Dest)
+		if secret, ok := secretDirs[dir]; ok && secret != file.Secret {
+			return ErrLiveFilesMixedInDirectory.WithParams(dir)
+		}
+		secretDirs[dir] = file.Secret
+	}
+	return nil
+}
+
 func validateVolume(volume *Volume) error {
+	if err := validateDNS1123Label(volume.Name, ErrInvalidVolumeName); err != nil {
+		return err
+	}
 	if volume.Path == "" {
 		return ErrVolumePathEmpty.WithParams(volume.Path)
 	}
@@ -154,6 +203,34 @@ func validateVolume(volume *Volume) error {
 	if volume.Size.Value() <= 0 {
 		return ErrVolumeSizeZero.WithParams(volume.Path)
 	}
+	return validateAccessMode(volume.AccessMode)
+}
+
+// validateAccessMode checks the access mode of a claim, empty being the default one
+func validateAccessMode(accessMode v1.PersistentVolumeAccessMode) error {
+	switch accessMode {
+	case "", v1.ReadWriteOnce, v1.ReadOnlyMany, v1.ReadWriteMany, v1.ReadWriteOncePod:
+		return nil
+	}
+	return ErrInvalidVolumeAccessMode.WithParams(accessMode)
+}
+
+// validateVolumeMounts checks that the volumes mounted in the sidecars are volumes of the main container
+func validateVolumeMounts(podConfig PodConfig) error {
+	volumes := make(map[string]bool, len(podConfig.ContainerConfig.Volumes))
+	for _, volume := range podConfig.ContainerConfig.Volumes {
+		volumes[volume.Name] = true
+	}
+	for _, sidecarConfig := range podConfig.SidecarConfigs {
+		for _, mount := range sidecarConfig.VolumeMounts {
+			if !volumes[mount.Volume] {
+				return ErrVolumeMountNotFound.WithParams(mount.Volume, sidecarConfig.Name, podConfig.ContainerConfig.Name)
+			}
+			if mount.Path == "" {
+				return ErrVolumePathEmpty.WithParams(mount.Path)
+			}
+		}
+	}
 	return nil
 }
 
@@ -190,7 +267,7 @@ func validatePodConfig(podConfig PodConfig) error {
 		}
 	}
 
-	return nil
+	return validateVolumeMounts(podConfig)
 }
 
 func validatePVCName(name string) error {
@@ -227,6 +304,71 @@ func validateReplicaSetConfig(rsConfig ReplicaSetConfig) error {
 	return nil
 }
 
+func validateStatefulSetName(name string) error {
+	return validateDNS1123Label(name, ErrInvalidStatefulSetName)
+}
+
+func validateStatefulSetConfig(ssConfig StatefulSetConfig) error {
+	if err := validateStatefulSetName(ssConfig.Name); err != nil {
+		return err
+	}
+	if err := validateNamespace(ssConfig.Namespace); err != nil {
+		return err
+	}
+	if err := validateLabels(ssConfig.Labels); err != nil {
+		return err
+	}
+	if ssConfig.Replicas < 0 {
+		return ErrStatefulSetReplicasNegative.WithParams(ssConfig.Replicas)
+	}
+	if ssConfig.ServiceName == "" {
+		return ErrStatefulSetServiceNameRequired.WithParams(ssConfig.Name)
+	}
+	if err := validatePodConfig(ssConfig.PodConfig); err != nil {
+		return err
+	}
+	return nil
+}
+
+func validateDaemonSetConfig(dsConfig DaemonSetConfig) error {
+	if err := validateDaemonSetName(dsConfig.Name); err != nil {
+		return err
+	}
+	if err := validateNamespace(dsConfig.Namespace); err != nil {
+		return err
+	}
+	if err := validateLabels(dsConfig.Labels); err != nil {
+		return err
+	}
+	if err := validatePodConfig(dsConfig.PodConfig); err != nil {
+		return err
+	}
+	return nil
+}
+
+func validateJobName(name string) error {
+	return validateDNS1123Label(name, ErrInvalidJobName)
+}
+
+func validateJobConfig(jobConfig JobConfig) error {
+	if err := validateJobName(jobConfig.Name); err != nil {
+		return err
+	}
+	if err := validateNamespace(jobConfig.Namespace); err != nil {
+		return err
+	}
+	if err := validateLabels(jobConfig.Labels); err != nil {
+		return err
+	}
+	if jobConfig.BackoffLimit < 0 {
+		return ErrJobBackoffLimitNegative.WithParams(jobConfig.BackoffLimit)
+	}
+	if err := validatePodConfig(jobConfig.PodConfig); err != nil {
+		return err
+	}
+	return nil
+}
+
 func validateRoleName(name string) error {
 	return validateDNS1123Subdomain(name, ErrInvalidRoleName)
 }
@@ -264,6 +406,10 @@ func validateClusterRoleBindingName(name string) error {
 	return validateDNS1123Subdomain(name, ErrInvalidClusterRoleBindingName)
 }
 
+func validateLeaseName(name string) error {
+	return validateDNS1123Subdomain(name, ErrInvalidLeaseName)
+}
+
 func validateServiceName(name string) error {
 	return validateDNS1123Label(name, ErrInvalidServiceName)
 }
@@ -277,6 +423,21 @@ func validatePorts(ports []int) error {
 	return nil
 }
 
+func validateSecret(name string, labels map[string]string, data map[string][]byte) error {
+	if err := validateDNS1123Subdomain(name, ErrInvalidSecretName); err != nil {
+		return err
+	}
+	if err := validateLabels(labels); err != nil {
+		return err
+	}
+	for key := range data {
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			return ErrInvalidSecretKey.WithParams(key, errs)
+		}
+	}
+	return nil
+}
+
 func validateConfigMap(name string, labels, data map[string]string) error {
 	if err := validateConfigMapName(name); err != nil {
 		return err
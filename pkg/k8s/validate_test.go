@@ -134,7 +134,7 @@ func TestValidatePodConfig(t *testing.T) {
 				Name:  "container",
 				Image: "image",
 				Volumes: []*Volume{
-					{Path: "/data", Size: resource.MustParse("1Gi")},
+					{Name: "data", Path: "/data", Size: resource.MustParse("1Gi")},
 				},
 				Files: []*File{
 					{Source: "source", Dest: "dest"},
@@ -150,13 +150,40 @@ func TestValidatePodConfig(t *testing.T) {
 				Name:  "container",
 				Image: "image",
 				Volumes: []*Volume{
-					{Path: "/data", Size: resource.MustParse("1Gi")},
+					{Name: "data", Path: "/data", Size: resource.MustParse("1Gi")},
 				},
 				Files: []*File{
 					{Source: "source", Dest: "dest"},
 				},
 			},
 		}, ErrInvalidPodName},
+		{"Invalid Volume Name", PodConfig{
+			Name:      "valid-name",
+			Namespace: "valid-namespace",
+			ContainerConfig: ContainerConfig{
+				Name:  "container",
+				Image: "image",
+				Volumes: []*Volume{
+					{Name: "Data", Path: "/data", Size: resource.MustParse("1Gi")},
+				},
+			},
+		}, ErrInvalidVolumeName},
+		{"Volume Mount Not Found", PodConfig{
+			Name:      "valid-name",
+			Namespace: "valid-namespace",
+			ContainerConfig: ContainerConfig{
+				Name:  "container",
+				Image: "image",
+				Volumes: []*Volume{
+					{Name: "data", Path: "/data", Size: resource.MustParse("1Gi")},
+				},
+			},
+			SidecarConfigs: []ContainerConfig{{
+				Name:         "sidecar",
+				Image:        "image",
+				VolumeMounts: []*VolumeMount{{Volume: "keys", Path: "/keys"}},
+			}},
+		}, ErrVolumeMountNotFound},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,60 @@
+package knuu
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/celestiaorg/knuu/pkg/instance"
+	"github.com/celestiaorg/knuu/pkg/system"
+)
+
+// Attach creates a Knuu object for an existing scope, e.g. after the test process that created it died,
+// and rebuilds its instances from the resources that are deployed in the cluster.
+// The scope given in the options is ignored in favor of the scope argument.
+// No new timeout handler is started, the one started with the scope keeps running.
+func Attach(ctx context.Context, scope string, opts Options) (*Knuu, []*instance.Instance, error) {
+	if scope == "" {
+		return nil, nil, ErrScopeNotSet
+	}
+	opts.Scope = scope
+	if err := validateOptions(opts); err != nil {
+		return nil, nil, err
+	}
+
+	k := &Knuu{
+		SystemDependencies: &system.SystemDependencies{
+			K8sClient:    opts.K8sClient,
+			MinioClient:  opts.MinioClient,
+			ImageBuilder: opts.ImageBuilder,
+			Logger:       opts.Logger,
+			Scope:        opts.Scope,
+			StartTime:    time.Now().UTC().Format(TimeFormat),
+		},
+	}
+
+	if err := setDefaults(ctx, k); err != nil {
+		return nil, nil, err
+	}
+
+	instances, err := instance.Attach(ctx, k.SystemDependencies)
+	if err != nil {
+		return nil, nil, ErrAttachingToScope.WithParams(k.Scope).Wrap(err)
+	}
+	if len(instances) == 0 {
+		return nil, nil, ErrNothingToAttach.WithParams(k.Scope)
+	}
+
+	if opts.ProxyEnabled {
+		if err := setupProxy(ctx, k); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	k.Logger.WithFields(logrus.Fields{
+		"scope":     k.Scope,
+		"instances": len(instances),
+	}).Info("attached to scope")
+	return k, instances, nil
+}
@@ -0,0 +1,139 @@
+package knuu
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	discfake "k8s.io/client-go/discovery/fake"
+	dynfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/celestiaorg/knuu/pkg/instance"
+	"github.com/celestiaorg/knuu/pkg/k8s"
+)
+
+const attachTestScope = "attach-test"
+
+func attachTestLabels(name, instanceType string) map[string]string {
+	return map[string]string{
+		"app":                          name,
+		"k8s.kubernetes.io/managed-by": "knuu",
+		"knuu.sh/scope":                attachTestScope,
+		"knuu.sh/name":                 name,
+		"knuu.sh/k8s-name":             name,
+		"knuu.sh/type":                 instanceType,
+	}
+}
+
+func TestAttach(t *testing.T) {
+	ctx := context.Background()
+
+	running := &appv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "validator",
+			Namespace: attachTestScope,
+			Labels:    attachTestLabels("validator", instance.BasicInstance.String()),
+		},
+		Spec: appv1.ReplicaSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:    "validator",
+							Image:   "alpine:latest",
+							Command: []string{"sleep", "infinity"},
+							Env:     []corev1.EnvVar{{Name: "KEY", Value: "value"}},
+						},
+						{
+							Name:  "validator-exporter",
+							Image: "prom/node-exporter:latest",
+						},
+					},
+				},
+			},
+		},
+	}
+	timeoutHandler := running.DeepCopy()
+	timeoutHandler.Name = "timeout-handler"
+	timeoutHandler.Labels = attachTestLabels("timeout-handler", instance.TimeoutHandlerInstance.String())
+
+	stoppedPVC := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bridge",
+			Namespace: attachTestScope,
+			Labels:    attachTestLabels("bridge", instance.BasicInstance.String()),
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+			},
+		},
+	}
+	otherScope := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other",
+			Namespace: attachTestScope,
+			Labels:    map[string]string{"knuu.sh/scope": "other", "knuu.sh/name": "other"},
+		},
+	}
+
+	k8sClient, err := k8s.NewClientCustom(ctx,
+		fake.NewSimpleClientset(running, timeoutHandler, stoppedPVC, otherScope),
+		&discfake.FakeDiscovery{Fake: &k8stesting.Fake{}},
+		dynfake.NewSimpleDynamicClient(runtime.NewScheme()),
+		attachTestScope,
+		logrus.New(),
+	)
+	require.NoError(t, err)
+
+	k, instances, err := Attach(ctx, attachTestScope, Options{K8sClient: k8sClient})
+	require.NoError(t, err)
+	require.NotNil(t, k)
+	require.Len(t, instances, 2)
+
+	byName := make(map[string]*instance.Instance, len(instances))
+	for _, i := range instances {
+		byName[i.Name()] = i
+	}
+
+	validator := byName["validator"]
+	require.NotNil(t, validator)
+	assert.Equal(t, instance.StateStarted, validator.State())
+	assert.Equal(t, "alpine:latest", validator.Build().ImageName())
+	require.Len(t, validator.Sidecars().Instances(), 1)
+	assert.Equal(t, "validator-exporter", validator.Sidecars().Instances()[0].Name())
+	assert.True(t, validator.Sidecars().Instances()[0].Sidecars().IsSidecar())
+
+	bridge := byName["bridge"]
+	require.NotNil(t, bridge)
+	assert.Equal(t, instance.StateStopped, bridge.State())
+	assert.ErrorIs(t, bridge.Execution().StartAsync(ctx), instance.ErrImageNotSetForInstance)
+}
+
+func TestAttachErrors(t *testing.T) {
+	ctx := context.Background()
+
+	_, _, err := Attach(ctx, "", Options{})
+	assert.ErrorIs(t, err, ErrScopeNotSet)
+
+	k8sClient, err := k8s.NewClientCustom(ctx,
+		fake.NewSimpleClientset(),
+		&discfake.FakeDiscovery{Fake: &k8stesting.Fake{}},
+		dynfake.NewSimpleDynamicClient(runtime.NewScheme()),
+		attachTestScope,
+		logrus.New(),
+	)
+	require.NoError(t, err)
+
+	_, _, err = Attach(ctx, attachTestScope, Options{K8sClient: k8sClient})
+	assert.ErrorIs(t, err, ErrNothingToAttach)
+}
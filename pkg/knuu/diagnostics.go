@@ -0,0 +1,285 @@
+package knuu
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/celestiaorg/knuu/pkg/instance"
+)
+
+const (
+	diagnosticsBucketName = "knuu-diagnostics"
+	diagnosticsFileMode   = 0644
+)
+
+// instanceDiagnostics is the knuu state of an instance, as written in the diagnostics
+type instanceDiagnostics struct {
+	Name     string            `json:"name"`
+	Type     string            `json:"type"`
+	State    string            `json:"state"`
+	Image    string            `json:"image,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Sidecar  bool              `json:"sidecar,omitempty"`
+	Sidecars []string          `json:"sidecars,omitempty"`
+}
+
+// CollectDiagnostics gathers the state of the scope into a tar.gz archive in the given directory
+// and returns the path of the archive.
+// The archive holds the knuu state of the instances, the timeline of their state transitions, the specs, statuses and logs (current and previous)
+// of the pods of the scope, the events of the namespace, and the configmaps, services and network policies.
+// Parts that can not be gathered are listed in errors.txt instead of failing the whole collection,
+// so that as much as possible is available to investigate a failed test.
+func (k *Knuu) CollectDiagnostics(ctx context.Context, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", ErrCreatingDiagnosticsDir.WithParams(dir).Wrap(err)
+	}
+
+	name := fmt.Sprintf("diagnostics-%s-%s.tar.gz", k.Scope, time.Now().UTC().Format(TimeFormat))
+	archivePath := filepath.Join(dir, name)
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return "", ErrCreatingDiagnosticsFile.WithParams(archivePath).Wrap(err)
+	}
+	defer f.Close()
+
+	if err := k.writeDiagnostics(ctx, f); err != nil {
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", ErrWritingDiagnostics.Wrap(err)
+	}
+
+	k.Logger.WithField("path", archivePath).Info("diagnostics collected")
+	return archivePath, nil
+}
+
+// PushDiagnostics gathers the diagnostics like CollectDiagnostics and pushes the archive to the Minio of the scope.
+// It returns the URL to download the archive.
+func (k *Knuu) PushDiagnostics(ctx context.Context) (string, error) {
+	var buf bytes.Buffer
+	if err := k.writeDiagnostics(ctx, &buf); err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("%s/diagnostics-%s.tar.gz", k.Scope, time.Now().UTC().Format(TimeFormat))
+	if err := k.MinioClient.Push(ctx, &buf, name, diagnosticsBucketName); err != nil {
+		return "", ErrPushingDiagnostics.Wrap(err)
+	}
+	url, err := k.MinioClient.GetURL(ctx, name, diagnosticsBucketName)
+	if err != nil {
+		return "", ErrPushingDiagnostics.Wrap(err)
+	}
+
+	k.Logger.WithField("url", url).Info("diagnostics pushed")
+	return url, nil
+}
+
+// diagnosticsWriter writes the files of the diagnostics archive
+// and keeps track of the parts that could not be gathered
+type diagnosticsWriter struct {
+	tw   *tar.Writer
+	now  time.Time
+	errs []string
+}
+
+func (k *Knuu) writeDiagnostics(ctx context.Context, w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	d := &diagnosticsWriter{
+		tw:  tar.NewWriter(gz),
+		now: time.Now(),
+	}
+
+	steps := []func() error{
+		func() error { return k.diagnoseInstances(d) },
+		func() error { return k.diagnoseTimeline(d) },
+		func() error { return k.diagnosePodStatuses(ctx, d) },
+		func() error { return k.diagnosePods(ctx, d) },
+		func() error { return k.diagnoseNamespace(ctx, d) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return ErrWritingDiagnostics.Wrap(err)
+		}
+	}
+
+	if len(d.errs) != 0 {
+		if err := d.writeFile("errors.txt", []byte(strings.Join(d.errs, "\n")+"\n")); err != nil {
+			return ErrWritingDiagnostics.Wrap(err)
+		}
+	}
+	if err := d.tw.Close(); err != nil {
+		return ErrWritingDiagnostics.Wrap(err)
+	}
+	if err := gz.Close(); err != nil {
+		return ErrWritingDiagnostics.Wrap(err)
+	}
+	return nil
+}
+
+func (k *Knuu) diagnoseInstances(d *diagnosticsWriter) error {
+	var instances []instanceDiagnostics
+	for _, i := range instance.Instances(k.SystemDependencies) {
+		diag := instanceDiagnostics{
+			Name:    i.Name(),
+			Type:    i.InstanceType().String(),
+			State:   i.State().String(),
+			Image:   i.Build().ImageName(),
+			Labels:  i.Execution().UserLabels(),
+			Sidecar: i.Sidecars().IsSidecar(),
+		}
+		for _, sc := range i.Sidecars().Instances() {
+			diag.Sidecars = append(diag.Sidecars, sc.Name())
+		}
+		instances = append(instances, diag)
+	}
+	return d.writeJSON("instances.json", instances)
+}
+
+func (k *Knuu) diagnoseTimeline(d *diagnosticsWriter) error {
+	var buf bytes.Buffer
+	if err := k.Timeline().WriteJSON(&buf); err != nil {
+		return err
+	}
+	return d.writeFile("timeline.json", buf.Bytes())
+}
+
+func (k *Knuu) diagnosePodStatuses(ctx context.Context, d *diagnosticsWriter) error {
+	statuses, err := k.K8sClient.AllPodsStatuses(ctx)
+	if err != nil {
+		d.addError("pod statuses", err)
+		return nil
+	}
+
+	var sb strings.Builder
+	for _, s := range statuses {
+		fmt.Fprintf(&sb, "%-60s | %-10s | pending for %s\n", s.Name, s.Status, s.PendingDuration)
+	}
+	return d.writeFile("pod-statuses.txt", []byte(sb.String()))
+}
+
+func (k *Knuu) diagnosePods(ctx context.Context, d *diagnosticsWriter) error {
+	pods, err := k.K8sClient.ListPods(ctx, map[string]string{"knuu.sh/scope": k.Scope})
+	if err != nil {
+		d.addError("pods", err)
+		return nil
+	}
+
+	for _, pod := range pods {
+		if err := d.writeJSON(path.Join("pods", pod.Name+".json"), pod); err != nil {
+			return err
+		}
+
+		restarts := make(map[string]int32)
+		for _, statuses := range [][]v1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+			for _, s := range statuses {
+				restarts[s.Name] = s.RestartCount
+			}
+		}
+		for _, containers := range [][]v1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+			for _, c := range containers {
+				dir := path.Join("logs", pod.Name)
+				if err := k.diagnoseLogs(ctx, d, pod.Name, c.Name, false, path.Join(dir, c.Name+".log")); err != nil {
+					return err
+				}
+				// the logs of the previous run only exist if the container has been restarted
+				if restarts[c.Name] == 0 {
+					continue
+				}
+				if err := k.diagnoseLogs(ctx, d, pod.Name, c.Name, true, path.Join(dir, c.Name+".previous.log")); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (k *Knuu) diagnoseLogs(ctx context.Context, d *diagnosticsWriter, podName, containerName string, previous bool, name string) error {
+	stream, err := k.K8sClient.GetPodLogStream(ctx, podName, v1.PodLogOptions{
+		Container: containerName,
+		Previous:  previous,
+	})
+	if err != nil {
+		d.addError(name, err)
+		return nil
+	}
+	defer stream.Close()
+
+	logs, err := io.ReadAll(stream)
+	if err != nil {
+		d.addError(name, err)
+		return nil
+	}
+	return d.writeFile(name, logs)
+}
+
+func (k *Knuu) diagnoseNamespace(ctx context.Context, d *diagnosticsWriter) error {
+	scopeLabels := map[string]string{"knuu.sh/scope": k.Scope}
+
+	events, err := k.K8sClient.ListEvents(ctx)
+	if err != nil {
+		d.addError("events", err)
+	} else if err := d.writeJSON("events.json", events); err != nil {
+		return err
+	}
+
+	configMaps, err := k.K8sClient.ListConfigMaps(ctx, scopeLabels)
+	if err != nil {
+		d.addError("configmaps", err)
+	} else if err := d.writeJSON("configmaps.json", configMaps); err != nil {
+		return err
+	}
+
+	services, err := k.K8sClient.ListServices(ctx, scopeLabels)
+	if err != nil {
+		d.addError("services", err)
+	} else if err := d.writeJSON("services.json", services); err != nil {
+		return err
+	}
+
+	networkPolicies, err := k.K8sClient.ListNetworkPolicies(ctx)
+	if err != nil {
+		d.addError("network policies", err)
+	} else if err := d.writeJSON("networkpolicies.json", networkPolicies); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d *diagnosticsWriter) addError(part string, err error) {
+	d.errs = append(d.errs, fmt.Sprintf("%s: %v", part, err))
+}
+
+func (d *diagnosticsWriter) writeJSON(name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return d.writeFile(name, data)
+}
+
+func (d *diagnosticsWriter) writeFile(name string, data []byte) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    diagnosticsFileMode,
+		Size:    int64(len(data)),
+		ModTime: d.now,
+	}
+	if err := d.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := d.tw.Write(data)
+	return err
+}
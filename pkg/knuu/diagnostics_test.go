@@ -0,0 +1,121 @@
+package knuu
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	discfake "k8s.io/client-go/discovery/fake"
+	dynfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+	"github.com/celestiaorg/knuu/pkg/system"
+)
+
+const diagnosticsTestScope = "diagnostics-test"
+
+func TestCollectDiagnostics(t *testing.T) {
+	ctx := context.Background()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "validator-abcde",
+			Namespace: diagnosticsTestScope,
+			Labels:    map[string]string{"knuu.sh/scope": diagnosticsTestScope},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "validator"}, {Name: "validator-exporter"}},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "validator", RestartCount: 1},
+				{Name: "validator-exporter"},
+			},
+		},
+	}
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "validator-abcde.1",
+			Namespace: diagnosticsTestScope,
+		},
+		Reason: "BackOff",
+	}
+
+	k8sClient, err := k8s.NewClientCustom(ctx,
+		fake.NewSimpleClientset(pod, event),
+		&discfake.FakeDiscovery{Fake: &k8stesting.Fake{}},
+		dynfake.NewSimpleDynamicClient(runtime.NewScheme()),
+		diagnosticsTestScope,
+		logrus.New(),
+	)
+	require.NoError(t, err)
+
+	k := &Knuu{
+		SystemDependencies: &system.SystemDependencies{
+			K8sClient: k8sClient,
+			Logger:    logrus.New(),
+			Scope:     diagnosticsTestScope,
+		},
+	}
+	_, err = k.NewInstance("validator")
+	require.NoError(t, err)
+
+	archivePath, err := k.CollectDiagnostics(ctx, t.TempDir())
+	require.NoError(t, err)
+
+	files := readTarGz(t, archivePath)
+	for _, name := range []string{
+		"instances.json",
+		"timeline.json",
+		"pod-statuses.txt",
+		"pods/validator-abcde.json",
+		"logs/validator-abcde/validator.log",
+		"logs/validator-abcde/validator.previous.log",
+		"logs/validator-abcde/validator-exporter.log",
+		"events.json",
+		"configmaps.json",
+		"services.json",
+		"networkpolicies.json",
+	} {
+		assert.Contains(t, files, name)
+	}
+	assert.NotContains(t, files, "logs/validator-abcde/validator-exporter.previous.log")
+	assert.NotContains(t, files, "errors.txt")
+	assert.Contains(t, files["instances.json"], `"name": "validator"`)
+	assert.Contains(t, files["events.json"], "BackOff")
+}
+
+func readTarGz(t *testing.T, path string) map[string]string {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+
+	files := make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = string(data)
+	}
+	return files
+}
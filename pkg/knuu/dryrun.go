@@ -0,0 +1,28 @@
+package knuu
+
+import (
+	"context"
+	"io"
+
+	"github.com/celestiaorg/knuu/pkg/builder"
+	"github.com/celestiaorg/knuu/pkg/k8s"
+)
+
+// dryRunBuilder does not build anything, the instances use the name of the image that would have been built
+type dryRunBuilder struct{}
+
+var _ builder.Builder = dryRunBuilder{}
+
+func (dryRunBuilder) Build(_ context.Context, _ *builder.BuilderOptions) (string, error) {
+	return "", nil
+}
+
+// WriteManifests writes the objects knuu would have created so far as a multi-document YAML,
+// in the order they were created. It can only be used in dry-run mode.
+func (k *Knuu) WriteManifests(w io.Writer) error {
+	dryRun, ok := k.K8sClient.(*k8s.DryRunClient)
+	if !ok {
+		return ErrNotDryRun
+	}
+	return dryRun.WriteManifests(w)
+}
@@ -0,0 +1,64 @@
+package knuu
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/knuu/pkg/reaper"
+	"github.com/celestiaorg/knuu/pkg/system"
+)
+
+func TestDryRun(t *testing.T) {
+	ctx := context.Background()
+
+	k, err := New(ctx, Options{
+		Scope:       "dry-run-test",
+		DryRun:      true,
+		Logger:      logrus.New(),
+		ReaperImage: reaper.DefaultImage,
+	})
+	require.NoError(t, err)
+
+	validator, err := k.NewInstance("validator")
+	require.NoError(t, err)
+	require.NoError(t, validator.Build().SetImage(ctx, "alpine:latest"))
+	require.NoError(t, validator.Build().Commit(ctx))
+	require.NoError(t, validator.Network().AddPortTCP(26656))
+	require.NoError(t, validator.Security().AddSecretEnv("MNEMONIC", "very secret words"))
+	require.NoError(t, validator.Execution().Start(ctx))
+
+	out, err := validator.Execution().ExecuteCommand(ctx, "echo", "hello")
+	require.NoError(t, err)
+	assert.Empty(t, out)
+
+	var buf bytes.Buffer
+	require.NoError(t, k.WriteManifests(&buf))
+	manifests := buf.String()
+	for _, expected := range []string{
+		"kind: Namespace",
+		"kind: Lease",
+		"kind: ClusterRoleBinding",
+		"kind: ReplicaSet",
+		"kind: Service",
+		"kind: Secret",
+		"image: alpine:latest",
+		"name: validator",
+		"namespace: dry-run-test",
+	} {
+		assert.Contains(t, manifests, expected)
+	}
+	assert.NotContains(t, manifests, "status:")
+	assert.NotContains(t, manifests, "resourceVersion")
+	assert.NotContains(t, manifests, "very secret words")
+
+	_, err = New(ctx, Options{DryRun: true, ProxyEnabled: true})
+	assert.ErrorIs(t, err, ErrDryRunWithProxy)
+
+	notDryRun := &Knuu{SystemDependencies: &system.SystemDependencies{}}
+	assert.ErrorIs(t, notDryRun.WriteManifests(&buf), ErrNotDryRun)
+}
@@ -210,4 +210,50 @@ var (
 	ErrScopeMismatch                             = errors.New("ScopeMismatch", "scope '%s' set in options does not match scope '%s' set by the k8sClient namespace")
 	ErrHandleTimeout                             = errors.New("HandleTimeout", "error starting handle timeout")
 	ErrDeprecated                                = errors.New("Deprecated", "deprecated")
+	ErrReadingTopologyFile                       = errors.New("ReadingTopologyFile", "error reading topology file '%s'")
+	ErrParsingTopology                           = errors.New("ParsingTopology", "error parsing topology")
+	ErrTopologyHasNoInstances                    = errors.New("TopologyHasNoInstances", "topology has no instances")
+	ErrTopologyInstanceNameEmpty                 = errors.New("TopologyInstanceNameEmpty", "topology instance name is empty")
+	ErrTopologyImageEmpty                        = errors.New("TopologyImageEmpty", "image is not set for topology instance '%s'")
+	ErrTopologyDuplicateInstance                 = errors.New("TopologyDuplicateInstance", "topology instance '%s' is defined more than once")
+	ErrTopologyNestedSidecars                    = errors.New("TopologyNestedSidecars", "sidecar '%s' of instance '%s' cannot have sidecars")
+	ErrTopologySidecarStart                      = errors.New("TopologySidecarStart", "start cannot be set for sidecar '%s' of instance '%s'")
+	ErrTopologyInvalidFile                       = errors.New("TopologyInvalidFile", "src and dest must be set for all files of topology instance '%s'")
+	ErrTopologyInvalidChown                      = errors.New("TopologyInvalidChown", "invalid chown '%s' of file '%s' of topology instance '%s', it must be in the format user:group")
+	ErrTopologyInvalidVolume                     = errors.New("TopologyInvalidVolume", "path must be set for all volumes of topology instance '%s'")
+	ErrTopologyInvalidQuantity                   = errors.New("TopologyInvalidQuantity", "invalid quantity '%s' for topology instance '%s'")
+	ErrTopologyInstanceNotFound                  = errors.New("TopologyInstanceNotFound", "topology instance '%s' not found")
+	ErrCreatingTopologyInstance                  = errors.New("CreatingTopologyInstance", "error creating topology instance '%s'")
+	ErrAddingTopologySidecar                     = errors.New("AddingTopologySidecar", "error adding sidecar '%s' to topology instance '%s'")
+	ErrStartingTopologyInstance                  = errors.New("StartingTopologyInstance", "error starting topology instance '%s'")
+	ErrTopologySidecarNotInitialized             = errors.New("TopologySidecarNotInitialized", "topology sidecar '%s' is not initialized")
+	ErrAttachingToScope                          = errors.New("AttachingToScope", "error attaching to scope '%s'")
+	ErrNothingToAttach                           = errors.New("NothingToAttach", "no instances found to attach in scope '%s'")
+	ErrCannotCreateTimeoutLease                  = errors.New("CannotCreateTimeoutLease", "cannot create the lease holding the timeout")
+	ErrCannotSetReaperEnv                        = errors.New("CannotSetReaperEnv", "cannot set the environment of the reaper")
+	ErrGettingTimeout                            = errors.New("GettingTimeout", "error getting the timeout of the scope")
+	ErrExtendingTimeout                          = errors.New("ExtendingTimeout", "error extending the timeout of the scope")
+	ErrRenewingTimeout                           = errors.New("RenewingTimeout", "error renewing the timeout of the scope")
+	ErrScopeTimedOut                             = errors.New("ScopeTimedOut", "scope '%s' has already timed out")
+	ErrExtendingTimeoutWithoutReaper             = errors.New("ExtendingTimeoutWithoutReaper", "the timeout of scope '%s' can only be extended with the reaper, see Options.ReaperImage")
+	ErrCannotCreateReaperClusterRole             = errors.New("CannotCreateReaperClusterRole", "cannot create the cluster role of the reaper")
+	ErrCannotCreateReaperClusterRoleBinding      = errors.New("CannotCreateReaperClusterRoleBinding", "cannot create the cluster role binding of the reaper")
+	ErrInstanceNotFound                          = errors.New("InstanceNotFound", "instance '%s' not found")
+	ErrCreatingDiagnosticsDir                    = errors.New("CreatingDiagnosticsDir", "error creating diagnostics directory '%s'")
+	ErrCreatingDiagnosticsFile                   = errors.New("CreatingDiagnosticsFile", "error creating diagnostics file '%s'")
+	ErrWritingDiagnostics                        = errors.New("WritingDiagnostics", "error writing diagnostics")
+	ErrPushingDiagnostics                        = errors.New("PushingDiagnostics", "error pushing diagnostics to minio")
+	ErrWatchingEvents                            = errors.New("WatchingEvents", "error watching the events of the scope")
+	ErrDryRunWithK8sClient                       = errors.New("DryRunWithK8sClient", "dry-run mode can not be used with a k8s client")
+	ErrDryRunWithProxy                           = errors.New("DryRunWithProxy", "dry-run mode can not be used with the proxy")
+	ErrNotDryRun                                 = errors.New("NotDryRun", "knuu is not in dry-run mode")
+	ErrTopologyDependencyNotFound                = errors.New("TopologyDependencyNotFound", "topology instance '%s' depends on unknown instance '%s'")
+	ErrTopologyDependencyNotStarted              = errors.New("TopologyDependencyNotStarted", "topology instance '%s' depends on instance '%s', which is not started")
+	ErrTopologyInvalidDependency                 = errors.New("TopologyInvalidDependency", "dependency of topology instance '%s' on '%s' can have a log line or a tcp port, not both")
+	ErrTopologySidecarDependsOn                  = errors.New("TopologySidecarDependsOn", "dependsOn cannot be set for sidecar '%s' of instance '%s'")
+	ErrTopologyDependencyCycle                   = errors.New("TopologyDependencyCycle", "topology has a dependency cycle: %s")
+	ErrAddingTopologyDependency                  = errors.New("AddingTopologyDependency", "error adding dependency of topology instance '%s' on '%s'")
+	ErrStartingTopology                          = errors.New("StartingTopology", "error starting the topology")
+	ErrTopologyMountsNotSidecar                  = errors.New("TopologyMountsNotSidecar", "mounts can only be set for sidecars, not for topology instance '%s'")
+	ErrTopologyMountNotFound                     = errors.New("TopologyMountNotFound", "sidecar '%s' of topology instance '%s' mounts unknown volume '%s'")
 )
@@ -0,0 +1,62 @@
+package knuu
+
+import (
+	"context"
+
+	"github.com/celestiaorg/knuu/pkg/instance"
+	"github.com/celestiaorg/knuu/pkg/k8s"
+)
+
+const eventsLabelName = "knuu.sh/name"
+
+// Event is a Kubernetes event of the scope, with the instance it belongs to
+type Event struct {
+	k8s.Event
+	// Instance is the instance or sidecar the event is about,
+	// nil if the event is not about an instance of this knuu object (e.g. the proxy)
+	Instance *instance.Instance
+}
+
+// Events streams the events of the scope from now on, until the context is done.
+// Each event is mapped to the instance, or the sidecar, it belongs to.
+// The channel is closed when the context is done.
+func (k *Knuu) Events(ctx context.Context) (<-chan Event, error) {
+	k8sEvents, err := k.K8sClient.WatchEvents(ctx)
+	if err != nil {
+		return nil, ErrWatchingEvents.Wrap(err)
+	}
+
+	events := make(chan Event, cap(k8sEvents))
+	go func() {
+		defer close(events)
+		for e := range k8sEvents {
+			select {
+			case events <- Event{Event: e, Instance: k.eventInstance(e)}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+// eventInstance returns the instance the event is about.
+// As the containers are named after their instance, the container of the event gives the sidecar it belongs to;
+// otherwise the instance is found from the labels of the pod, or the name of the object for the other kinds.
+func (k *Knuu) eventInstance(e k8s.Event) *instance.Instance {
+	instances := make(map[string]*instance.Instance)
+	for _, i := range instance.Instances(k.SystemDependencies) {
+		instances[i.Name()] = i
+	}
+
+	candidates := []string{e.Container, e.Labels[eventsLabelName]}
+	if e.Kind != "Pod" {
+		candidates = append(candidates, e.Name)
+	}
+	for _, name := range candidates {
+		if i, ok := instances[name]; ok && name != "" {
+			return i
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package knuu
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	discfake "k8s.io/client-go/discovery/fake"
+	dynfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/celestiaorg/knuu/pkg/instance"
+	"github.com/celestiaorg/knuu/pkg/k8s"
+	"github.com/celestiaorg/knuu/pkg/system"
+)
+
+const eventsTestScope = "events-test"
+
+func TestEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "validator-abcde",
+			Namespace: eventsTestScope,
+			Labels:    map[string]string{"knuu.sh/name": "validator"},
+		},
+	}
+	k8sClient, err := k8s.NewClientCustom(ctx,
+		fake.NewSimpleClientset(pod),
+		&discfake.FakeDiscovery{Fake: &k8stesting.Fake{}},
+		dynfake.NewSimpleDynamicClient(runtime.NewScheme()),
+		eventsTestScope,
+		logrus.New(),
+	)
+	require.NoError(t, err)
+
+	k := &Knuu{
+		SystemDependencies: &system.SystemDependencies{
+			K8sClient: k8sClient,
+			Logger:    logrus.New(),
+			Scope:     eventsTestScope,
+		},
+	}
+	validator, err := k.NewInstance("validator")
+	require.NoError(t, err)
+	exporter, err := k.NewInstance("validator-exporter")
+	require.NoError(t, err)
+
+	events, err := k.Events(ctx)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		fieldPath string
+		reason    string
+		expected  k8s.EventType
+		instance  *instance.Instance
+	}{
+		{
+			name:     "pod event",
+			reason:   "FailedScheduling",
+			expected: k8s.EventFailedScheduling,
+			instance: validator,
+		},
+		{
+			name:      "sidecar event",
+			fieldPath: "spec.containers{validator-exporter}",
+			reason:    "BackOff",
+			expected:  k8s.EventBackOff,
+			instance:  exporter,
+		},
+	}
+	for n, tc := range tests {
+		_, err := k8sClient.Clientset().CoreV1().Events(eventsTestScope).Create(ctx, &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{Name: "validator-abcde." + string(rune('a'+n)), Namespace: eventsTestScope},
+			InvolvedObject: corev1.ObjectReference{
+				Kind:      "Pod",
+				Name:      "validator-abcde",
+				FieldPath: tc.fieldPath,
+			},
+			Reason: tc.reason,
+		}, metav1.CreateOptions{})
+		require.NoError(t, err)
+
+		select {
+		case e := <-events:
+			assert.Equal(t, tc.expected, e.Type, tc.name)
+			assert.Same(t, tc.instance, e.Instance, tc.name)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s: timed out waiting for event", tc.name)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package knuu
 
 import (
 	"github.com/celestiaorg/knuu/pkg/instance"
+	"github.com/celestiaorg/knuu/pkg/k8s"
 	"github.com/celestiaorg/knuu/pkg/preloader"
 )
 
@@ -13,3 +14,31 @@ func (k *Knuu) NewInstance(name string) (*instance.Instance, error) {
 func (k *Knuu) NewPreloader(name string) (*preloader.Preloader, error) {
 	return preloader.New(name, k.SystemDependencies)
 }
+
+// Instances returns the instances created by this knuu object, sorted by name.
+// The timeout handler, which removes the scope once the timeout is reached, is not included.
+func (k *Knuu) Instances() instance.List {
+	var list instance.List
+	for _, i := range instance.Instances(k.SystemDependencies) {
+		if i.InstanceType() != instance.TimeoutHandlerInstance {
+			list = append(list, i)
+		}
+	}
+	return list
+}
+
+// Instance returns the instance with the given name
+func (k *Knuu) Instance(name string) (*instance.Instance, error) {
+	name = k8s.SanitizeName(name)
+	for _, i := range k.Instances() {
+		if i.Name() == name {
+			return i, nil
+		}
+	}
+	return nil, ErrInstanceNotFound.WithParams(name)
+}
+
+// FindInstances returns the instances created by this knuu object that are selected by the filter
+func (k *Knuu) FindInstances(filter instance.Filter) instance.List {
+	return k.Instances().Filter(filter)
+}
@@ -0,0 +1,77 @@
+package knuu
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/knuu/pkg/instance"
+	"github.com/celestiaorg/knuu/pkg/system"
+)
+
+func TestInstances(t *testing.T) {
+	k := &Knuu{
+		SystemDependencies: &system.SystemDependencies{
+			Logger: logrus.New(),
+			Scope:  "test",
+		},
+	}
+
+	validator, err := k.NewInstance("validator")
+	require.NoError(t, err)
+	validator.SetState(instance.StatePreparing)
+	require.NoError(t, validator.Execution().AddLabel("role", "validator"))
+
+	bridge, err := k.NewInstance("bridge")
+	require.NoError(t, err)
+	bridge.SetState(instance.StatePreparing)
+	require.NoError(t, bridge.Execution().AddLabel("role", "bridge"))
+
+	handler, err := k.NewInstance(timeoutHandlerName)
+	require.NoError(t, err)
+	handler.SetInstanceType(instance.TimeoutHandlerInstance)
+
+	_, err = k.NewPreloader("preloader")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"bridge", "validator"}, k.Instances().Names())
+
+	got, err := k.Instance("validator")
+	require.NoError(t, err)
+	assert.Same(t, validator, got)
+
+	_, err = k.Instance(timeoutHandlerName)
+	assert.ErrorIs(t, err, ErrInstanceNotFound)
+
+	found := k.FindInstances(instance.Filter{Labels: map[string]string{"role": "bridge"}})
+	assert.Equal(t, []string{"bridge"}, found.Names())
+
+	found = k.FindInstances(instance.Filter{States: []instance.InstanceState{instance.StateStarted}})
+	assert.Empty(t, found)
+}
+
+func TestAddLabel(t *testing.T) {
+	k := &Knuu{
+		SystemDependencies: &system.SystemDependencies{
+			Logger: logrus.New(),
+			Scope:  "test",
+		},
+	}
+
+	ins, err := k.NewInstance("labeled")
+	require.NoError(t, err)
+
+	err = ins.Execution().AddLabel("role", "validator")
+	assert.ErrorIs(t, err, instance.ErrAddingLabelNotAllowed)
+
+	ins.SetState(instance.StatePreparing)
+	assert.ErrorIs(t, ins.Execution().AddLabel("knuu.sh/name", "other"), instance.ErrLabelKeyReserved)
+	assert.ErrorIs(t, ins.Execution().AddLabel("role", "not a valid value"), instance.ErrInvalidLabel)
+
+	require.NoError(t, ins.Execution().AddLabel("role", "validator"))
+	labels := ins.Execution().Labels()
+	assert.Equal(t, "validator", labels["role"])
+	assert.Equal(t, "labeled", labels["knuu.sh/name"])
+}
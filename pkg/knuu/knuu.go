@@ -3,6 +3,7 @@ package knuu
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -20,6 +21,7 @@ import (
 	"github.com/celestiaorg/knuu/pkg/k8s"
 	"github.com/celestiaorg/knuu/pkg/log"
 	"github.com/celestiaorg/knuu/pkg/minio"
+	"github.com/celestiaorg/knuu/pkg/reaper"
 	"github.com/celestiaorg/knuu/pkg/system"
 	"github.com/celestiaorg/knuu/pkg/traefik"
 )
@@ -32,7 +34,11 @@ const (
 
 	timeoutHandlerNameStop = timeoutHandlerName + "-stop"
 	timeoutHandlerTimeout  = 1 * time.Second
-	ExitCodeSIGINT         = 130
+	// heartbeatPeriods is the number of times the lease of the scope is renewed within the timeout,
+	// so a couple of renewals can fail before the reaper removes the scope of a live process
+	heartbeatPeriods = 3
+
+	ExitCodeSIGINT = 130
 
 	TimeFormat = "20060102T150405Z"
 )
@@ -40,6 +46,16 @@ const (
 type Knuu struct {
 	*system.SystemDependencies
 	stopMu sync.Mutex
+
+	timeline   *instance.Timeline
+	timelineMu sync.Mutex
+
+	// timeoutMu serializes the updates of the lease holding the deadline of the scope
+	timeoutMu     sync.Mutex
+	stopHeartbeat context.CancelFunc
+
+	reaperImage string
+	dryRun      bool
 }
 
 type Options struct {
@@ -50,6 +66,13 @@ type Options struct {
 	ProxyEnabled bool
 	Timeout      time.Duration
 	Logger       *logrus.Logger
+	// ReaperImage is the image of the reaper that removes the scope once it times out, e.g. reaper.DefaultImage.
+	// When it is not set, the scope is removed by a kubectl timeout handler once the timeout has passed,
+	// which can not be extended and does not remove the instances whose TTL has passed.
+	ReaperImage string
+	// DryRun records the objects knuu would create instead of applying them to a cluster,
+	// so they can be written out with WriteManifests. No cluster is needed.
+	DryRun bool
 }
 
 func New(ctx context.Context, opts Options) (*Knuu, error) {
@@ -66,6 +89,8 @@ func New(ctx context.Context, opts Options) (*Knuu, error) {
 			Scope:        opts.Scope,
 			StartTime:    time.Now().UTC().Format(TimeFormat),
 		},
+		reaperImage: opts.ReaperImage,
+		dryRun:      opts.DryRun,
 	}
 
 	if err := setDefaults(ctx, k); err != nil {
@@ -75,9 +100,12 @@ func New(ctx context.Context, opts Options) (*Knuu, error) {
 	if opts.Timeout == 0 {
 		opts.Timeout = defaultTimeout
 	}
-	if err := k.handleTimeout(ctx, opts.Timeout, timeoutHandlerName); err != nil {
+	if err := k.handleTimeout(ctx, opts.Timeout); err != nil {
 		return nil, ErrHandleTimeout.Wrap(err)
 	}
+	if !k.dryRun && k.reaperImage != "" {
+		k.startHeartbeat(opts.Timeout)
+	}
 
 	if opts.ProxyEnabled {
 		if err := setupProxy(ctx, k); err != nil {
@@ -88,8 +116,16 @@ func New(ctx context.Context, opts Options) (*Knuu, error) {
 	return k, nil
 }
 
+// CleanUp removes all the resources of the scope and its namespace.
+// The cluster-scoped resources, which are not removed with the namespace, are removed as well.
 func (k *Knuu) CleanUp(ctx context.Context) error {
-	return k.K8sClient.DeleteNamespace(ctx, k.Scope)
+	k.stopTimeoutHeartbeat()
+	r := &reaper.Reaper{
+		K8sClient: k.K8sClient,
+		Scope:     k.Scope,
+		Logger:    k.Logger,
+	}
+	return errors.Join(r.CleanUp(ctx), r.CleanUpClusterResources(ctx))
 }
 
 func (k *Knuu) HandleStopSignal(ctx context.Context) {
@@ -101,17 +137,189 @@ func (k *Knuu) HandleStopSignal(ctx context.Context) {
 		// Lock the stop mutex to prevent multiple stop signals from being processed concurrently
 		k.stopMu.Lock()
 		defer k.stopMu.Unlock()
-		err := k.handleTimeout(ctx, timeoutHandlerTimeout, timeoutHandlerNameStop)
-		if err != nil {
-			k.Logger.Errorf("Error cleaning up resources with timeout handler: %v", err)
+		k.stopTimeoutHeartbeat()
+		if k.reaperImage == "" {
+			err := k.startTimeoutHandler(ctx, timeoutHandlerTimeout, timeoutHandlerNameStop)
+			if err != nil {
+				k.Logger.Errorf("Error cleaning up resources with timeout handler: %v", err)
+			}
+		} else if _, err := k.K8sClient.RenewLease(ctx, reaper.LeaseName, 0); err != nil {
+			// Expiring the lease makes the reaper clean up the scope right away
+			k.Logger.Errorf("Error expiring the timeout of the scope: %v", err)
 		}
 		k.K8sClient.Terminate()
 		os.Exit(ExitCodeSIGINT)
 	}()
 }
 
-// handleTimeout creates a timeout handler that will delete all resources with the scope after the timeout
-func (k *Knuu) handleTimeout(ctx context.Context, timeout time.Duration, timeoutHandlerName string) error {
+// ExtendTimeout extends the deadline of the scope by the given duration.
+// Once the deadline is reached, the reaper deletes all the resources of the scope.
+// The heartbeat keeps the extended deadline until it is closer than the timeout of the scope.
+// It needs the reaper, see Options.ReaperImage.
+func (k *Knuu) ExtendTimeout(ctx context.Context, d time.Duration) error {
+	if k.reaperImage == "" {
+		return ErrExtendingTimeoutWithoutReaper.WithParams(k.Scope)
+	}
+
+	k.timeoutMu.Lock()
+	defer k.timeoutMu.Unlock()
+
+	lease, err := k.K8sClient.GetLease(ctx, reaper.LeaseName)
+	if err != nil {
+		return ErrGettingTimeout.Wrap(err)
+	}
+
+	now := time.Now()
+	deadline := k8s.LeaseExpiry(lease)
+	if !now.Before(deadline) {
+		return ErrScopeTimedOut.WithParams(k.Scope)
+	}
+
+	if _, err := k.K8sClient.RenewLease(ctx, reaper.LeaseName, deadline.Add(d).Sub(now)); err != nil {
+		return ErrExtendingTimeout.Wrap(err)
+	}
+	k.Logger.WithFields(logrus.Fields{
+		"scope":    k.Scope,
+		"deadline": deadline.Add(d),
+	}).Debug("timeout extended")
+	return nil
+}
+
+// startHeartbeat renews the lease of the scope every timeout/heartbeatPeriods until CleanUp is called
+// or a stop signal is handled. It does not depend on the context given to New, which may be done long before the test is.
+// The reaper therefore only removes the scope once the test process has stopped renewing it for the whole timeout.
+func (k *Knuu) startHeartbeat(timeout time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	k.timeoutMu.Lock()
+	k.stopHeartbeat = cancel
+	k.timeoutMu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(timeout / heartbeatPeriods)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if err := k.renewTimeout(ctx, timeout); err != nil {
+				k.Logger.WithError(err).WithField("scope", k.Scope).Error("renewing the timeout of the scope")
+			}
+		}
+	}()
+}
+
+// stopTimeoutHeartbeat stops renewing the lease of the scope, the deadline is left as is
+func (k *Knuu) stopTimeoutHeartbeat() {
+	k.timeoutMu.Lock()
+	defer k.timeoutMu.Unlock()
+	if k.stopHeartbeat != nil {
+		k.stopHeartbeat()
+		k.stopHeartbeat = nil
+	}
+}
+
+// renewTimeout moves the deadline of the scope to the given duration from now.
+// A deadline that is further away, e.g. after ExtendTimeout, is kept,
+// and a scope that has already timed out is not brought back as the reaper is removing it.
+func (k *Knuu) renewTimeout(ctx context.Context, timeout time.Duration) error {
+	k.timeoutMu.Lock()
+	defer k.timeoutMu.Unlock()
+	// the heartbeat is stopped under the lock, so no renewal happens once it is stopped
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	lease, err := k.K8sClient.GetLease(ctx, reaper.LeaseName)
+	if err != nil {
+		return ErrGettingTimeout.Wrap(err)
+	}
+
+	now := time.Now()
+	deadline := k8s.LeaseExpiry(lease)
+	if !now.Before(deadline) {
+		return ErrScopeTimedOut.WithParams(k.Scope)
+	}
+	if deadline.After(now.Add(timeout)) {
+		return nil
+	}
+
+	if _, err := k.K8sClient.RenewLease(ctx, reaper.LeaseName, timeout); err != nil {
+		return ErrRenewingTimeout.Wrap(err)
+	}
+	return nil
+}
+
+// handleTimeout starts the handler that deletes all resources with the scope once the timeout is reached:
+// the reaper if its image is set, or the kubectl timeout handler otherwise.
+func (k *Knuu) handleTimeout(ctx context.Context, timeout time.Duration) error {
+	if k.reaperImage == "" {
+		return k.startTimeoutHandler(ctx, timeout, timeoutHandlerName)
+	}
+	return k.startReaper(ctx, timeout)
+}
+
+// startReaper starts the reaper that deletes all resources with the scope once the timeout is reached.
+// The deadline is held by a lease, so it can be extended with ExtendTimeout.
+func (k *Knuu) startReaper(ctx context.Context, timeout time.Duration) error {
+	leaseLabels := map[string]string{
+		"k8s.kubernetes.io/managed-by": "knuu",
+		"knuu.sh/scope":                k.Scope,
+		"knuu.sh/type":                 instance.TimeoutHandlerInstance.String(),
+	}
+	if _, err := k.K8sClient.CreateLease(ctx, reaper.LeaseName, leaseLabels, timeout); err != nil {
+		return ErrCannotCreateTimeoutLease.Wrap(err)
+	}
+
+	inst, err := k.NewInstance(timeoutHandlerName)
+	if err != nil {
+		return ErrCannotCreateInstance.Wrap(err)
+	}
+	inst.SetInstanceType(instance.TimeoutHandlerInstance)
+
+	if err := inst.Build().SetImage(ctx, k.reaperImage); err != nil {
+		return ErrCannotSetImage.Wrap(err)
+	}
+	if err := inst.Build().Commit(ctx); err != nil {
+		return ErrCannotCommitInstance.Wrap(err)
+	}
+
+	if err := inst.Build().SetEnvironmentVariable(reaper.EnvScope, k.Scope); err != nil {
+		return ErrCannotSetReaperEnv.Wrap(err)
+	}
+
+	rule := rbacv1.PolicyRule{
+		Verbs:     []string{"*"},
+		APIGroups: []string{"*"},
+		Resources: []string{"*"},
+	}
+
+	if err := inst.Security().AddPolicyRule(rule); err != nil {
+		return ErrCannotAddPolicyRule.Wrap(err)
+	}
+
+	// The reaper needs a cluster role to remove the namespace of the scope, limited to that namespace.
+	// The binding is owned by the cluster role, so the reaper removes both by deleting the cluster role.
+	clusterRoleName := reaper.ClusterRoleName(k.Scope)
+	err = k.K8sClient.CreateClusterRole(ctx, clusterRoleName, leaseLabels, reaper.ClusterRoleRules(k.Scope, k.K8sClient.Namespace()))
+	if err != nil && !errors.Is(err, k8s.ErrClusterRoleAlreadyExists) {
+		return ErrCannotCreateReaperClusterRole.Wrap(err)
+	}
+	err = k.K8sClient.CreateClusterRoleBindingOwnedByRole(ctx, clusterRoleName, leaseLabels, clusterRoleName, inst.Name())
+	if err != nil {
+		return ErrCannotCreateReaperClusterRoleBinding.Wrap(err)
+	}
+
+	if err := inst.Execution().Start(ctx); err != nil {
+		return ErrCannotStartInstance.Wrap(err)
+	}
+
+	return nil
+}
+
+// startTimeoutHandler starts an instance that deletes all resources with the scope with kubectl once the timeout has passed
+func (k *Knuu) startTimeoutHandler(ctx context.Context, timeout time.Duration, timeoutHandlerName string) error {
 	inst, err := k.NewInstance(timeoutHandlerName)
 	if err != nil {
 		return ErrCannotCreateInstance.Wrap(err)
@@ -184,6 +392,14 @@ func validateOptions(opts Options) error {
 		k8s.SanitizeName(opts.Scope) != opts.K8sClient.Namespace() {
 		return ErrScopeMismatch.WithParams(opts.Scope, opts.K8sClient.Namespace())
 	}
+
+	if opts.DryRun && opts.K8sClient != nil {
+		return ErrDryRunWithK8sClient
+	}
+	// the proxy needs a cluster to get its endpoint
+	if opts.DryRun && opts.ProxyEnabled {
+		return ErrDryRunWithProxy
+	}
 	return nil
 }
 
@@ -201,6 +417,14 @@ func setDefaults(ctx context.Context, k *Knuu) error {
 	}
 	k.Scope = k8s.SanitizeName(k.Scope)
 
+	if k.K8sClient == nil && k.dryRun {
+		var err error
+		k.K8sClient, err = k8s.NewDryRunClient(ctx, k.Scope, k.Logger)
+		if err != nil {
+			return ErrCannotInitializeK8s.Wrap(err)
+		}
+	}
+
 	if k.K8sClient == nil {
 		var err error
 		k.K8sClient, err = k8s.NewClient(ctx, k.Scope, k.Logger)
@@ -209,12 +433,18 @@ func setDefaults(ctx context.Context, k *Knuu) error {
 		}
 	}
 
+	if k.ImageBuilder == nil && k.dryRun {
+		k.ImageBuilder = dryRunBuilder{}
+	}
+
 	if k.ImageBuilder == nil {
 		k.ImageBuilder = &kaniko.Kaniko{
 			SystemDependencies: k.SystemDependencies,
 		}
 	}
 
+	// start recording the timeline before any instance is created
+	k.Timeline()
 	return nil
 }
 
@@ -8,13 +8,18 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	appv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/celestiaorg/knuu/pkg/builder/kaniko"
 	"github.com/celestiaorg/knuu/pkg/k8s"
+	"github.com/celestiaorg/knuu/pkg/k8s/fake"
 	"github.com/celestiaorg/knuu/pkg/minio"
+	"github.com/celestiaorg/knuu/pkg/reaper"
+	"github.com/celestiaorg/knuu/pkg/system"
 )
 
 const (
@@ -46,14 +51,44 @@ func (m *mockK8s) CreateRoleBinding(ctx context.Context, name string, labels map
 	return nil
 }
 
+func (m *mockK8s) CreateClusterRole(ctx context.Context, name string, labels map[string]string, policyRules []rbacv1.PolicyRule) error {
+	return nil
+}
+
+func (m *mockK8s) CreateClusterRoleBinding(ctx context.Context, name string, labels map[string]string, clusterRole, serviceAccount string) error {
+	return nil
+}
+
+func (m *mockK8s) CreateClusterRoleBindingOwnedByRole(ctx context.Context, name string, labels map[string]string, clusterRole, serviceAccount string) error {
+	return nil
+}
+
 func (m *mockK8s) CreateReplicaSet(ctx context.Context, rsConfig k8s.ReplicaSetConfig, init bool) (*appv1.ReplicaSet, error) {
 	return &appv1.ReplicaSet{}, nil
 }
 
+func (m *mockK8s) CreateLease(ctx context.Context, name string, labels map[string]string, duration time.Duration) (*coordinationv1.Lease, error) {
+	return &coordinationv1.Lease{}, nil
+}
+
 func (m *mockK8s) IsReplicaSetRunning(ctx context.Context, name string) (bool, error) {
 	return true, nil
 }
 
+// newTestKnuu returns a knuu object of the scope that runs on a fake client
+func newTestKnuu(t *testing.T, scope string) (*Knuu, *fake.Client) {
+	t.Helper()
+	client, err := fake.NewClient(context.Background(), scope, nil)
+	require.NoError(t, err)
+	return &Knuu{
+		SystemDependencies: &system.SystemDependencies{
+			K8sClient: client,
+			Logger:    logrus.New(),
+			Scope:     scope,
+		},
+	}, client
+}
+
 func TestNew(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
 	defer cancel()
@@ -200,3 +235,66 @@ func TestValidateOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeoutHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	k, client := newTestKnuu(t, "heartbeat-test")
+	assert.ErrorIs(t, k.ExtendTimeout(ctx, time.Hour), ErrExtendingTimeoutWithoutReaper)
+	k.reaperImage = reaper.DefaultImage
+
+	const timeout = 300 * time.Millisecond
+	_, err := client.CreateLease(ctx, reaper.LeaseName, nil, timeout)
+	require.NoError(t, err)
+	renewTime := func() time.Time {
+		lease, err := client.GetLease(ctx, reaper.LeaseName)
+		require.NoError(t, err)
+		return lease.Spec.RenewTime.Time
+	}
+	created := renewTime()
+
+	k.startHeartbeat(timeout)
+	assert.Eventually(t, func() bool {
+		return renewTime().After(created)
+	}, time.Second, 10*time.Millisecond, "the heartbeat should renew the lease")
+
+	// an extended deadline is not shortened by the heartbeat
+	require.NoError(t, k.ExtendTimeout(ctx, time.Hour))
+	extended := renewTime()
+	time.Sleep(2 * timeout / heartbeatPeriods)
+	assert.Equal(t, extended, renewTime())
+
+	k.stopTimeoutHeartbeat()
+	_, err = client.RenewLease(ctx, reaper.LeaseName, timeout)
+	require.NoError(t, err)
+	stopped := renewTime()
+	time.Sleep(2 * timeout / heartbeatPeriods)
+	assert.Equal(t, stopped, renewTime(), "the lease should not be renewed once the heartbeat is stopped")
+}
+
+func TestTimeoutHeartbeatOutlivesContext(t *testing.T) {
+	client, err := fake.NewClient(context.Background(), "heartbeat-ctx-test", nil)
+	require.NoError(t, err)
+
+	const timeout = 300 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	k, err := New(ctx, Options{
+		K8sClient:   client,
+		Logger:      logrus.New(),
+		Timeout:     timeout,
+		ReaperImage: reaper.DefaultImage,
+	})
+	require.NoError(t, err)
+	// the context of New is commonly done once the test is set up
+	cancel()
+
+	lease, err := client.GetLease(context.Background(), reaper.LeaseName)
+	require.NoError(t, err)
+	created := lease.Spec.RenewTime.Time
+	assert.Eventually(t, func() bool {
+		lease, err := client.GetLease(context.Background(), reaper.LeaseName)
+		require.NoError(t, err)
+		return lease.Spec.RenewTime.After(created)
+	}, time.Second, 10*time.Millisecond, "the heartbeat should keep renewing the lease")
+
+	k.stopTimeoutHeartbeat()
+}
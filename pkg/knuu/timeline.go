@@ -0,0 +1,26 @@
+package knuu
+
+import "github.com/celestiaorg/knuu/pkg/instance"
+
+// OnStateChange registers a function that is called on each state transition
+// of the instances, and their sidecars, created by this knuu object.
+// The function is called in the goroutine that changes the state, so it should return quickly.
+func (k *Knuu) OnStateChange(observer instance.StateObserver) {
+	k.AddStateObserver(func(transition interface{}) {
+		if t, ok := transition.(instance.StateTransition); ok {
+			observer(t)
+		}
+	})
+}
+
+// Timeline returns the timeline of the state transitions of the instances of the scope.
+// It can be exported with its WriteJSON method, e.g. to see how long each instance took to start.
+func (k *Knuu) Timeline() *instance.Timeline {
+	k.timelineMu.Lock()
+	defer k.timelineMu.Unlock()
+	if k.timeline == nil {
+		k.timeline = instance.NewTimeline()
+		k.OnStateChange(k.timeline.Record)
+	}
+	return k.timeline
+}
@@ -0,0 +1,72 @@
+package knuu
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/knuu/pkg/instance"
+	"github.com/celestiaorg/knuu/pkg/system"
+)
+
+func TestStateTransitions(t *testing.T) {
+	k := &Knuu{
+		SystemDependencies: &system.SystemDependencies{
+			Logger: logrus.New(),
+			Scope:  "test",
+		},
+	}
+	timeline := k.Timeline()
+
+	var global []instance.StateTransition
+	k.OnStateChange(func(tr instance.StateTransition) {
+		global = append(global, tr)
+	})
+
+	validator, err := k.NewInstance("validator")
+	require.NoError(t, err)
+	var local []instance.StateTransition
+	validator.OnStateChange(func(tr instance.StateTransition) {
+		local = append(local, tr)
+	})
+
+	bridge, err := k.NewInstance("bridge")
+	require.NoError(t, err)
+
+	validator.SetState(instance.StatePreparing)
+	// setting the same state again is not a transition
+	validator.SetState(instance.StatePreparing)
+	bridge.SetState(instance.StatePreparing)
+	validator.SetState(instance.StateCommitted)
+	validator.SetState(instance.StateStarted)
+
+	require.Len(t, local, 3)
+	assert.Equal(t, instance.StateNone, local[0].From)
+	assert.Equal(t, instance.StatePreparing, local[0].To)
+	assert.Equal(t, instance.StateCommitted, local[2].From)
+	assert.Equal(t, instance.StateStarted, local[2].To)
+	assert.Same(t, validator, local[2].Instance)
+	assert.False(t, local[2].Time.Before(local[0].Time))
+
+	require.Len(t, global, 4)
+	assert.Same(t, bridge, global[1].Instance)
+	assert.Equal(t, global, timeline.Transitions())
+
+	_, ok := timeline.Duration("validator", instance.StateCommitted, instance.StateStarted)
+	assert.True(t, ok)
+	_, ok = timeline.Duration("bridge", instance.StateCommitted, instance.StateStarted)
+	assert.False(t, ok)
+
+	var buf bytes.Buffer
+	require.NoError(t, timeline.WriteJSON(&buf))
+	var entries []map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entries))
+	require.Len(t, entries, 4)
+	assert.Equal(t, "validator", entries[3]["instance"])
+	assert.Equal(t, "Committed", entries[3]["from"])
+	assert.Equal(t, "Started", entries[3]["to"])
+}
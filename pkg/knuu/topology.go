@@ -0,0 +1,540 @@
+package knuu
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/celestiaorg/knuu/pkg/instance"
+	"github.com/celestiaorg/knuu/pkg/system"
+)
+
+// defaultFileChown is the owner of the files of a topology that do not set chown
+const defaultFileChown = "0:0"
+
+// TopologySpec describes a whole test network.
+// It can be written in YAML or JSON.
+type TopologySpec struct {
+	Instances []InstanceSpec `yaml:"instances" json:"instances"`
+}
+
+// InstanceSpec describes a single instance of a topology.
+// The same structure is used to describe sidecars, except that
+// sidecars cannot have sidecars themselves nor be started on their own.
+type InstanceSpec struct {
+	Name    string            `yaml:"name" json:"name"`
+	Image   string            `yaml:"image" json:"image"`
+	Command []string          `yaml:"command,omitempty" json:"command,omitempty"`
+	Args    []string          `yaml:"args,omitempty" json:"args,omitempty"`
+	Env     map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
+	Ports   PortsSpec         `yaml:"ports,omitempty" json:"ports,omitempty"`
+	Files   []FileSpec        `yaml:"files,omitempty" json:"files,omitempty"`
+	Volumes []VolumeSpec      `yaml:"volumes,omitempty" json:"volumes,omitempty"`
+	// Mounts are the named volumes of the instance that a sidecar mounts, it can only be set for sidecars.
+	Mounts       []MountSpec    `yaml:"mounts,omitempty" json:"mounts,omitempty"`
+	Resources    ResourcesSpec  `yaml:"resources,omitempty" json:"resources,omitempty"`
+	Privileged   bool           `yaml:"privileged,omitempty" json:"privileged,omitempty"`
+	Capabilities []string       `yaml:"capabilities,omitempty" json:"capabilities,omitempty"`
+	Sidecars     []InstanceSpec `yaml:"sidecars,omitempty" json:"sidecars,omitempty"`
+	// DependsOn lists the instances that have to meet a condition before this one is started.
+	DependsOn []DependencySpec `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	// Start defaults to true; set it to false to only build and commit the instance.
+	Start *bool `yaml:"start,omitempty" json:"start,omitempty"`
+}
+
+// DependencySpec describes an instance that has to meet a condition before the instance depending on it is started.
+// The condition is that the instance is running, unless a log line or a tcp port is set.
+type DependencySpec struct {
+	Instance string `yaml:"instance" json:"instance"`
+	// LogLine waits for a line of the logs of the instance to contain the string
+	LogLine string `yaml:"logLine,omitempty" json:"logLine,omitempty"`
+	// TCPPort waits for the port of the instance to accept connections, checked from inside its pod with nc or bash
+	TCPPort int `yaml:"tcpPort,omitempty" json:"tcpPort,omitempty"`
+}
+
+type PortsSpec struct {
+	TCP []int `yaml:"tcp,omitempty" json:"tcp,omitempty"`
+	UDP []int `yaml:"udp,omitempty" json:"udp,omitempty"`
+}
+
+// FileSpec describes a file or a folder to be added to an instance.
+// Relative sources are resolved against the directory of the topology file.
+// Chown is the owner of the file in the user:group format, root if it is not set.
+type FileSpec struct {
+	Src   string `yaml:"src" json:"src"`
+	Dest  string `yaml:"dest" json:"dest"`
+	Chown string `yaml:"chown,omitempty" json:"chown,omitempty"`
+}
+
+// VolumeSpec describes a volume of an instance, the name is only needed to mount the volume in a sidecar
+type VolumeSpec struct {
+	Name         string `yaml:"name,omitempty" json:"name,omitempty"`
+	Path         string `yaml:"path" json:"path"`
+	Size         string `yaml:"size" json:"size"`
+	Owner        *int64 `yaml:"owner,omitempty" json:"owner,omitempty"`
+	StorageClass string `yaml:"storageClass,omitempty" json:"storageClass,omitempty"`
+	AccessMode   string `yaml:"accessMode,omitempty" json:"accessMode,omitempty"`
+}
+
+// MountSpec describes a named volume of an instance that is mounted in one of its sidecars
+type MountSpec struct {
+	Volume   string `yaml:"volume" json:"volume"`
+	Path     string `yaml:"path" json:"path"`
+	ReadOnly bool   `yaml:"readOnly,omitempty" json:"readOnly,omitempty"`
+}
+
+type ResourcesSpec struct {
+	CPU    string     `yaml:"cpu,omitempty" json:"cpu,omitempty"`
+	Memory MemorySpec `yaml:"memory,omitempty" json:"memory,omitempty"`
+}
+
+type MemorySpec struct {
+	Request string `yaml:"request,omitempty" json:"request,omitempty"`
+	Limit   string `yaml:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// Topology holds the instances created from a TopologySpec
+type Topology struct {
+	instances map[string]*instance.Instance
+	// order keeps the instances in the order they are defined in the spec
+	order []string
+}
+
+// ParseTopology parses a YAML or JSON topology and validates it
+func ParseTopology(data []byte) (*TopologySpec, error) {
+	spec := &TopologySpec{}
+	if err := yaml.UnmarshalStrict(data, spec); err != nil {
+		return nil, ErrParsingTopology.Wrap(err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// Validate checks that the topology is consistent
+func (t *TopologySpec) Validate() error {
+	if len(t.Instances) == 0 {
+		return ErrTopologyHasNoInstances
+	}
+
+	names := make(map[string]struct{}, len(t.Instances))
+	for _, is := range t.Instances {
+		if err := is.validate(); err != nil {
+			return err
+		}
+		if len(is.Mounts) != 0 {
+			return ErrTopologyMountsNotSidecar.WithParams(is.Name)
+		}
+		volumeNames := make(map[string]struct{}, len(is.Volumes))
+		for _, v := range is.Volumes {
+			volumeNames[v.Name] = struct{}{}
+		}
+		if _, ok := names[is.Name]; ok {
+			return ErrTopologyDuplicateInstance.WithParams(is.Name)
+		}
+		names[is.Name] = struct{}{}
+
+		sidecarNames := make(map[string]struct{}, len(is.Sidecars))
+		for _, sc := range is.Sidecars {
+			if err := sc.validate(); err != nil {
+				return err
+			}
+			if len(sc.Sidecars) != 0 {
+				return ErrTopologyNestedSidecars.WithParams(sc.Name, is.Name)
+			}
+			if sc.Start != nil {
+				return ErrTopologySidecarStart.WithParams(sc.Name, is.Name)
+			}
+			if len(sc.DependsOn) != 0 {
+				return ErrTopologySidecarDependsOn.WithParams(sc.Name, is.Name)
+			}
+			for _, m := range sc.Mounts {
+				if _, ok := volumeNames[m.Volume]; !ok || m.Volume == "" {
+					return ErrTopologyMountNotFound.WithParams(sc.Name, is.Name, m.Volume)
+				}
+			}
+			if _, ok := sidecarNames[sc.Name]; ok {
+				return ErrTopologyDuplicateInstance.WithParams(is.Name + "/" + sc.Name)
+			}
+			sidecarNames[sc.Name] = struct{}{}
+		}
+	}
+	return t.validateDependencies()
+}
+
+// validateDependencies checks that the instances depend on started instances of the topology, without cycles
+func (t *TopologySpec) validateDependencies() error {
+	specs := make(map[string]*InstanceSpec, len(t.Instances))
+	for n := range t.Instances {
+		specs[t.Instances[n].Name] = &t.Instances[n]
+	}
+	for _, is := range t.Instances {
+		for _, d := range is.DependsOn {
+			dep, ok := specs[d.Instance]
+			if !ok {
+				return ErrTopologyDependencyNotFound.WithParams(is.Name, d.Instance)
+			}
+			if !dep.shouldStart() {
+				return ErrTopologyDependencyNotStarted.WithParams(is.Name, d.Instance)
+			}
+			if d.LogLine != "" && d.TCPPort != 0 {
+				return ErrTopologyInvalidDependency.WithParams(is.Name, d.Instance)
+			}
+		}
+	}
+
+	// the instances are visited depth first, a dependency on an instance being visited closes a cycle
+	const (
+		visiting = iota + 1
+		visited
+	)
+	marks := make(map[string]int, len(t.Instances))
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		path = append(path, name)
+		switch marks[name] {
+		case visited:
+			return nil
+		case visiting:
+			for n := range path {
+				if path[n] == name {
+					return ErrTopologyDependencyCycle.WithParams(strings.Join(path[n:], " -> "))
+				}
+			}
+		}
+		marks[name] = visiting
+		for _, d := range specs[name].DependsOn {
+			if err := visit(d.Instance, path); err != nil {
+				return err
+			}
+		}
+		marks[name] = visited
+		return nil
+	}
+	for _, is := range t.Instances {
+		if err := visit(is.Name, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// condition returns the condition the dependency waits for
+func (d DependencySpec) condition() instance.Condition {
+	switch {
+	case d.LogLine != "":
+		return instance.ConditionLogLine(d.LogLine)
+	case d.TCPPort != 0:
+		return instance.ConditionProbe(&v1.Probe{
+			ProbeHandler: v1.ProbeHandler{
+				TCPSocket: &v1.TCPSocketAction{Port: intstr.FromInt32(int32(d.TCPPort))},
+			},
+		})
+	}
+	return instance.ConditionRunning()
+}
+
+func (is *InstanceSpec) validate() error {
+	if is.Name == "" {
+		return ErrTopologyInstanceNameEmpty
+	}
+	if is.Image == "" {
+		return ErrTopologyImageEmpty.WithParams(is.Name)
+	}
+	for _, f := range is.Files {
+		if f.Src == "" || f.Dest == "" {
+			return ErrTopologyInvalidFile.WithParams(is.Name)
+		}
+		if f.Chown != "" {
+			user, group, ok := strings.Cut(f.Chown, ":")
+			if !ok || user == "" || group == "" || strings.Contains(group, ":") {
+				return ErrTopologyInvalidChown.WithParams(f.Chown, f.Dest, is.Name)
+			}
+		}
+	}
+	for _, v := range is.Volumes {
+		if v.Path == "" {
+			return ErrTopologyInvalidVolume.WithParams(is.Name)
+		}
+		if _, err := resource.ParseQuantity(v.Size); err != nil {
+			return ErrTopologyInvalidQuantity.WithParams(v.Size, is.Name).Wrap(err)
+		}
+	}
+	for _, q := range []string{is.Resources.CPU, is.Resources.Memory.Request, is.Resources.Memory.Limit} {
+		if q == "" {
+			continue
+		}
+		if _, err := resource.ParseQuantity(q); err != nil {
+			return ErrTopologyInvalidQuantity.WithParams(q, is.Name).Wrap(err)
+		}
+	}
+	return nil
+}
+
+func (is *InstanceSpec) shouldStart() bool {
+	return is.Start == nil || *is.Start
+}
+
+// LoadTopology reads the topology file at the given path,
+// creates all the instances described in it and starts them, see ApplyTopology.
+// Relative file paths in the topology are resolved against the directory of the topology file.
+func (k *Knuu) LoadTopology(ctx context.Context, path string) (*Topology, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, ErrReadingTopologyFile.WithParams(path).Wrap(err)
+	}
+
+	spec, err := ParseTopology(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return k.ApplyTopology(ctx, spec, filepath.Dir(path))
+}
+
+// ApplyTopology creates all the instances described in the given spec and starts them.
+// An instance is started once its dependencies meet their conditions,
+// the instances that do not depend on each other are started in parallel.
+// baseDir is used to resolve relative file paths.
+// If an instance can not be created or started, the returned topology holds the instances created so far
+// along with the error, so that they can be inspected or destroyed.
+func (k *Knuu) ApplyTopology(ctx context.Context, spec *TopologySpec, baseDir string) (*Topology, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	t := &Topology{
+		instances: make(map[string]*instance.Instance, len(spec.Instances)),
+		order:     make([]string, 0, len(spec.Instances)),
+	}
+
+	for _, is := range spec.Instances {
+		inst, err := k.NewInstance(is.Name)
+		if err != nil {
+			return t, ErrCreatingTopologyInstance.WithParams(is.Name).Wrap(err)
+		}
+		// the instance is part of the topology as soon as it is created, so it is returned if it fails to be set up
+		t.instances[is.Name] = inst
+		t.order = append(t.order, is.Name)
+
+		if err := is.apply(ctx, inst, baseDir); err != nil {
+			return t, ErrCreatingTopologyInstance.WithParams(is.Name).Wrap(err)
+		}
+
+		for _, sc := range is.Sidecars {
+			sidecar := &topologySidecar{spec: sc, baseDir: baseDir}
+			if err := inst.Sidecars().Add(ctx, sidecar); err != nil {
+				return t, ErrAddingTopologySidecar.WithParams(sc.Name, is.Name).Wrap(err)
+			}
+		}
+	}
+
+	group := instance.NewGroup()
+	for _, is := range spec.Instances {
+		for _, d := range is.DependsOn {
+			if err := t.instances[is.Name].Execution().DependsOn(t.instances[d.Instance], d.condition()); err != nil {
+				return t, ErrAddingTopologyDependency.WithParams(is.Name, d.Instance).Wrap(err)
+			}
+		}
+		if is.shouldStart() {
+			group.Add(t.instances[is.Name])
+		}
+	}
+
+	if err := group.Start(ctx); err != nil {
+		return t, ErrStartingTopology.Wrap(err)
+	}
+	k.Logger.WithFields(logrus.Fields{
+		"instances": len(group.Instances()),
+	}).Debug("topology started")
+
+	return t, nil
+}
+
+// apply builds and commits the instance and then configures it.
+// Everything apart from the image is set after the commit,
+// so that no custom image has to be built for the instance.
+func (is *InstanceSpec) apply(ctx context.Context, inst *instance.Instance, baseDir string) error {
+	if err := inst.Build().SetImage(ctx, is.Image); err != nil {
+		return err
+	}
+	if err := inst.Build().Commit(ctx); err != nil {
+		return err
+	}
+
+	if len(is.Command) != 0 {
+		if err := inst.Build().SetStartCommand(is.Command...); err != nil {
+			return err
+		}
+	}
+	if len(is.Args) != 0 {
+		if err := inst.Build().SetArgs(is.Args...); err != nil {
+			return err
+		}
+	}
+	for key, value := range is.Env {
+		if err := inst.Build().SetEnvironmentVariable(key, value); err != nil {
+			return err
+		}
+	}
+
+	for _, port := range is.Ports.TCP {
+		if err := inst.Network().AddPortTCP(port); err != nil {
+			return err
+		}
+	}
+	for _, port := range is.Ports.UDP {
+		if err := inst.Network().AddPortUDP(port); err != nil {
+			return err
+		}
+	}
+
+	for _, v := range is.Volumes {
+		size := resource.MustParse(v.Size)
+		var owner int64
+		if v.Owner != nil {
+			owner = *v.Owner
+		}
+		err := inst.Storage().AddNamedVolume(v.Name, v.Path, size, instance.VolumeOptions{
+			Owner:        owner,
+			StorageClass: v.StorageClass,
+			AccessMode:   v1.PersistentVolumeAccessMode(v.AccessMode),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, m := range is.Mounts {
+		if err := inst.Storage().MountVolume(m.Volume, m.Path, m.ReadOnly); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range is.Files {
+		src := f.Src
+		if !filepath.IsAbs(src) {
+			src = filepath.Join(baseDir, src)
+		}
+		fi, err := os.Stat(src)
+		if err != nil {
+			return err
+		}
+		chown := f.Chown
+		if chown == "" {
+			chown = defaultFileChown
+		}
+		if fi.IsDir() {
+			err = inst.Storage().AddFolder(src, f.Dest, chown)
+		} else {
+			err = inst.Storage().AddFile(src, f.Dest, chown)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if is.Resources.Memory.Request != "" || is.Resources.Memory.Limit != "" {
+		request, limit := is.Resources.Memory.Request, is.Resources.Memory.Limit
+		if request == "" {
+			request = limit
+		}
+		if limit == "" {
+			limit = request
+		}
+		if err := inst.Resources().SetMemory(resource.MustParse(request), resource.MustParse(limit)); err != nil {
+			return err
+		}
+	}
+	if is.Resources.CPU != "" {
+		if err := inst.Resources().SetCPU(resource.MustParse(is.Resources.CPU)); err != nil {
+			return err
+		}
+	}
+
+	if is.Privileged {
+		if err := inst.Security().SetPrivileged(true); err != nil {
+			return err
+		}
+	}
+	if len(is.Capabilities) != 0 {
+		if err := inst.Security().AddKubernetesCapabilities(is.Capabilities); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Instance returns the instance with the given name
+func (t *Topology) Instance(name string) (*instance.Instance, error) {
+	inst, ok := t.instances[name]
+	if !ok {
+		return nil, ErrTopologyInstanceNotFound.WithParams(name)
+	}
+	return inst, nil
+}
+
+// Instances returns all the instances of the topology
+// in the order they are defined in the spec
+func (t *Topology) Instances() []*instance.Instance {
+	instances := make([]*instance.Instance, 0, len(t.order))
+	for _, name := range t.order {
+		instances = append(instances, t.instances[name])
+	}
+	return instances
+}
+
+// Names returns the names of the instances in the order they are defined in the spec
+func (t *Topology) Names() []string {
+	return append([]string(nil), t.order...)
+}
+
+// topologySidecar is a generic sidecar created from an InstanceSpec
+type topologySidecar struct {
+	spec     InstanceSpec
+	baseDir  string
+	instance *instance.Instance
+}
+
+var _ instance.SidecarManager = (*topologySidecar)(nil)
+
+func (s *topologySidecar) Initialize(ctx context.Context, namePrefix string, sysDeps *system.SystemDependencies) error {
+	var err error
+	s.instance, err = instance.New(namePrefix+"-"+s.spec.Name, sysDeps)
+	if err != nil {
+		return err
+	}
+	s.instance.Sidecars().SetIsSidecar(true)
+	return s.spec.apply(ctx, s.instance, s.baseDir)
+}
+
+func (s *topologySidecar) PreStart(ctx context.Context) error {
+	if s.instance == nil {
+		return ErrTopologySidecarNotInitialized.WithParams(s.spec.Name)
+	}
+	return nil
+}
+
+func (s *topologySidecar) Instance() *instance.Instance {
+	return s.instance
+}
+
+func (s *topologySidecar) Clone(namePrefix string) (instance.SidecarManager, error) {
+	clone, err := s.instance.CloneWithName(namePrefix + "-" + s.spec.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &topologySidecar{
+		spec:     s.spec,
+		baseDir:  s.baseDir,
+		instance: clone,
+	}, nil
+}
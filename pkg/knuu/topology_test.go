@@ -0,0 +1,410 @@
+package knuu
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/celestiaorg/knuu/pkg/instance"
+)
+
+const validTopology = `
+instances:
+  - name: validator
+    image: ghcr.io/celestiaorg/celestia-app:v1.0.0
+    command: ["celestia-appd", "start"]
+    args: ["--home", "/home/celestia"]
+    env:
+      CHAIN_ID: test
+    ports:
+      tcp: [26656, 26657]
+    files:
+      - src: genesis.json
+        dest: /home/celestia/config/genesis.json
+        chown: "10001:10001"
+    volumes:
+      - path: /home/celestia
+        size: 1Gi
+        owner: 10001
+    resources:
+      cpu: 500m
+      memory:
+        request: 1Gi
+        limit: 2Gi
+    sidecars:
+      - name: exporter
+        image: prom/node-exporter:latest
+        ports:
+          tcp: [9100]
+  - name: bridge
+    image: ghcr.io/celestiaorg/celestia-node:v0.14.0
+    start: false
+`
+
+func TestParseTopology(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		expectedError error
+	}{
+		{
+			name: "Valid YAML topology",
+			data: validTopology,
+		},
+		{
+			name: "Valid JSON topology",
+			data: `{"instances": [{"name": "a", "image": "alpine", "ports": {"udp": [53]}}]}`,
+		},
+		{
+			name:          "Empty topology",
+			data:          `instances: []`,
+			expectedError: ErrTopologyHasNoInstances,
+		},
+		{
+			name:          "Unknown field",
+			data:          "instances:\n  - name: a\n    image: alpine\n    imagee: typo\n",
+			expectedError: ErrParsingTopology,
+		},
+		{
+			name:          "Missing name",
+			data:          "instances:\n  - image: alpine\n",
+			expectedError: ErrTopologyInstanceNameEmpty,
+		},
+		{
+			name:          "Missing image",
+			data:          "instances:\n  - name: a\n",
+			expectedError: ErrTopologyImageEmpty,
+		},
+		{
+			name:          "Duplicate instance",
+			data:          "instances:\n  - name: a\n    image: alpine\n  - name: a\n    image: alpine\n",
+			expectedError: ErrTopologyDuplicateInstance,
+		},
+		{
+			name:          "Invalid volume size",
+			data:          "instances:\n  - name: a\n    image: alpine\n    volumes:\n      - path: /data\n        size: lots\n",
+			expectedError: ErrTopologyInvalidQuantity,
+		},
+		{
+			name:          "Mounts set on instance",
+			data:          "instances:\n  - name: a\n    image: alpine\n    mounts:\n      - volume: data\n        path: /data\n",
+			expectedError: ErrTopologyMountsNotSidecar,
+		},
+		{
+			name: "Sidecar mounts unknown volume",
+			data: `
+instances:
+  - name: a
+    image: alpine
+    volumes:
+      - path: /data
+        size: 1Gi
+    sidecars:
+      - name: b
+        image: alpine
+        mounts:
+          - volume: data
+            path: /data
+`,
+			expectedError: ErrTopologyMountNotFound,
+		},
+		{
+			name:          "Invalid file",
+			data:          "instances:\n  - name: a\n    image: alpine\n    files:\n      - src: a.txt\n",
+			expectedError: ErrTopologyInvalidFile,
+		},
+		{
+			name:          "Invalid file chown",
+			data:          "instances:\n  - name: a\n    image: alpine\n    files:\n      - src: a.txt\n        dest: /a.txt\n        chown: \"10001\"\n",
+			expectedError: ErrTopologyInvalidChown,
+		},
+		{
+			name: "Nested sidecars",
+			data: `
+instances:
+  - name: a
+    image: alpine
+    sidecars:
+      - name: b
+        image: alpine
+        sidecars:
+          - name: c
+            image: alpine
+`,
+			expectedError: ErrTopologyNestedSidecars,
+		},
+		{
+			name: "Start set on sidecar",
+			data: `
+instances:
+  - name: a
+    image: alpine
+    sidecars:
+      - name: b
+        image: alpine
+        start: true
+`,
+			expectedError: ErrTopologySidecarStart,
+		},
+		{
+			name:          "Unknown dependency",
+			data:          "instances:\n  - name: a\n    image: alpine\n    dependsOn:\n      - instance: b\n",
+			expectedError: ErrTopologyDependencyNotFound,
+		},
+		{
+			name: "Dependency not started",
+			data: `
+instances:
+  - name: a
+    image: alpine
+    start: false
+  - name: b
+    image: alpine
+    dependsOn:
+      - instance: a
+`,
+			expectedError: ErrTopologyDependencyNotStarted,
+		},
+		{
+			name: "Dependency with two conditions",
+			data: `
+instances:
+  - name: a
+    image: alpine
+  - name: b
+    image: alpine
+    dependsOn:
+      - instance: a
+        logLine: ready
+        tcpPort: 26657
+`,
+			expectedError: ErrTopologyInvalidDependency,
+		},
+		{
+			name: "Dependency cycle",
+			data: `
+instances:
+  - name: a
+    image: alpine
+    dependsOn:
+      - instance: c
+  - name: b
+    image: alpine
+    dependsOn:
+      - instance: a
+  - name: c
+    image: alpine
+    dependsOn:
+      - instance: b
+`,
+			expectedError: ErrTopologyDependencyCycle,
+		},
+		{
+			name: "DependsOn set on sidecar",
+			data: `
+instances:
+  - name: a
+    image: alpine
+    sidecars:
+      - name: b
+        image: alpine
+        dependsOn:
+          - instance: a
+`,
+			expectedError: ErrTopologySidecarDependsOn,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			spec, err := ParseTopology([]byte(tc.data))
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, spec.Instances)
+		})
+	}
+}
+
+func TestParseTopologyFields(t *testing.T) {
+	spec, err := ParseTopology([]byte(validTopology))
+	require.NoError(t, err)
+	require.Len(t, spec.Instances, 2)
+
+	v := spec.Instances[0]
+	assert.Equal(t, "validator", v.Name)
+	assert.Equal(t, []string{"celestia-appd", "start"}, v.Command)
+	assert.Equal(t, map[string]string{"CHAIN_ID": "test"}, v.Env)
+	assert.Equal(t, []int{26656, 26657}, v.Ports.TCP)
+	require.Len(t, v.Volumes, 1)
+	require.NotNil(t, v.Volumes[0].Owner)
+	assert.Equal(t, int64(10001), *v.Volumes[0].Owner)
+	assert.Equal(t, "2Gi", v.Resources.Memory.Limit)
+	require.Len(t, v.Sidecars, 1)
+	assert.Equal(t, "exporter", v.Sidecars[0].Name)
+	assert.True(t, v.shouldStart())
+
+	assert.False(t, spec.Instances[1].shouldStart())
+}
+
+func TestLoadTopologyFileNotFound(t *testing.T) {
+	k := &Knuu{}
+	_, err := k.LoadTopology(context.Background(), filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorIs(t, err, ErrReadingTopologyFile)
+}
+
+func TestLoadTopologyInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "topology.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("instances: []"), 0644))
+
+	k := &Knuu{}
+	_, err := k.LoadTopology(context.Background(), path)
+	assert.ErrorIs(t, err, ErrTopologyHasNoInstances)
+}
+
+func TestTopologyInstanceNotFound(t *testing.T) {
+	topology := &Topology{}
+	_, err := topology.Instance("missing")
+	assert.ErrorIs(t, err, ErrTopologyInstanceNotFound)
+}
+
+func TestApplyTopologyDependencies(t *testing.T) {
+	ctx := context.Background()
+	k, _ := newTestKnuu(t, "topology-test")
+	timeline := k.Timeline()
+
+	spec, err := ParseTopology([]byte(`
+instances:
+  - name: light
+    image: alpine
+    dependsOn:
+      - instance: bridge
+  - name: bridge
+    image: alpine
+    dependsOn:
+      - instance: core
+        logLine: fake logs
+  - name: core
+    image: alpine
+`))
+	require.NoError(t, err)
+	topology, err := k.ApplyTopology(ctx, spec, t.TempDir())
+	require.NoError(t, err)
+
+	var started []string
+	for _, tr := range timeline.Transitions() {
+		if tr.To == instance.StateStarted {
+			started = append(started, tr.Instance.Name())
+		}
+	}
+	assert.Equal(t, []string{"core", "bridge", "light"}, started)
+
+	bridge, err := topology.Instance("bridge")
+	require.NoError(t, err)
+	require.Len(t, bridge.Execution().Dependencies(), 1)
+	assert.Equal(t, "core", bridge.Execution().Dependencies()[0].Instance.Name())
+	assert.Equal(t, "log line 'fake logs'", bridge.Execution().Dependencies()[0].Condition.String())
+}
+
+func TestApplyTopologyVolumes(t *testing.T) {
+	ctx := context.Background()
+	k, client := newTestKnuu(t, "topology-test")
+
+	spec, err := ParseTopology([]byte(`
+instances:
+  - name: validator
+    image: alpine
+    volumes:
+      - name: data
+        path: /home/celestia
+        size: 1Gi
+        owner: 10001
+      - path: /keys
+        size: 1Mi
+        storageClass: fast
+        accessMode: ReadWriteOncePod
+    sidecars:
+      - name: exporter
+        image: alpine
+        mounts:
+          - volume: data
+            path: /data
+            readOnly: true
+`))
+	require.NoError(t, err)
+	_, err = k.ApplyTopology(ctx, spec, t.TempDir())
+	require.NoError(t, err)
+
+	// each volume has its own claim
+	pvcs, err := client.ListPersistentVolumeClaims(ctx, map[string]string{"knuu.sh/name": "validator"})
+	require.NoError(t, err)
+	claims := make(map[string]v1.PersistentVolumeClaim, len(pvcs))
+	for _, pvc := range pvcs {
+		claims[pvc.Name] = pvc
+	}
+	require.Len(t, claims, 2)
+	require.Contains(t, claims, "validator-data")
+	require.Contains(t, claims, "validator-volume-1")
+	keys := claims["validator-volume-1"]
+	require.NotNil(t, keys.Spec.StorageClassName)
+	assert.Equal(t, "fast", *keys.Spec.StorageClassName)
+	assert.Equal(t, []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod}, keys.Spec.AccessModes)
+
+	rs, err := client.Clientset().AppsV1().ReplicaSets(client.Namespace()).Get(ctx, "validator", metav1.GetOptions{})
+	require.NoError(t, err)
+	podSpec := rs.Spec.Template.Spec
+	require.Len(t, podSpec.Containers, 2)
+	assert.Equal(t, []v1.VolumeMount{
+		{Name: "validator-data", MountPath: "/home/celestia"},
+		{Name: "validator-volume-1", MountPath: "/keys"},
+	}, podSpec.Containers[0].VolumeMounts)
+	assert.Equal(t, []v1.VolumeMount{
+		{Name: "validator-data", MountPath: "/data", ReadOnly: true},
+	}, podSpec.Containers[1].VolumeMounts)
+	require.Len(t, podSpec.InitContainers, 1)
+	assert.Equal(t, []v1.VolumeMount{
+		{Name: "validator-data", MountPath: "/knuu/home/celestia"},
+		{Name: "validator-volume-1", MountPath: "/knuu/keys"},
+	}, podSpec.InitContainers[0].VolumeMounts)
+}
+
+func TestApplyTopologyFiles(t *testing.T) {
+	ctx := context.Background()
+	k, _ := newTestKnuu(t, "topology-test")
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "genesis.json"), []byte("{}"), 0o644))
+
+	// the file without chown is owned by root, the missing file fails the second instance
+	spec, err := ParseTopology([]byte(`
+instances:
+  - name: validator
+    image: alpine
+    start: false
+    files:
+      - src: genesis.json
+        dest: /home/celestia/config/genesis.json
+  - name: bridge
+    image: alpine
+    start: false
+    files:
+      - src: missing.json
+        dest: /home/celestia/config/genesis.json
+`))
+	require.NoError(t, err)
+	topology, err := k.ApplyTopology(ctx, spec, dir)
+	assert.ErrorIs(t, err, ErrCreatingTopologyInstance)
+	assert.ErrorContains(t, err, "bridge")
+
+	// the instances created before the error are returned, so they can be destroyed
+	require.NotNil(t, topology)
+	assert.Equal(t, []string{"validator", "bridge"}, topology.Names())
+}
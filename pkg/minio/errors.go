@@ -42,4 +42,5 @@ var (
 	ErrMinioFailedToCreatePersistentVolumeClaim = errors.New("MinioFailedToCreatePersistentVolumeClaim", "failed to create PersistentVolumeClaim")
 	ErrMinioClientNotInitialized                = errors.New("MinioClientNotInitialized", "Minio client not initialized")
 	ErrMinioNotInitialized                      = errors.New("MinioNotInitialized", "Minio not initialized")
+	ErrMinioFailedToCreateCredentialsSecret     = errors.New("MinioFailedToCreateCredentialsSecret", "failed to create the Secret with the credentials of Minio")
 )
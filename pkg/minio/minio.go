@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/url"
 	"sync"
 	"time"
 
@@ -29,6 +30,10 @@ const (
 	StorageClassName = "standard" // standard | gp2 | default
 	VolumeClaimName  = "minio-data"
 	VolumeMountPath  = "/data"
+	// CredentialsSecretName is the Secret with the credentials of Minio, the pods sign their downloads with it, see ObjectURL
+	CredentialsSecretName = "minio-credentials"
+	// Region is the region of Minio, the signed requests are made for
+	Region = "us-east-1"
 
 	// The minio service is used internally, so not sure if it is ok to use constant key/secret
 	rootUser     = "minioUser"     // Previously accessKey
@@ -39,6 +44,9 @@ const (
 	pvHostPath           = "/tmp/minio-pv"
 	deploymentAppLabel   = "app"
 	deploymentMinioLabel = "minio"
+	managedByLabel       = "k8s.kubernetes.io/managed-by"
+	managedByLabelValue  = "knuu"
+	scopeLabel           = "knuu.sh/scope"
 	apiServicePortName   = "api"
 	webuiServicePortName = "webui"
 
@@ -153,6 +161,22 @@ func (m *Minio) GetURL(ctx context.Context, minioFilePath, bucketName string) (s
 	return presignedURL.String(), nil
 }
 
+// ObjectURL returns the URL of a Minio file inside the cluster.
+// Unlike GetURL, the URL does not expire, but the requests have to be signed with the credentials
+// of the Secret named CredentialsSecretName, see k8s.VolumeSeed.CredentialsSecret.
+func (m *Minio) ObjectURL(minioFilePath, bucketName string) (string, error) {
+	if m == nil {
+		return "", ErrMinioNotInitialized
+	}
+
+	u := url.URL{
+		Scheme: "http",
+		Host:   fmt.Sprintf("%s:%d", ServiceName, ServiceAPIPort),
+		Path:   "/" + bucketName + "/" + minioFilePath,
+	}
+	return u.String(), nil
+}
+
 func (m *Minio) GetConfigs(ctx context.Context) (*Config, error) {
 	if m == nil {
 		return nil, ErrMinioNotInitialized
@@ -187,6 +211,15 @@ func (m *Minio) deployMinio(ctx context.Context) error {
 		return ErrMinioFailedToBeReadyService.Wrap(err)
 	}
 
+	credentials := map[string][]byte{
+		k8s.S3AccessKeyIDKey:     []byte(rootUser),
+		k8s.S3SecretAccessKeyKey: []byte(rootPassword),
+		k8s.S3RegionKey:          []byte(Region),
+	}
+	if _, err := m.k8sClient.CreateOrUpdateSecret(ctx, CredentialsSecretName, m.labels(), credentials); err != nil {
+		return ErrMinioFailedToCreateCredentialsSecret.Wrap(err)
+	}
+
 	m.Logger.Debug("Minio deployed or updated successfully.")
 	return nil
 }
@@ -199,6 +232,7 @@ func (m *Minio) createOrUpdateDeployment(ctx context.Context) error {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      DeploymentName,
 			Namespace: m.k8sClient.Namespace(),
+			Labels:    m.labels(),
 		},
 		Spec: appsV1.DeploymentSpec{
 			Selector: &metav1.LabelSelector{
@@ -283,6 +317,7 @@ func (m *Minio) createOrUpdateService(ctx context.Context) error {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      ServiceName,
 			Namespace: m.k8sClient.Namespace(),
+			Labels:    m.labels(),
 		},
 		Spec: v1.ServiceSpec{
 			Selector: map[string]string{"app": "minio"},
@@ -444,6 +479,7 @@ func (m *Minio) createPVC(ctx context.Context, pvcName string, storageSize resou
 		_, err = m.k8sClient.Clientset().CoreV1().PersistentVolumes().Create(ctx, &v1.PersistentVolume{
 			ObjectMeta: metav1.ObjectMeta{
 				GenerateName: pvPrefix,
+				Labels:       m.labels(),
 			},
 			Spec: v1.PersistentVolumeSpec{
 				Capacity: v1.ResourceList{
@@ -468,6 +504,7 @@ func (m *Minio) createPVC(ctx context.Context, pvcName string, storageSize resou
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      pvcName,
 			Namespace: m.k8sClient.Namespace(),
+			Labels:    m.labels(),
 		},
 		Spec: v1.PersistentVolumeClaimSpec{
 			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
@@ -487,3 +524,14 @@ func (m *Minio) createPVC(ctx context.Context, pvcName string, storageSize resou
 	m.Logger.WithField("pvc", pvcName).Debug("PersistentVolumeClaim created successfully.")
 	return nil
 }
+
+// labels returns the labels of the resources created for minio.
+// The scope is the namespace of the client, so that the resources are removed with the scope,
+// including the persistent volume that is not removed with the namespace.
+func (m *Minio) labels() map[string]string {
+	return map[string]string{
+		deploymentAppLabel: deploymentMinioLabel,
+		managedByLabel:     managedByLabelValue,
+		scopeLabel:         m.k8sClient.Namespace(),
+	}
+}
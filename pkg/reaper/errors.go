@@ -0,0 +1,24 @@
+package reaper
+
+import (
+	"github.com/celestiaorg/knuu/pkg/errors"
+)
+
+type Error = errors.Error
+
+var (
+	ErrK8sClientNotSet           = errors.New("K8sClientNotSet", "k8s client is not set")
+	ErrScopeNotSet               = errors.New("ScopeNotSet", "scope is not set")
+	ErrGettingLease              = errors.New("GettingLease", "error getting lease '%s'")
+	ErrListingReplicaSets        = errors.New("ListingReplicaSets", "error listing replicaSets of scope '%s'")
+	ErrParsingExpiresAt          = errors.New("ParsingExpiresAt", "error parsing expiration time '%s' of instance '%s'")
+	ErrReapingInstance           = errors.New("ReapingInstance", "error reaping instance '%s'")
+	ErrListingResources          = errors.New("ListingResources", "error listing resources '%s'")
+	ErrDeletingResource          = errors.New("DeletingResource", "error deleting resource '%s/%s'")
+	ErrDeletingNamespace         = errors.New("DeletingNamespace", "error deleting namespace '%s'")
+	ErrInvalidInterval           = errors.New("InvalidInterval", "invalid interval '%s'")
+	ErrEnvironmentVariableNotSet = errors.New("EnvironmentVariableNotSet", "environment variable '%s' is not set")
+	ErrListingStatefulSets       = errors.New("ListingStatefulSets", "error listing statefulSets of scope '%s'")
+	ErrListingDaemonSets         = errors.New("ListingDaemonSets", "error listing daemonSets of scope '%s'")
+	ErrListingJobs               = errors.New("ListingJobs", "error listing jobs of scope '%s'")
+)
@@ -0,0 +1,340 @@
+// Package reaper removes the resources of a knuu scope once it has expired.
+// It runs inside the cluster, next to the instances of the scope, so that
+// the resources are removed even if the test process that created them died.
+package reaper
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/utils/ptr"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+	"github.com/celestiaorg/knuu/pkg/log"
+)
+
+const (
+	// DefaultImage is the image of the reaper, built from cmd/reaper.
+	// It is published by the reaper image workflow when this tag changes on main,
+	// so the tag must be bumped along with changes to the reaper.
+	DefaultImage = "ghcr.io/celestiaorg/knuu-reaper:v0.1.0"
+
+	// LeaseName is the name of the lease that holds the deadline of the scope.
+	// The test process renews it to extend the deadline.
+	LeaseName = "knuu-reaper"
+
+	// clusterRolePrefix is the prefix of the cluster role of the reaper of a scope, and of its binding
+	clusterRolePrefix = "knuu-reaper-"
+
+	// AnnotationExpiresAt is the pod annotation that holds the time (RFC 3339)
+	// after which an instance is removed, regardless of the deadline of the scope.
+	AnnotationExpiresAt = "knuu.sh/expires-at"
+
+	DefaultInterval = 5 * time.Second
+
+	// Environment variables used to configure the reaper binary
+	EnvScope    = "KNUU_REAPER_SCOPE"
+	EnvInterval = "KNUU_REAPER_INTERVAL"
+
+	labelManagedBy = "k8s.kubernetes.io/managed-by"
+	labelScope     = "knuu.sh/scope"
+	labelName      = "knuu.sh/name"
+	labelParent    = "knuu.sh/parent"
+	labelType      = "knuu.sh/type"
+	managedByValue = "knuu"
+	// the reaper runs as the timeout handler instance of the scope
+	reaperType = "TimeoutHandlerInstance"
+)
+
+// namespacedResources are the kinds of resources that knuu creates in the namespace of a scope
+var namespacedResources = []schema.GroupVersionResource{
+	{Group: "apps", Version: "v1", Resource: "deployments"},
+	{Group: "apps", Version: "v1", Resource: "replicasets"},
+	{Group: "apps", Version: "v1", Resource: "statefulsets"},
+	{Group: "apps", Version: "v1", Resource: "daemonsets"},
+	{Group: "batch", Version: "v1", Resource: "jobs"},
+	{Group: "", Version: "v1", Resource: "pods"},
+	{Group: "", Version: "v1", Resource: "services"},
+	{Group: "", Version: "v1", Resource: "configmaps"},
+	{Group: "", Version: "v1", Resource: "secrets"},
+	{Group: "", Version: "v1", Resource: "persistentvolumeclaims"},
+	{Group: "", Version: "v1", Resource: "serviceaccounts"},
+	{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"},
+	{Group: "coordination.k8s.io", Version: "v1", Resource: "leases"},
+	{Group: "traefik.io", Version: "v1alpha1", Resource: "ingressroutes"},
+	{Group: "traefik.io", Version: "v1alpha1", Resource: "middlewares"},
+}
+
+// clusterResources are the kinds of cluster-scoped resources that knuu creates for a scope.
+// They are not removed with the namespace, so they are removed by label by CleanUpClusterResources.
+var clusterResources = []schema.GroupVersionResource{
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterrolebindings"},
+	{Group: "", Version: "v1", Resource: "persistentvolumes"},
+}
+
+// ClusterRoleName returns the name of the cluster role of the reaper of the given scope, which is also the name of its binding
+func ClusterRoleName(scope string) string {
+	return clusterRolePrefix + scope
+}
+
+// ClusterRoleRules returns the rules of the cluster role of the reaper of the given scope.
+// The reaper can only delete the namespace of its scope and its own cluster role,
+// the binding of which is removed along with it; the other cluster-scoped resources are removed by Knuu.CleanUp.
+func ClusterRoleRules(scope, namespace string) []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{
+			APIGroups:     []string{""},
+			Resources:     []string{"namespaces"},
+			ResourceNames: []string{namespace},
+			Verbs:         []string{"get", "delete"},
+		},
+		{
+			APIGroups:     []string{rbacv1.GroupName},
+			Resources:     []string{"clusterroles"},
+			ResourceNames: []string{ClusterRoleName(scope)},
+			Verbs:         []string{"delete"},
+		},
+	}
+}
+
+type Reaper struct {
+	K8sClient k8s.KubeManager
+	Scope     string
+	Interval  time.Duration
+	Logger    *logrus.Logger
+}
+
+// Run checks the deadline of the scope and the expiration time of its instances every interval.
+// Expired instances are removed; once the scope itself has expired, all its resources are removed and Run returns.
+func (r *Reaper) Run(ctx context.Context) error {
+	if err := r.validate(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+	for {
+		expired, err := r.Reconcile(ctx)
+		if err != nil {
+			// errors are most likely transient, so the reaper keeps going
+			r.Logger.WithError(err).Error("reconciling scope")
+		}
+		if expired {
+			r.Logger.WithField("scope", r.Scope).Info("scope expired, cleaning up")
+			return r.CleanUp(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile removes the instances that have expired and reports whether the scope itself has expired
+func (r *Reaper) Reconcile(ctx context.Context) (bool, error) {
+	now := time.Now()
+
+	lease, err := r.K8sClient.GetLease(ctx, LeaseName)
+	if err != nil {
+		// without a lease, nobody can extend the deadline anymore
+		if apierrs.IsNotFound(err) {
+			return true, nil
+		}
+		return false, ErrGettingLease.WithParams(LeaseName).Wrap(err)
+	}
+	if !now.Before(k8s.LeaseExpiry(lease)) {
+		return true, nil
+	}
+
+	return false, r.reapExpiredInstances(ctx, now)
+}
+
+// CleanUp removes all the namespaced resources of the scope, its namespace and the cluster role of the reaper.
+// It is used by the reaper once the scope has expired and by knuu when the test is done.
+// The other cluster-scoped resources of the scope are not removed, see CleanUpClusterResources.
+func (r *Reaper) CleanUp(ctx context.Context) error {
+	if err := r.validate(); err != nil {
+		return err
+	}
+	selector := map[string]string{labelScope: r.Scope}
+
+	// The reaper is removed last, so it can finish the cleanup if it is interrupted
+	isReaper := func(labels map[string]string) bool {
+		return labels[labelType] == reaperType
+	}
+	if err := r.deleteResources(ctx, namespacedResources, r.K8sClient.Namespace(), selector, isReaper); err != nil {
+		return err
+	}
+
+	namespace := r.K8sClient.Namespace()
+	if err := r.K8sClient.DeleteNamespace(ctx, namespace); err != nil {
+		r.Logger.WithError(err).WithField("namespace", namespace).Error("deleting namespace, removing the remaining resources")
+		if err := r.deleteResources(ctx, namespacedResources, namespace, selector, nil); err != nil {
+			return err
+		}
+		return ErrDeletingNamespace.WithParams(namespace).Wrap(err)
+	}
+
+	// the cluster role of the reaper is not removed with the namespace, its binding is removed along with it
+	name := ClusterRoleName(r.Scope)
+	if err := r.K8sClient.DeleteClusterRole(ctx, name); err != nil && !apierrs.IsNotFound(err) {
+		return ErrDeletingResource.WithParams("clusterroles", name).Wrap(err)
+	}
+	return nil
+}
+
+// CleanUpClusterResources removes the cluster-scoped resources of the scope, found by label.
+// The reaper is not allowed to list or delete them, so it is only used by knuu when the test is done.
+func (r *Reaper) CleanUpClusterResources(ctx context.Context) error {
+	if err := r.validate(); err != nil {
+		return err
+	}
+	return r.deleteResources(ctx, clusterResources, "", map[string]string{labelScope: r.Scope}, nil)
+}
+
+// reapExpiredInstances removes the instances whose expiration time is before now
+func (r *Reaper) reapExpiredInstances(ctx context.Context, now time.Time) error {
+	selector := map[string]string{
+		labelManagedBy: managedByValue,
+		labelScope:     r.Scope,
+	}
+	rsList, err := r.K8sClient.ListReplicaSets(ctx, selector)
+	if err != nil {
+		return ErrListingReplicaSets.WithParams(r.Scope).Wrap(err)
+	}
+	ssList, err := r.K8sClient.ListStatefulSets(ctx, selector)
+	if err != nil {
+		return ErrListingStatefulSets.WithParams(r.Scope).Wrap(err)
+	}
+	dsList, err := r.K8sClient.ListDaemonSets(ctx, selector)
+	if err != nil {
+		return ErrListingDaemonSets.WithParams(r.Scope).Wrap(err)
+	}
+	jobList, err := r.K8sClient.ListJobs(ctx, selector)
+	if err != nil {
+		return ErrListingJobs.WithParams(r.Scope).Wrap(err)
+	}
+
+	workloads := make([]metav1.ObjectMeta, 0, len(rsList)+len(ssList)+len(dsList)+len(jobList))
+	for _, rs := range rsList {
+		workloads = append(workloads, metav1.ObjectMeta{Labels: rs.Labels, Annotations: rs.Spec.Template.Annotations})
+	}
+	for _, ss := range ssList {
+		workloads = append(workloads, metav1.ObjectMeta{Labels: ss.Labels, Annotations: ss.Spec.Template.Annotations})
+	}
+	for _, ds := range dsList {
+		workloads = append(workloads, metav1.ObjectMeta{Labels: ds.Labels, Annotations: ds.Spec.Template.Annotations})
+	}
+	for _, job := range jobList {
+		workloads = append(workloads, metav1.ObjectMeta{Labels: job.Labels, Annotations: job.Spec.Template.Annotations})
+	}
+
+	for _, w := range workloads {
+		name := w.Labels[labelName]
+		value, ok := w.Annotations[AnnotationExpiresAt]
+		if !ok || name == "" {
+			continue
+		}
+		expiresAt, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			r.Logger.WithError(ErrParsingExpiresAt.WithParams(value, name).Wrap(err)).Error("skipping instance")
+			continue
+		}
+		if now.Before(expiresAt) {
+			continue
+		}
+
+		r.Logger.WithFields(logrus.Fields{
+			"instance":   name,
+			"expires_at": value,
+		}).Info("instance expired, reaping it")
+		if err := r.reapInstance(ctx, name); err != nil {
+			return ErrReapingInstance.WithParams(name).Wrap(err)
+		}
+	}
+	return nil
+}
+
+// reapInstance removes all the resources of the instance and its sidecars
+func (r *Reaper) reapInstance(ctx context.Context, name string) error {
+	namespace := r.K8sClient.Namespace()
+	if err := r.deleteResources(ctx, namespacedResources, namespace, map[string]string{labelScope: r.Scope, labelName: name}, nil); err != nil {
+		return err
+	}
+	return r.deleteResources(ctx, namespacedResources, namespace, map[string]string{labelScope: r.Scope, labelParent: name}, nil)
+}
+
+// deleteResources removes the resources of the given kinds that match the given labels, except the ones skipped by the given function.
+// The resources are looked up in the given namespace, or cluster wide if it is empty.
+func (r *Reaper) deleteResources(
+	ctx context.Context,
+	gvrs []schema.GroupVersionResource,
+	namespace string,
+	labels map[string]string,
+	skip func(map[string]string) bool,
+) error {
+	var (
+		selector = metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: labels})
+		delOpts  = metav1.DeleteOptions{
+			GracePeriodSeconds: ptr.To[int64](0),
+			PropagationPolicy:  ptr.To(metav1.DeletePropagationBackground),
+		}
+	)
+
+	for _, gvr := range gvrs {
+		var client dynamic.ResourceInterface = r.K8sClient.DynamicClient().Resource(gvr)
+		if namespace != "" {
+			client = r.K8sClient.DynamicClient().Resource(gvr).Namespace(namespace)
+		}
+		list, err := client.List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			// the kind is not served by the cluster, e.g. traefik is not installed
+			if apierrs.IsNotFound(err) {
+				continue
+			}
+			return ErrListingResources.WithParams(gvr.Resource).Wrap(err)
+		}
+
+		for _, item := range list.Items {
+			if skip != nil && skip(item.GetLabels()) {
+				continue
+			}
+			err := client.Delete(ctx, item.GetName(), delOpts)
+			if err != nil && !apierrs.IsNotFound(err) {
+				return ErrDeletingResource.WithParams(gvr.Resource, item.GetName()).Wrap(err)
+			}
+			r.Logger.WithFields(logrus.Fields{
+				"resource": gvr.Resource,
+				"name":     item.GetName(),
+			}).Debug("deleted resource")
+		}
+	}
+	return nil
+}
+
+func (r *Reaper) validate() error {
+	if r.K8sClient == nil {
+		return ErrK8sClientNotSet
+	}
+	if r.Scope == "" {
+		return ErrScopeNotSet
+	}
+	if r.Interval <= 0 {
+		r.Interval = DefaultInterval
+	}
+	if r.Logger == nil {
+		r.Logger = log.DefaultLogger()
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package reaper
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	discfake "k8s.io/client-go/discovery/fake"
+	dynfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/celestiaorg/knuu/pkg/k8s"
+)
+
+const testScope = "reaper-test"
+
+var (
+	configMapsGVR   = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	clusterRolesGVR = schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}
+)
+
+func testLabels(name string) map[string]string {
+	return map[string]string{
+		labelManagedBy: managedByValue,
+		labelScope:     testScope,
+		labelName:      name,
+		labelType:      "BasicInstance",
+	}
+}
+
+func testReplicaSet(name, expiresAt string) *appv1.ReplicaSet {
+	rs := &appv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testScope,
+			Labels:    testLabels(name),
+		},
+	}
+	if expiresAt != "" {
+		rs.Spec.Template.Annotations = map[string]string{AnnotationExpiresAt: expiresAt}
+	}
+	return rs
+}
+
+func testJob(name, expiresAt string) *batchv1.Job {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testScope,
+			Labels:    testLabels(name),
+		},
+	}
+	job.Spec.Template.Annotations = map[string]string{AnnotationExpiresAt: expiresAt}
+	return job
+}
+
+func testConfigMap(name string, labels map[string]string) *unstructured.Unstructured {
+	cm := &unstructured.Unstructured{}
+	cm.SetAPIVersion("v1")
+	cm.SetKind("ConfigMap")
+	cm.SetName(name)
+	cm.SetNamespace(testScope)
+	cm.SetLabels(labels)
+	return cm
+}
+
+func testClusterRole(name string, labels map[string]string) *unstructured.Unstructured {
+	cr := &unstructured.Unstructured{}
+	cr.SetAPIVersion("rbac.authorization.k8s.io/v1")
+	cr.SetKind("ClusterRole")
+	cr.SetName(name)
+	cr.SetLabels(labels)
+	return cr
+}
+
+func newTestReaper(t *testing.T, objects []runtime.Object, dynObjects ...runtime.Object) *Reaper {
+	listKinds := make(map[schema.GroupVersionResource]string)
+	for _, gvr := range append(namespacedResources, clusterResources...) {
+		kind := strings.TrimSuffix(gvr.Resource, "s")
+		listKinds[gvr] = strings.ToUpper(kind[:1]) + kind[1:] + "List"
+	}
+
+	client, err := k8s.NewClientCustom(
+		context.Background(),
+		fake.NewSimpleClientset(objects...),
+		&discfake.FakeDiscovery{Fake: &k8stesting.Fake{}},
+		dynfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, dynObjects...),
+		testScope,
+		logrus.New(),
+	)
+	require.NoError(t, err)
+
+	r := &Reaper{K8sClient: client, Scope: testScope}
+	require.NoError(t, r.validate())
+	return r
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("lease not expired", func(t *testing.T) {
+		r := newTestReaper(t, nil)
+		_, err := r.K8sClient.CreateLease(ctx, LeaseName, nil, time.Hour)
+		require.NoError(t, err)
+
+		expired, err := r.Reconcile(ctx)
+		require.NoError(t, err)
+		assert.False(t, expired)
+	})
+
+	t.Run("lease expired", func(t *testing.T) {
+		r := newTestReaper(t, nil)
+		_, err := r.K8sClient.CreateLease(ctx, LeaseName, nil, 0)
+		require.NoError(t, err)
+
+		expired, err := r.Reconcile(ctx)
+		require.NoError(t, err)
+		assert.True(t, expired)
+	})
+
+	t.Run("lease missing", func(t *testing.T) {
+		r := newTestReaper(t, nil)
+
+		expired, err := r.Reconcile(ctx)
+		require.NoError(t, err)
+		assert.True(t, expired)
+	})
+
+	t.Run("expired instance is reaped", func(t *testing.T) {
+		past := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+		future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+
+		sidecarLabels := testLabels("expired-sidecar")
+		sidecarLabels[labelParent] = "expired"
+
+		r := newTestReaper(t,
+			[]runtime.Object{
+				testReplicaSet("expired", past),
+				testReplicaSet("alive", future),
+				testReplicaSet("no-ttl", ""),
+				testJob("expired-job", past),
+			},
+			testConfigMap("expired-config", testLabels("expired")),
+			testConfigMap("expired-job-config", testLabels("expired-job")),
+			testConfigMap("expired-sidecar-config", sidecarLabels),
+			testConfigMap("alive-config", testLabels("alive")),
+			testConfigMap("no-ttl-config", testLabels("no-ttl")),
+		)
+		_, err := r.K8sClient.CreateLease(ctx, LeaseName, nil, time.Hour)
+		require.NoError(t, err)
+
+		expired, err := r.Reconcile(ctx)
+		require.NoError(t, err)
+		assert.False(t, expired)
+
+		list, err := r.K8sClient.DynamicClient().Resource(configMapsGVR).Namespace(testScope).
+			List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+
+		var names []string
+		for _, item := range list.Items {
+			names = append(names, item.GetName())
+		}
+		assert.ElementsMatch(t, []string{"alive-config", "no-ttl-config"}, names)
+	})
+}
+
+func TestCleanUp(t *testing.T) {
+	ctx := context.Background()
+
+	otherLabels := testLabels("other")
+	otherLabels[labelScope] = "other-scope"
+	reaperLabels := testLabels("timeout-handler")
+	reaperLabels[labelType] = reaperType
+
+	r := newTestReaper(t,
+		[]runtime.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testScope}},
+			&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: ClusterRoleName(testScope), Labels: reaperLabels}},
+		},
+		testConfigMap("mine-config", testLabels("mine")),
+		testConfigMap("other-config", otherLabels),
+		testClusterRole("mine-role", testLabels("mine")),
+		testClusterRole("other-role", otherLabels),
+	)
+	require.NoError(t, r.CleanUp(ctx))
+
+	configMaps, err := r.K8sClient.DynamicClient().Resource(configMapsGVR).Namespace(testScope).
+		List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, configMaps.Items, 1)
+	assert.Equal(t, "other-config", configMaps.Items[0].GetName())
+
+	_, err = r.K8sClient.GetNamespace(ctx, testScope)
+	assert.True(t, apierrs.IsNotFound(err))
+	_, err = r.K8sClient.Clientset().RbacV1().ClusterRoles().Get(ctx, ClusterRoleName(testScope), metav1.GetOptions{})
+	assert.True(t, apierrs.IsNotFound(err), "the cluster role of the reaper should be removed")
+
+	// the reaper is not allowed to remove the other cluster-scoped resources
+	clusterRoleNames := func() []string {
+		clusterRoles, err := r.K8sClient.DynamicClient().Resource(clusterRolesGVR).
+			List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		var names []string
+		for _, item := range clusterRoles.Items {
+			names = append(names, item.GetName())
+		}
+		return names
+	}
+	assert.ElementsMatch(t, []string{"mine-role", "other-role"}, clusterRoleNames())
+
+	require.NoError(t, r.CleanUpClusterResources(ctx))
+	assert.Equal(t, []string{"other-role"}, clusterRoleNames())
+}
+
+func TestClusterRoleRules(t *testing.T) {
+	rules := ClusterRoleRules(testScope, "reaper-namespace")
+	for _, rule := range rules {
+		assert.NotEmpty(t, rule.ResourceNames, "rule on %v should be limited to the objects of the scope", rule.Resources)
+		assert.NotContains(t, rule.Verbs, "list")
+		assert.NotContains(t, rule.Verbs, "*")
+	}
+	assert.Contains(t, rules, rbacv1.PolicyRule{
+		APIGroups:     []string{""},
+		Resources:     []string{"namespaces"},
+		ResourceNames: []string{"reaper-namespace"},
+		Verbs:         []string{"get", "delete"},
+	})
+}
@@ -20,12 +20,29 @@ type SystemDependencies struct {
 	Scope        string
 	StartTime    string
 	instancesMap sync.Map
+
+	stateObservers   []func(transition interface{})
+	stateObserversMu sync.RWMutex
 }
 
 func (s *SystemDependencies) AddInstanceName(name string) {
 	s.instancesMap.Store(name, struct{}{})
 }
 
+// AddInstance reserves the name like AddInstanceName and registers the instance under it,
+// so that it can be looked up later. The instance is stored as is, as this package can not import its type.
+func (s *SystemDependencies) AddInstance(name string, instance interface{}) {
+	s.instancesMap.Store(name, instance)
+}
+
+// RangeInstances calls f for each reserved name and the instance registered under it, if any.
+// Iteration stops when f returns false.
+func (s *SystemDependencies) RangeInstances(f func(name string, instance interface{}) bool) {
+	s.instancesMap.Range(func(key, value interface{}) bool {
+		return f(key.(string), value)
+	})
+}
+
 func (s *SystemDependencies) HasInstanceName(name string) bool {
 	_, exists := s.instancesMap.Load(name)
 	return exists
@@ -34,3 +51,21 @@ func (s *SystemDependencies) HasInstanceName(name string) bool {
 func (s *SystemDependencies) RemoveInstanceName(name string) {
 	s.instancesMap.Delete(name)
 }
+
+// AddStateObserver registers a function that is called on each state transition of the instances using these dependencies.
+// The transition is given as is, as this package can not import its type.
+func (s *SystemDependencies) AddStateObserver(observer func(transition interface{})) {
+	s.stateObserversMu.Lock()
+	defer s.stateObserversMu.Unlock()
+	s.stateObservers = append(s.stateObservers, observer)
+}
+
+// NotifyStateObservers calls the registered state observers with the given transition
+func (s *SystemDependencies) NotifyStateObservers(transition interface{}) {
+	s.stateObserversMu.RLock()
+	observers := s.stateObservers
+	s.stateObserversMu.RUnlock()
+	for _, observer := range observers {
+		observer(transition)
+	}
+}
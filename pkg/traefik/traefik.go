@@ -31,6 +31,9 @@ const (
 	image                  = "traefik:v3.0"
 	appLabel               = "app"
 	appLabelValue          = "traefik"
+	managedByLabel         = "k8s.kubernetes.io/managed-by"
+	managedByLabelValue    = "knuu"
+	scopeLabel             = "knuu.sh/scope"
 	replicas               = 1
 	waitRetry              = 5 * time.Second
 
@@ -56,7 +59,7 @@ func (t *Traefik) Deploy(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	if err := t.K8sClient.CreateServiceAccount(ctx, serviceAccountName, nil); err != nil {
+	if err := t.K8sClient.CreateServiceAccount(ctx, serviceAccountName, t.labels()); err != nil {
 		return ErrFailedToCreateServiceAccount.Wrap(err)
 	}
 
@@ -66,7 +69,7 @@ func (t *Traefik) Deploy(ctx context.Context) error {
 	}
 
 	// Define and create a ClusterRole for Traefik
-	err = t.K8sClient.CreateClusterRole(ctx, clusterRoleName, nil, []rbacv1.PolicyRule{
+	err = t.K8sClient.CreateClusterRole(ctx, clusterRoleName, t.labels(), []rbacv1.PolicyRule{
 		{
 			APIGroups: []string{""}, // Core group
 			Resources: []string{"pods", "endpoints", "secrets", "services"},
@@ -92,7 +95,7 @@ func (t *Traefik) Deploy(ctx context.Context) error {
 		return ErrTraefikRoleCreationFailed.Wrap(err)
 	}
 
-	if err := t.K8sClient.CreateClusterRoleBinding(ctx, clusterRoleName, nil, clusterRoleName, serviceAccountName); err != nil {
+	if err := t.K8sClient.CreateClusterRoleBinding(ctx, clusterRoleName, t.labels(), clusterRoleName, serviceAccountName); err != nil {
 		return ErrTraefikRoleBindingCreationFailed.Wrap(err)
 	}
 
@@ -118,7 +121,7 @@ func (t *Traefik) Deploy(ctx context.Context) error {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      deploymentName,
 			Namespace: t.K8sClient.Namespace(),
-			Labels:    map[string]string{appLabel: appLabelValue},
+			Labels:    t.labels(),
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: ptr.To[int32](replicas),
@@ -127,7 +130,7 @@ func (t *Traefik) Deploy(ctx context.Context) error {
 			},
 			Template: v1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{appLabel: appLabelValue},
+					Labels: t.labels(),
 				},
 				Spec: v1.PodSpec{
 					ServiceAccountName: serviceAccountName,
@@ -230,7 +233,7 @@ func (t *Traefik) createService(ctx context.Context) error {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      traefikServiceName,
 			Namespace: t.K8sClient.Namespace(),
-			Labels:    map[string]string{appLabel: appLabelValue},
+			Labels:    t.labels(),
 		},
 		Spec: v1.ServiceSpec{
 			Selector: map[string]string{appLabel: appLabelValue},
@@ -277,6 +280,8 @@ func (t *Traefik) createMiddleware(ctx context.Context, serviceName, middlewareN
 		},
 	}
 
+	middleware.SetLabels(t.labels())
+
 	middlewareResource := schema.GroupVersionResource{
 		Group:    "traefik.io",
 		Version:  "v1alpha1",
@@ -339,6 +344,8 @@ func (t *Traefik) createIngressRoute(
 		},
 	}
 
+	ingressRoute.SetLabels(t.labels())
+
 	_, err = t.K8sClient.DynamicClient().Resource(ingressRouteGVR).Namespace(t.K8sClient.Namespace()).
 		Create(ctx, ingressRoute, metav1.CreateOptions{})
 	if err != nil {
@@ -348,6 +355,17 @@ func (t *Traefik) createIngressRoute(
 	return nil
 }
 
+// labels returns the labels of the resources created for traefik.
+// The scope is the namespace of the client, so that the resources are removed with the scope,
+// including the cluster-scoped ones that are not removed with the namespace.
+func (t *Traefik) labels() map[string]string {
+	return map[string]string{
+		appLabel:       appLabelValue,
+		managedByLabel: managedByLabelValue,
+		scopeLabel:     t.K8sClient.Namespace(),
+	}
+}
+
 // IsTraefikAPIAvailable checks if the Traefik API is available in the cluster.
 func (t *Traefik) IsTraefikAPIAvailable(ctx context.Context) bool {
 	apiResourceList, err := t.K8sClient.Clientset().Discovery().ServerResourcesForGroupVersion(traefikAPIGroupVersion)